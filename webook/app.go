@@ -0,0 +1,18 @@
+package main
+
+import (
+	"dream/webook/internal/events"
+	"dream/webook/internal/job"
+	"dream/webook/pkg/cronjob"
+
+	"github.com/gin-gonic/gin"
+)
+
+// App 应用里面需要启动的东西
+type App struct {
+	server    *gin.Engine
+	scheduler *cronjob.Scheduler
+	// jobScheduler 数据库里的任务
+	jobScheduler *job.Scheduler
+	consumers    []events.Consumer
+}
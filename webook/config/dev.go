@@ -11,4 +11,11 @@ var Config = config{
 	Redis: RedisConfig{
 		Addr: "localhost:6379",
 	},
+	Admin: AdminConfig{
+		// 本地第一个注册的用户
+		Uids: []int64{1},
+	},
+	Pay: PayConfig{
+		Memory: true,
+	},
 }
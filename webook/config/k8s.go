@@ -11,4 +11,7 @@ var Config = config{
 	Redis: RedisConfig{
 		Addr: "webook-redis:11479",
 	},
+	Kafka: KafkaConfig{
+		Addrs: []string{"webook-kafka:9092"},
+	},
 }
@@ -3,6 +3,9 @@ package config
 type config struct {
 	DB    DBConfig
 	Redis RedisConfig
+	Admin AdminConfig
+	Kafka KafkaConfig
+	Pay   PayConfig
 }
 
 type DBConfig struct {
@@ -12,3 +15,18 @@ type DBConfig struct {
 type RedisConfig struct {
 	Addr string
 }
+
+type AdminConfig struct {
+	// 管理员的用户 id
+	Uids []int64
+}
+
+type PayConfig struct {
+	// Memory 用内存模拟支付，回调没有签名谁都能伪造，只能本地开发的时候打开
+	Memory bool
+}
+
+type KafkaConfig struct {
+	// Addrs 没有配置的话用内存里的消息队列
+	Addrs []string
+}
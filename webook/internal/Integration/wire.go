@@ -3,6 +3,7 @@
 package Integration
 
 import (
+	"dream/webook/internal/events/article"
 	"dream/webook/internal/repository"
 	"dream/webook/internal/repository/cache"
 	"dream/webook/internal/repository/dao"
@@ -19,24 +20,86 @@ func initWebServer() *gin.Engine {
 	wire.Build(
 		ioc.InitDB, ioc.InitRedis,
 		dao.NewUserDAO,
+		dao.NewArticleDAO,
+		dao.NewArticleRevisionDAO,
+		dao.NewInteractiveDAO,
+		dao.NewCollectionDAO,
+		dao.NewJobDAO,
+		dao.NewRewardDAO,
+		dao.NewReconciliationDAO,
+		dao.NewAccountDAO,
+		dao.NewCommentDAO,
+		dao.NewFollowDAO,
+		dao.NewFeedDAO,
+		dao.NewDeadLetterDAO,
+		dao.NewAsyncSMSDAO,
 
 		cache.NewUserCache,
 		cache.NewCodeCache,
+		cache.NewInteractiveCache,
+		cache.NewRankingCache,
+		cache.NewRankingLocalCache,
+		cache.NewRewardCache,
+		cache.NewFollowCache,
 
 		repository.NewCodeRepository,
 		repository.NewUserRepository,
+		repository.NewArticleRepository,
+		repository.NewArticleRevisionRepository,
+		repository.NewInteractiveRepository,
+		repository.NewCollectionRepository,
+		repository.NewRankingRepository,
+		repository.NewJobRepository,
+		repository.NewRewardRepository,
+		repository.NewReconciliationRepository,
+		repository.NewAccountRepository,
+		repository.NewCommentRepository,
+		repository.NewFollowRepository,
+		repository.NewFeedRepository,
+		repository.NewDeadLetterRepository,
+		repository.NewAsyncSMSRepository,
 
 		service.NewCodeService,
 		service.NewUserService,
+		service.NewArticleService,
+		service.NewArticleRevisionService,
+		service.NewInteractiveService,
+		service.NewCollectionService,
+		service.NewRankingService,
+		service.NewJobService,
+		service.NewRewardService,
+		service.NewReconciliationService,
+		service.NewAccountService,
+		service.NewCommentService,
+		service.NewFollowService,
+		service.NewFeedService,
+		service.NewDeadLetterService,
 
+		ioc.InitAsyncSMSService,
 		ioc.InitSMSService,
 		ioc.InitWechatService,
+		ioc.InitPaymentService,
 
 		web.NewUserHandler,
 		web.NewWeChatOAuth2Handler,
+		web.NewArticleHandler,
+		web.NewCollectionHandler,
+		web.NewJobHandler,
+		web.NewRewardHandler,
+		web.NewPaymentHandler,
+		web.NewReconciliationHandler,
+		web.NewAccountHandler,
+		web.NewCommentHandler,
+		web.NewFollowHandler,
+		web.NewFeedHandler,
+		web.NewDeadLetterHandler,
 
 		ijwt.NewRedisJWTHandler,
 
+		ioc.InitEventBroker,
+		ioc.InitEventProducer,
+		article.NewProducer,
+
 		ioc.InitMiddlewares,
 		ioc.InitGin,
 	)
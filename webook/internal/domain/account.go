@@ -0,0 +1,60 @@
+package domain
+
+import "time"
+
+// Account 账户，余额永远等于这个账户所有流水的金额之和
+type Account struct {
+	Id   int64
+	Uid  int64
+	Type AccountType
+	// Balance 余额，单位是分
+	Balance  int64
+	Currency string
+	Ctime    time.Time
+	Utime    time.Time
+}
+
+type AccountType uint8
+
+const (
+	AccountTypeUnknown AccountType = iota
+	// AccountTypePersonal 个人账户，作者的收入
+	AccountTypePersonal
+	// AccountTypePlatform 平台账户，平台的抽成
+	AccountTypePlatform
+	// AccountTypeClearing 支付渠道进来的钱先记在这里，余额是负的，和其他账户加起来刚好是 0
+	AccountTypeClearing
+)
+
+func (t AccountType) ToUint8() uint8 {
+	return uint8(t)
+}
+
+func (t AccountType) String() string {
+	switch t {
+	case AccountTypePersonal:
+		return "personal"
+	case AccountTypePlatform:
+		return "platform"
+	case AccountTypeClearing:
+		return "clearing"
+	default:
+		return "unknown"
+	}
+}
+
+// Credit 一笔入账，Biz 和 BizId 一起唯一确定一笔支付，重复入账会被忽略
+type Credit struct {
+	Biz   string
+	BizId int64
+	Items []CreditItem
+}
+
+// CreditItem 入账到某个账户的钱
+type CreditItem struct {
+	// Uid 平台账户和清算账户是 0
+	Uid      int64
+	Type     AccountType
+	Amt      int64
+	Currency string
+}
@@ -0,0 +1,68 @@
+package domain
+
+import "time"
+
+// Article 帖子领域对象
+type Article struct {
+	Id      int64
+	Title   string
+	Content string
+	// 作者，只需要 id 和 名字
+	Author Author
+	Status ArticleStatus
+	Ctime  time.Time
+	Utime  time.Time
+}
+
+// Abstract 取部分作为摘要
+func (a Article) Abstract() string {
+	cs := []rune(a.Content)
+	if len(cs) < 100 {
+		return a.Content
+	}
+	return string(cs[:100])
+}
+
+type ArticleStatus uint8
+
+const (
+	// ArticleStatusUnknown 为了避免零值问题
+	ArticleStatusUnknown ArticleStatus = iota
+	// ArticleStatusUnpublished 未发表
+	ArticleStatusUnpublished
+	// ArticleStatusPublished 已发表
+	ArticleStatusPublished
+	// ArticleStatusPrivate 仅自己可见，也就是撤回之后的状态
+	ArticleStatusPrivate
+)
+
+func (s ArticleStatus) ToUint8() uint8 {
+	return uint8(s)
+}
+
+func (s ArticleStatus) Valid() bool {
+	return s.ToUint8() > 0
+}
+
+func (s ArticleStatus) NonPublished() bool {
+	return s != ArticleStatusPublished
+}
+
+func (s ArticleStatus) String() string {
+	switch s {
+	case ArticleStatusUnpublished:
+		return "unpublished"
+	case ArticleStatusPublished:
+		return "published"
+	case ArticleStatusPrivate:
+		return "private"
+	default:
+		return "unknown"
+	}
+}
+
+// Author 在帖子这个领域内，作者是一个值对象
+type Author struct {
+	Id   int64
+	Name string
+}
@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// ArticleRevision 帖子的一次保存记录，创建之后就不会再修改
+type ArticleRevision struct {
+	Id        int64
+	ArticleId int64
+	Author    Author
+	Title     string
+	Content   string
+	// Hash 内容的 sha256，用来快速判断两个版本内容是否一样
+	Hash  string
+	Ctime time.Time
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// AsyncSMS 服务商出问题的时候先存起来，之后异步发送的短信
+type AsyncSMS struct {
+	Id   int64
+	Tpl  string
+	Args []string
+	// NamedArgs 调用的是 SendNamed 的时候用这个，Args 是空的
+	NamedArgs []SMSNamedArg
+	Numbers   []string
+	// Deadline 过了这个时间就不用发了，比如验证码已经过期了
+	Deadline time.Time
+	// RetryCnt 已经重试了几次
+	RetryCnt int
+	// RetryMax 最多重试几次，超过了就放弃
+	RetryMax int
+}
+
+type SMSNamedArg struct {
+	Name string
+	Val  string
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// Collection 收藏夹
+type Collection struct {
+	Id   int64
+	Uid  int64
+	Name string
+	// Private 私密收藏夹别人看不到
+	Private bool
+	// Default 默认收藏夹，收藏的时候没有指定收藏夹就放这里，不能删除
+	Default bool
+	Ctime   time.Time
+	Utime   time.Time
+}
+
+// CollectionItem 收藏夹里面的一条记录
+type CollectionItem struct {
+	Cid         int64
+	Article     Article
+	Interactive Interactive
+	// 收藏时间
+	Ctime time.Time
+}
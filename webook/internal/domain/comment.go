@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// Comment 评论，RootId 和 ParentId 都是 0 的就是直接评论帖子的根评论
+type Comment struct {
+	Id  int64
+	Uid int64
+	// Biz 和 BizId 是被评论的资源
+	Biz   string
+	BizId int64
+	// Content 删除之后是空的
+	Content string
+	// RootId 回复属于哪一条根评论
+	RootId int64
+	// ParentId 直接回复的是哪一条评论
+	ParentId int64
+	// Deleted 软删除，回复还能看到，但是内容没了
+	Deleted bool
+	Ctime   time.Time
+	Utime   time.Time
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// DeadLetter 消费者重试完了还是处理失败的消息
+type DeadLetter struct {
+	Id    int64
+	Group string
+	Topic string
+	Key   []byte
+	Value []byte
+	Err   string
+	// Replayed 已经重放过了，重放之后又失败的话会是一条新的死信
+	Replayed bool
+	Ctime    time.Time
+	Utime    time.Time
+}
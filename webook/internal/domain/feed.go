@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// FeedEvent 时间线里的一条，目前只有关注的人发表了帖子
+type FeedEvent struct {
+	// Uid 推模型里是收件人，拉模型里是作者自己
+	Uid     int64
+	Article Article
+	// Ctime 发表的时间，也是游标
+	Ctime time.Time
+}
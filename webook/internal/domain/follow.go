@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+// FollowRelation Follower 关注了 Followee
+type FollowRelation struct {
+	Follower int64
+	Followee int64
+	Ctime    time.Time
+}
+
+// FollowStatics 一个用户的关注数和粉丝数
+type FollowStatics struct {
+	// Followers 粉丝数
+	Followers int64
+	// Followees 关注了多少人
+	Followees int64
+}
+
+// FollowUser 关注列表和粉丝列表里面的一个人
+type FollowUser struct {
+	Id       int64
+	Nickname string
+	// Mutual 互相关注
+	Mutual bool
+	// Ctime 关注的时间
+	Ctime time.Time
+}
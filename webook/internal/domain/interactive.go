@@ -0,0 +1,14 @@
+package domain
+
+// Interactive 阅读、点赞、收藏这些交互数据
+// Biz + BizId 确定一个资源，比如 article + 帖子 id
+type Interactive struct {
+	Biz        string
+	BizId      int64
+	ReadCnt    int64
+	LikeCnt    int64
+	CollectCnt int64
+	// 下面两个是当前用户视角的
+	Liked     bool
+	Collected bool
+}
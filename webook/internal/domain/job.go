@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Job 存在数据库里面的任务，多个实例通过抢占的方式执行
+type Job struct {
+	Id   int64
+	Name string
+	// Executor 用哪个执行器执行
+	Executor string
+	// Cfg 执行器需要的配置，执行器自己解析
+	Cfg        string
+	Expression string
+	NextTime   time.Time
+	Status     JobStatus
+	Version    int64
+	// Owner 抢占到任务的实例
+	Owner string
+	Utime time.Time
+}
+
+// Next 根据 cron 表达式计算下一次执行时间，表达式不对返回零值
+func (j Job) Next(t time.Time) time.Time {
+	s, err := cron.ParseStandard(j.Expression)
+	if err != nil {
+		return time.Time{}
+	}
+	return s.Next(t)
+}
+
+type JobStatus uint8
+
+const (
+	JobStatusUnknown JobStatus = iota
+	// JobStatusWaiting 等待被抢占
+	JobStatusWaiting
+	// JobStatusRunning 已经被某个实例抢占了
+	JobStatusRunning
+	// JobStatusPaused 暂停，不会被抢占
+	JobStatusPaused
+)
+
+func (s JobStatus) ToUint8() uint8 {
+	return uint8(s)
+}
+
+func (s JobStatus) String() string {
+	switch s {
+	case JobStatusWaiting:
+		return "waiting"
+	case JobStatusRunning:
+		return "running"
+	case JobStatusPaused:
+		return "paused"
+	default:
+		return "unknown"
+	}
+}
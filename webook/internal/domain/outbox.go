@@ -0,0 +1,11 @@
+package domain
+
+// OutboxMessage 发件箱里等待转发到消息队列的消息
+type OutboxMessage struct {
+	Id    int64
+	Topic string
+	Key   string
+	Value []byte
+	// Retries 已经失败了几次
+	Retries int
+}
@@ -0,0 +1,33 @@
+package domain
+
+// Payment 一笔支付，和具体的支付平台无关
+type Payment struct {
+	Amt Amount
+	// BizTradeNo 业务方的单号，比如打赏订单就是 reward-1
+	BizTradeNo  string
+	Description string
+	Status      PaymentStatus
+	// TxnID 支付平台那边的交易号
+	TxnID string
+}
+
+// Amount 金额，Total 的单位是分
+type Amount struct {
+	Currency string
+	Total    int64
+}
+
+type PaymentStatus uint8
+
+const (
+	PaymentStatusUnknown PaymentStatus = iota
+	// PaymentStatusInit 已经下单，还没有支付
+	PaymentStatusInit
+	PaymentStatusSuccess
+	PaymentStatusFailed
+	PaymentStatusRefund
+)
+
+func (s PaymentStatus) ToUint8() uint8 {
+	return uint8(s)
+}
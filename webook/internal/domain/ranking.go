@@ -0,0 +1,8 @@
+package domain
+
+// HotArticle 热榜上的一篇帖子
+type HotArticle struct {
+	Article Article
+	// Score 热度，只用来排序，没有具体含义
+	Score float64
+}
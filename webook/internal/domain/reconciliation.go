@@ -0,0 +1,57 @@
+package domain
+
+import "time"
+
+// Reconciliation 一次对账的结果
+type Reconciliation struct {
+	Id         int64
+	Biz        string
+	BizId      int64
+	BizTradeNo string
+	// Amt 订单金额，单位是分
+	Amt    int64
+	Result ReconciliationResult
+	// Err 对账失败的原因
+	Err   string
+	Ctime time.Time
+}
+
+// ReconciliationSummary 按照对账结果汇总
+type ReconciliationSummary struct {
+	Result ReconciliationResult
+	Cnt    int64
+	Amt    int64
+}
+
+type ReconciliationResult uint8
+
+const (
+	ReconciliationResultUnknown ReconciliationResult = iota
+	// ReconciliationResultPaid 其实已经支付了，回调丢了
+	ReconciliationResultPaid
+	// ReconciliationResultClosed 一直没有支付，关闭了订单
+	ReconciliationResultClosed
+	// ReconciliationResultFailed 支付失败，或者支付平台根本没有这个订单
+	ReconciliationResultFailed
+	// ReconciliationResultError 对账本身出错了，下一轮再试
+	ReconciliationResultError
+)
+
+func (r ReconciliationResult) ToUint8() uint8 {
+	return uint8(r)
+}
+
+func (r ReconciliationResult) String() string {
+	switch r {
+	case ReconciliationResultPaid:
+		return "paid"
+	case ReconciliationResultClosed:
+		return "closed"
+	case ReconciliationResultFailed:
+		return "failed"
+	case ReconciliationResultError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
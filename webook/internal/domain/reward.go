@@ -0,0 +1,54 @@
+package domain
+
+// Reward 打赏
+type Reward struct {
+	Id int64
+	// Uid 打赏的人
+	Uid    int64
+	Target Target
+	// Amt 金额，单位是分
+	Amt    int64
+	Status RewardStatus
+}
+
+// Target 打赏的对象
+type Target struct {
+	Biz     string
+	BizId   int64
+	BizName string
+	// Uid 收钱的人
+	Uid int64
+}
+
+// CodeURL 用户扫码支付的链接
+type CodeURL struct {
+	Rid int64
+	URL string
+}
+
+type RewardStatus uint8
+
+const (
+	RewardStatusUnknown RewardStatus = iota
+	RewardStatusInit
+	RewardStatusPayed
+	RewardStatusFailed
+)
+
+func (s RewardStatus) ToUint8() uint8 {
+	return uint8(s)
+}
+
+// String 前端直接用这个字符串判断有没有支付成功
+func (s RewardStatus) String() string {
+	switch s {
+	case RewardStatusInit:
+		return "RewardStatusInit"
+	case RewardStatusPayed:
+		return "RewardStatusPayed"
+	case RewardStatusFailed:
+		return "RewardStatusFailed"
+	default:
+		return "RewardStatusUnknown"
+	}
+}
@@ -0,0 +1,73 @@
+package article
+
+import (
+	"context"
+	"dream/webook/internal/service"
+	"dream/webook/pkg/events"
+	"encoding/json"
+	"log"
+	"time"
+)
+
+// ReadEventConsumer 消费阅读事件，增加阅读数并且更新热榜
+type ReadEventConsumer struct {
+	consumer events.Consumer
+	svc      service.InteractiveService
+	rankSvc  service.RankingService
+	// cfg 阅读事件量很大，攒一批再写数据库
+	cfg events.BatchConfig
+}
+
+func NewReadEventConsumer(consumer events.Consumer, svc service.InteractiveService,
+	rankSvc service.RankingService) *ReadEventConsumer {
+	return &ReadEventConsumer{
+		consumer: consumer,
+		svc:      svc,
+		rankSvc:  rankSvc,
+		cfg: events.BatchConfig{
+			Size:     100,
+			Interval: time.Second,
+		},
+	}
+}
+
+func (c *ReadEventConsumer) Start(ctx context.Context) error {
+	go func() {
+		err := c.consumer.ConsumeBatch(ctx, []string{TopicReadEvent}, c.cfg, c.Consume)
+		log.Println("阅读事件消费者退出", err)
+	}()
+	return nil
+}
+
+func (c *ReadEventConsumer) Consume(ctx context.Context, msgs []*events.Message) error {
+	aids := make([]int64, 0, len(msgs))
+	for _, msg := range msgs {
+		var evt ReadEvent
+		if err := json.Unmarshal(msg.Value, &evt); err != nil {
+			// 格式不对的消息重试也没用，直接丢掉
+			log.Println("阅读事件格式不对", string(msg.Value), err)
+			continue
+		}
+		aids = append(aids, evt.Aid)
+	}
+	if len(aids) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Second*3)
+	defer cancel()
+	if err := c.svc.BatchIncrReadCnt(ctx, "article", aids); err != nil {
+		return err
+	}
+	// 热榜失败了等定时任务全量重算
+	refreshed := make(map[int64]struct{}, len(aids))
+	for _, aid := range aids {
+		if _, ok := refreshed[aid]; ok {
+			continue
+		}
+		refreshed[aid] = struct{}{}
+		if err := c.rankSvc.Refresh(ctx, aid); err != nil {
+			log.Println("更新热榜失败", aid, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package article
+
+import (
+	"context"
+	"dream/webook/internal/service"
+	svcmocks "dream/webook/internal/service/mocks"
+	"dream/webook/pkg/events"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestReadEventConsumer_Consume(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (service.InteractiveService, service.RankingService)
+		msgs []*events.Message
+
+		wantErr error
+	}{
+		{
+			name: "一批一起更新阅读数，热榜去重",
+			mock: func(ctrl *gomock.Controller) (service.InteractiveService, service.RankingService) {
+				svc := svcmocks.NewMockInteractiveService(ctrl)
+				rankSvc := svcmocks.NewMockRankingService(ctrl)
+				svc.EXPECT().BatchIncrReadCnt(gomock.Any(), "article", []int64{1, 2, 1}).Return(nil)
+				rankSvc.EXPECT().Refresh(gomock.Any(), int64(1)).Return(nil)
+				// 热榜失败不影响提交
+				rankSvc.EXPECT().Refresh(gomock.Any(), int64(2)).Return(errors.New("mock redis error"))
+				return svc, rankSvc
+			},
+			msgs: []*events.Message{
+				{Value: []byte(`{"aid":1,"uid":11}`)},
+				{Value: []byte(`{"aid":2,"uid":11}`)},
+				{Value: []byte(`not json`)},
+				{Value: []byte(`{"aid":1,"uid":12}`)},
+			},
+		},
+		{
+			name: "格式都不对，直接跳过",
+			mock: func(ctrl *gomock.Controller) (service.InteractiveService, service.RankingService) {
+				return svcmocks.NewMockInteractiveService(ctrl), svcmocks.NewMockRankingService(ctrl)
+			},
+			msgs: []*events.Message{
+				{Value: []byte(`not json`)},
+			},
+		},
+		{
+			name: "更新阅读数失败，整批重试",
+			mock: func(ctrl *gomock.Controller) (service.InteractiveService, service.RankingService) {
+				svc := svcmocks.NewMockInteractiveService(ctrl)
+				svc.EXPECT().BatchIncrReadCnt(gomock.Any(), "article", []int64{1}).
+					Return(errors.New("mock db error"))
+				return svc, svcmocks.NewMockRankingService(ctrl)
+			},
+			msgs: []*events.Message{
+				{Value: []byte(`{"aid":1,"uid":11}`)},
+			},
+			wantErr: errors.New("mock db error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc, rankSvc := tc.mock(ctrl)
+			c := NewReadEventConsumer(nil, svc, rankSvc)
+			err := c.Consume(context.Background(), tc.msgs)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package article
+
+import (
+	"context"
+	"dream/webook/pkg/events"
+	"encoding/json"
+	"strconv"
+)
+
+const TopicReadEvent = "article_read"
+
+// ReadEvent 读者看了一次帖子
+type ReadEvent struct {
+	Aid int64 `json:"aid"`
+	Uid int64 `json:"uid"`
+}
+
+type Producer interface {
+	ProduceReadEvent(ctx context.Context, evt ReadEvent) error
+}
+
+type EventProducer struct {
+	producer events.Producer
+}
+
+func NewProducer(producer events.Producer) Producer {
+	return &EventProducer{
+		producer: producer,
+	}
+}
+
+func (p *EventProducer) ProduceReadEvent(ctx context.Context, evt ReadEvent) error {
+	val, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	// 同一篇帖子的阅读事件进同一个分区
+	return p.producer.Produce(ctx, &events.Message{
+		Topic: TopicReadEvent,
+		Key:   []byte(strconv.FormatInt(evt.Aid, 10)),
+		Value: val,
+	})
+}
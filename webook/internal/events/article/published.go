@@ -0,0 +1,55 @@
+package article
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	ievents "dream/webook/internal/events"
+	"dream/webook/internal/service"
+	"dream/webook/pkg/events"
+	"encoding/json"
+	"log"
+	"time"
+)
+
+// PublishedEventConsumer 消费帖子发表事件，写粉丝的时间线
+type PublishedEventConsumer struct {
+	consumer events.Consumer
+	feedSvc  service.FeedService
+}
+
+func NewPublishedEventConsumer(consumer events.Consumer, feedSvc service.FeedService) *PublishedEventConsumer {
+	return &PublishedEventConsumer{
+		consumer: consumer,
+		feedSvc:  feedSvc,
+	}
+}
+
+func (c *PublishedEventConsumer) Start(ctx context.Context) error {
+	go func() {
+		err := c.consumer.Consume(ctx, []string{ievents.TopicArticlePublished}, c.Consume)
+		log.Println("帖子发表事件消费者退出", err)
+	}()
+	return nil
+}
+
+func (c *PublishedEventConsumer) Consume(ctx context.Context, msg *events.Message) error {
+	var evt ievents.ArticlePublishedEvent
+	if err := json.Unmarshal(msg.Value, &evt); err != nil {
+		// 格式不对的消息重试也没用，直接丢掉
+		log.Println("帖子发表事件格式不对", string(msg.Value), err)
+		return nil
+	}
+	// 粉丝多的时候要分批写收件箱，给长一点的时间
+	ctx, cancel := context.WithTimeout(ctx, time.Second*10)
+	defer cancel()
+	art := domain.Article{
+		Id:     evt.Aid,
+		Title:  evt.Title,
+		Author: domain.Author{Id: evt.Uid},
+	}
+	// 老版本的消息没有发表时间，留空让 feed 用当前时间
+	if evt.Ctime > 0 {
+		art.Ctime = time.UnixMilli(evt.Ctime)
+	}
+	return c.feedSvc.PublishArticle(ctx, art)
+}
@@ -0,0 +1,80 @@
+package article
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/service"
+	svcmocks "dream/webook/internal/service/mocks"
+	"dream/webook/pkg/events"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPublishedEventConsumer_Consume(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) service.FeedService
+		msg  *events.Message
+
+		wantErr error
+	}{
+		{
+			name: "写时间线",
+			mock: func(ctrl *gomock.Controller) service.FeedService {
+				svc := svcmocks.NewMockFeedService(ctrl)
+				svc.EXPECT().PublishArticle(gomock.Any(), domain.Article{
+					Id:     1,
+					Title:  "标题",
+					Author: domain.Author{Id: 123},
+					Ctime:  time.UnixMilli(9000),
+				}).Return(nil)
+				return svc
+			},
+			msg: &events.Message{Value: []byte(`{"aid":1,"uid":123,"title":"标题","ctime":9000}`)},
+		},
+		{
+			name: "老消息没有发表时间",
+			mock: func(ctrl *gomock.Controller) service.FeedService {
+				svc := svcmocks.NewMockFeedService(ctrl)
+				svc.EXPECT().PublishArticle(gomock.Any(), domain.Article{
+					Id:     1,
+					Title:  "标题",
+					Author: domain.Author{Id: 123},
+				}).Return(nil)
+				return svc
+			},
+			msg: &events.Message{Value: []byte(`{"aid":1,"uid":123,"title":"标题"}`)},
+		},
+		{
+			name: "格式不对，直接跳过",
+			mock: func(ctrl *gomock.Controller) service.FeedService {
+				return svcmocks.NewMockFeedService(ctrl)
+			},
+			msg: &events.Message{Value: []byte(`not json`)},
+		},
+		{
+			name: "写时间线失败，重试",
+			mock: func(ctrl *gomock.Controller) service.FeedService {
+				svc := svcmocks.NewMockFeedService(ctrl)
+				svc.EXPECT().PublishArticle(gomock.Any(), gomock.Any()).
+					Return(errors.New("mock db error"))
+				return svc
+			},
+			msg:     &events.Message{Value: []byte(`{"aid":1,"uid":123,"title":"标题"}`)},
+			wantErr: errors.New("mock db error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			c := NewPublishedEventConsumer(nil, tc.mock(ctrl))
+			err := c.Consume(context.Background(), tc.msg)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
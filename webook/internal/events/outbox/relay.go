@@ -0,0 +1,110 @@
+package outbox
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository"
+	"dream/webook/pkg/events"
+	"log"
+	"time"
+)
+
+const (
+	batchSize = 100
+	// interval 发件箱空了之后隔多久再扫一次
+	interval         = time.Second
+	minRetryInterval = time.Second
+	maxRetryInterval = time.Minute * 5
+)
+
+// Relay 把发件箱里的消息转发到消息队列，发送成功再从发件箱删掉。
+// 删除失败或者多个实例同时转发会重复发送，所以消费者要保证幂等
+type Relay struct {
+	repo     repository.OutboxRepository
+	producer events.Producer
+}
+
+func NewRelay(repo repository.OutboxRepository, producer events.Producer) *Relay {
+	return &Relay{
+		repo:     repo,
+		producer: producer,
+	}
+}
+
+func (r *Relay) Start(ctx context.Context) error {
+	go func() {
+		r.loop(ctx)
+		log.Println("发件箱转发退出")
+	}()
+	return nil
+}
+
+func (r *Relay) loop(ctx context.Context) {
+	for {
+		cnt, err := r.relayOnce(ctx)
+		if err != nil {
+			log.Println("转发发件箱失败", err)
+		}
+		// 这一批是满的，说明后面可能还有，直接接着转发
+		if err == nil && cnt == batchSize {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// relayOnce 转发一批，返回这一批的数量
+func (r *Relay) relayOnce(ctx context.Context) (int, error) {
+	dbCtx, cancel := context.WithTimeout(ctx, time.Second)
+	msgs, err := r.repo.FindPending(dbCtx, batchSize)
+	cancel()
+	if err != nil {
+		return 0, err
+	}
+	for _, msg := range msgs {
+		if ctx.Err() != nil {
+			return len(msgs), ctx.Err()
+		}
+		r.relay(ctx, msg)
+	}
+	return len(msgs), nil
+}
+
+func (r *Relay) relay(ctx context.Context, msg domain.OutboxMessage) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*3)
+	defer cancel()
+	err := r.producer.Produce(ctx, &events.Message{
+		Topic: msg.Topic,
+		Key:   []byte(msg.Key),
+		Value: msg.Value,
+	})
+	if err == nil {
+		err = r.repo.MarkSent(ctx, msg.Id)
+		if err != nil {
+			// 下一轮会再发一次
+			log.Println("标记发件箱消息失败", msg.Id, err)
+		}
+		return
+	}
+	log.Println("发送发件箱消息失败", msg.Id, msg.Topic, err)
+	err = r.repo.MarkRetry(ctx, msg.Id, time.Now().Add(retryInterval(msg.Retries)))
+	if err != nil {
+		log.Println("标记发件箱消息重试失败", msg.Id, err)
+	}
+}
+
+// retryInterval 指数退避，retries 是之前已经失败的次数
+func retryInterval(retries int) time.Duration {
+	d := minRetryInterval
+	for i := 0; i < retries; i++ {
+		d *= 2
+		if d >= maxRetryInterval {
+			return maxRetryInterval
+		}
+	}
+	return d
+}
@@ -0,0 +1,129 @@
+package outbox
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository"
+	repomocks "dream/webook/internal/repository/mocks"
+	"dream/webook/pkg/events"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type stubProducer struct {
+	err  error
+	msgs []*events.Message
+}
+
+func (p *stubProducer) Produce(ctx context.Context, msg *events.Message) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.msgs = append(p.msgs, msg)
+	return nil
+}
+
+func (p *stubProducer) Close() error {
+	return nil
+}
+
+func TestRelay_relayOnce(t *testing.T) {
+	msgs := []domain.OutboxMessage{
+		{Id: 1, Topic: "article_published", Key: "11", Value: []byte(`{"aid":11}`)},
+		{Id: 2, Topic: "article_published", Key: "22", Value: []byte(`{"aid":22}`), Retries: 2},
+	}
+	testCases := []struct {
+		name     string
+		mock     func(ctrl *gomock.Controller) repository.OutboxRepository
+		producer *stubProducer
+
+		wantCnt  int
+		wantErr  error
+		wantMsgs []*events.Message
+	}{
+		{
+			name: "全部发送成功",
+			mock: func(ctrl *gomock.Controller) repository.OutboxRepository {
+				repo := repomocks.NewMockOutboxRepository(ctrl)
+				repo.EXPECT().FindPending(gomock.Any(), batchSize).Return(msgs, nil)
+				repo.EXPECT().MarkSent(gomock.Any(), int64(1)).Return(nil)
+				repo.EXPECT().MarkSent(gomock.Any(), int64(2)).Return(nil)
+				return repo
+			},
+			producer: &stubProducer{},
+			wantCnt:  2,
+			wantMsgs: []*events.Message{
+				{Topic: "article_published", Key: []byte("11"), Value: []byte(`{"aid":11}`)},
+				{Topic: "article_published", Key: []byte("22"), Value: []byte(`{"aid":22}`)},
+			},
+		},
+		{
+			name: "发送失败，按照失败次数退避",
+			mock: func(ctrl *gomock.Controller) repository.OutboxRepository {
+				repo := repomocks.NewMockOutboxRepository(ctrl)
+				repo.EXPECT().FindPending(gomock.Any(), batchSize).Return(msgs, nil)
+				now := time.Now()
+				repo.EXPECT().MarkRetry(gomock.Any(), int64(1), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, id int64, next time.Time) error {
+						assert.WithinDuration(t, now.Add(time.Second), next, time.Second)
+						return nil
+					})
+				repo.EXPECT().MarkRetry(gomock.Any(), int64(2), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, id int64, next time.Time) error {
+						assert.WithinDuration(t, now.Add(time.Second*4), next, time.Second)
+						return nil
+					})
+				return repo
+			},
+			producer: &stubProducer{err: errors.New("mock produce error")},
+			wantCnt:  2,
+		},
+		{
+			name: "标记失败不影响后面的消息",
+			mock: func(ctrl *gomock.Controller) repository.OutboxRepository {
+				repo := repomocks.NewMockOutboxRepository(ctrl)
+				repo.EXPECT().FindPending(gomock.Any(), batchSize).Return(msgs, nil)
+				repo.EXPECT().MarkSent(gomock.Any(), int64(1)).Return(errors.New("mock db error"))
+				repo.EXPECT().MarkSent(gomock.Any(), int64(2)).Return(nil)
+				return repo
+			},
+			producer: &stubProducer{},
+			wantCnt:  2,
+			wantMsgs: []*events.Message{
+				{Topic: "article_published", Key: []byte("11"), Value: []byte(`{"aid":11}`)},
+				{Topic: "article_published", Key: []byte("22"), Value: []byte(`{"aid":22}`)},
+			},
+		},
+		{
+			name: "查询发件箱失败",
+			mock: func(ctrl *gomock.Controller) repository.OutboxRepository {
+				repo := repomocks.NewMockOutboxRepository(ctrl)
+				repo.EXPECT().FindPending(gomock.Any(), batchSize).Return(nil, errors.New("mock db error"))
+				return repo
+			},
+			producer: &stubProducer{},
+			wantErr:  errors.New("mock db error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			r := NewRelay(tc.mock(ctrl), tc.producer)
+			cnt, err := r.relayOnce(context.Background())
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantCnt, cnt)
+			assert.Equal(t, tc.wantMsgs, tc.producer.msgs)
+		})
+	}
+}
+
+func TestRetryInterval(t *testing.T) {
+	assert.Equal(t, time.Second, retryInterval(0))
+	assert.Equal(t, time.Second*8, retryInterval(3))
+	assert.Equal(t, maxRetryInterval, retryInterval(20))
+}
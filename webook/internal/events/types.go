@@ -0,0 +1,29 @@
+package events
+
+import "context"
+
+// Consumer 业务里的消费者，Start 不阻塞，ctx 取消之后退出
+type Consumer interface {
+	Start(ctx context.Context) error
+}
+
+// TopicArticlePublished 帖子发表事件是 dao 在发表的事务里面写到发件箱的，
+// 所以定义在这里，不依赖 service
+const TopicArticlePublished = "article_published"
+
+// ArticlePublishedEvent 帖子发表，修改之后重新发表也算
+type ArticlePublishedEvent struct {
+	Aid   int64  `json:"aid"`
+	Uid   int64  `json:"uid"`
+	Title string `json:"title"`
+	// Ctime 发表时间，毫秒，时间线按照它排序
+	Ctime int64 `json:"ctime"`
+}
+
+// TopicUserSignup 注册事件也是 dao 在创建用户的事务里面写到发件箱的
+const TopicUserSignup = "user_signup"
+
+// UserSignupEvent 新用户注册，邮箱、手机号、微信都算
+type UserSignupEvent struct {
+	Uid int64 `json:"uid"`
+}
@@ -0,0 +1,45 @@
+package user
+
+import (
+	"context"
+	ievents "dream/webook/internal/events"
+	"dream/webook/internal/service"
+	"dream/webook/pkg/events"
+	"encoding/json"
+	"log"
+	"time"
+)
+
+// SignupEventConsumer 消费注册事件，给新用户建好默认收藏夹
+type SignupEventConsumer struct {
+	consumer events.Consumer
+	collSvc  service.CollectionService
+}
+
+func NewSignupEventConsumer(consumer events.Consumer, collSvc service.CollectionService) *SignupEventConsumer {
+	return &SignupEventConsumer{
+		consumer: consumer,
+		collSvc:  collSvc,
+	}
+}
+
+func (c *SignupEventConsumer) Start(ctx context.Context) error {
+	go func() {
+		err := c.consumer.Consume(ctx, []string{ievents.TopicUserSignup}, c.Consume)
+		log.Println("注册事件消费者退出", err)
+	}()
+	return nil
+}
+
+func (c *SignupEventConsumer) Consume(ctx context.Context, msg *events.Message) error {
+	var evt ievents.UserSignupEvent
+	if err := json.Unmarshal(msg.Value, &evt); err != nil {
+		// 格式不对的消息重试也没用，直接丢掉
+		log.Println("注册事件格式不对", string(msg.Value), err)
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	// 重复消费也没关系，已经有默认收藏夹就不会再建
+	return c.collSvc.CreateDefault(ctx, evt.Uid)
+}
@@ -0,0 +1,60 @@
+package user
+
+import (
+	"context"
+	"dream/webook/internal/service"
+	svcmocks "dream/webook/internal/service/mocks"
+	"dream/webook/pkg/events"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSignupEventConsumer_Consume(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) service.CollectionService
+		msg  *events.Message
+
+		wantErr error
+	}{
+		{
+			name: "创建默认收藏夹",
+			mock: func(ctrl *gomock.Controller) service.CollectionService {
+				svc := svcmocks.NewMockCollectionService(ctrl)
+				svc.EXPECT().CreateDefault(gomock.Any(), int64(123)).Return(nil)
+				return svc
+			},
+			msg: &events.Message{Value: []byte(`{"uid":123}`)},
+		},
+		{
+			name: "格式不对，直接跳过",
+			mock: func(ctrl *gomock.Controller) service.CollectionService {
+				return svcmocks.NewMockCollectionService(ctrl)
+			},
+			msg: &events.Message{Value: []byte(`not json`)},
+		},
+		{
+			name: "创建失败，重试",
+			mock: func(ctrl *gomock.Controller) service.CollectionService {
+				svc := svcmocks.NewMockCollectionService(ctrl)
+				svc.EXPECT().CreateDefault(gomock.Any(), int64(123)).
+					Return(errors.New("mock db error"))
+				return svc
+			},
+			msg:     &events.Message{Value: []byte(`{"uid":123}`)},
+			wantErr: errors.New("mock db error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			c := NewSignupEventConsumer(nil, tc.mock(ctrl))
+			err := c.Consume(context.Background(), tc.msg)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
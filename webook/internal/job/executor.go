@@ -0,0 +1,41 @@
+package job
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"fmt"
+)
+
+// Executor 执行数据库里的任务
+type Executor interface {
+	Name() string
+	Exec(ctx context.Context, j domain.Job) error
+}
+
+// LocalFuncExecutor 按照任务名字找到本地注册的方法执行
+type LocalFuncExecutor struct {
+	funcs map[string]func(ctx context.Context, j domain.Job) error
+}
+
+func NewLocalFuncExecutor() *LocalFuncExecutor {
+	return &LocalFuncExecutor{
+		funcs: make(map[string]func(ctx context.Context, j domain.Job) error),
+	}
+}
+
+func (l *LocalFuncExecutor) Name() string {
+	return "local"
+}
+
+// RegisterFunc 只能在启动的时候注册，运行期间不是并发安全的
+func (l *LocalFuncExecutor) RegisterFunc(name string, fn func(ctx context.Context, j domain.Job) error) {
+	l.funcs[name] = fn
+}
+
+func (l *LocalFuncExecutor) Exec(ctx context.Context, j domain.Job) error {
+	fn, ok := l.funcs[j.Name]
+	if !ok {
+		return fmt.Errorf("任务 %s 没有注册执行方法", j.Name)
+	}
+	return fn(ctx, j)
+}
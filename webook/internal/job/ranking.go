@@ -0,0 +1,33 @@
+package job
+
+import (
+	"context"
+	"dream/webook/internal/service"
+	"dream/webook/pkg/cronjob"
+	"time"
+)
+
+var _ cronjob.Job = (*RankingJob)(nil)
+
+// RankingJob 定时全量重新计算热榜
+type RankingJob struct {
+	svc     service.RankingService
+	timeout time.Duration
+}
+
+func NewRankingJob(svc service.RankingService) *RankingJob {
+	return &RankingJob{
+		svc:     svc,
+		timeout: time.Minute,
+	}
+}
+
+func (r *RankingJob) Name() string {
+	return "ranking"
+}
+
+func (r *RankingJob) Run(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	return r.svc.RankTopN(ctx)
+}
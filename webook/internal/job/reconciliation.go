@@ -0,0 +1,32 @@
+package job
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/service"
+	"time"
+)
+
+// ReconciliationJob 定时对账，放在数据库任务里面，可以在后台暂停
+type ReconciliationJob struct {
+	svc     service.ReconciliationService
+	timeout time.Duration
+}
+
+func NewReconciliationJob(svc service.ReconciliationService) *ReconciliationJob {
+	return &ReconciliationJob{
+		svc:     svc,
+		timeout: time.Minute * 5,
+	}
+}
+
+func (r *ReconciliationJob) Name() string {
+	return "reconciliation"
+}
+
+// Run 符合 LocalFuncExecutor 注册方法的签名
+func (r *ReconciliationJob) Run(ctx context.Context, j domain.Job) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	return r.svc.Reconcile(ctx)
+}
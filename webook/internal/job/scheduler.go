@@ -0,0 +1,124 @@
+package job
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/service"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"golang.org/x/sync/semaphore"
+)
+
+// Scheduler 从数据库里抢占到期的任务来执行
+// 和 cronjob.Scheduler 不同，任务的定义和状态都在数据库里，可以在运行期间暂停和恢复
+type Scheduler struct {
+	svc       service.JobService
+	executors map[string]Executor
+	owner     string
+	// interval 没有任务可以抢占的时候，隔多久再试
+	interval time.Duration
+	// heartbeat 执行期间隔多久续约一次，要比 JobService 的超时时间短
+	heartbeat time.Duration
+	// limiter 限制一个实例同时执行的任务数量
+	limiter *semaphore.Weighted
+}
+
+func NewScheduler(svc service.JobService) *Scheduler {
+	host, _ := os.Hostname()
+	return &Scheduler{
+		svc:       svc,
+		executors: make(map[string]Executor),
+		owner:     fmt.Sprintf("%s-%d", host, os.Getpid()),
+		interval:  time.Second * 10,
+		heartbeat: time.Second * 10,
+		limiter:   semaphore.NewWeighted(10),
+	}
+}
+
+func (s *Scheduler) RegisterExecutor(exec Executor) {
+	s.executors[exec.Name()] = exec
+}
+
+// Schedule 一直调度，直到 ctx 被取消
+func (s *Scheduler) Schedule(ctx context.Context) error {
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := s.limiter.Acquire(ctx, 1); err != nil {
+			return err
+		}
+		pctx, cancel := context.WithTimeout(ctx, time.Second)
+		j, err := s.svc.Preempt(pctx, s.owner)
+		cancel()
+		if err != nil {
+			s.limiter.Release(1)
+			if err != service.ErrNoMoreJob {
+				log.Println("抢占任务失败", err)
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(s.interval):
+			}
+			continue
+		}
+		go func() {
+			defer s.limiter.Release(1)
+			s.exec(ctx, j)
+		}()
+	}
+}
+
+// exec ctx 是调度的 ctx，退出的时候正在执行的任务也会被取消
+func (s *Scheduler) exec(ctx context.Context, j domain.Job) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	exec, ok := s.executors[j.Executor]
+	if !ok {
+		log.Println("找不到任务的执行器", j.Name, j.Executor)
+	} else {
+		done := make(chan struct{})
+		go s.keepAlive(j, done, cancel)
+		err := exec.Exec(ctx, j)
+		close(done)
+		if err != nil {
+			log.Println("执行任务失败", j.Name, err)
+		}
+	}
+	// 退出的时候也要释放，不然要等心跳超时别的实例才能抢占
+	rctx, rcancel := context.WithTimeout(context.Background(), time.Second)
+	defer rcancel()
+	err := s.svc.Release(rctx, j)
+	// 不是自己的了，说明执行期间被暂停或者被别人抢走了，不需要释放
+	if err != nil && err != service.ErrJobNotOwned {
+		log.Println("释放任务失败", j.Name, err)
+	}
+}
+
+// keepAlive 执行期间一直续约，任务不属于自己了就取消执行
+func (s *Scheduler) keepAlive(j domain.Job, done <-chan struct{}, cancel context.CancelFunc) {
+	ticker := time.NewTicker(s.heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			ctx, hcancel := context.WithTimeout(context.Background(), time.Second)
+			err := s.svc.Heartbeat(ctx, j)
+			hcancel()
+			if err == service.ErrJobNotOwned {
+				log.Println("任务已经不属于当前实例，取消执行", j.Name)
+				cancel()
+				return
+			}
+			if err != nil {
+				log.Println("任务续约失败", j.Name, err)
+			}
+		}
+	}
+}
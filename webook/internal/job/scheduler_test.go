@@ -0,0 +1,52 @@
+package job
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/service"
+	svcmocks "dream/webook/internal/service/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestScheduler_ScheduleCancel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	j := domain.Job{Id: 1, Name: "mock", Executor: "local", Version: 4, Owner: "instance-1"}
+	svc := svcmocks.NewMockJobService(ctrl)
+	svc.EXPECT().Preempt(gomock.Any(), gomock.Any()).Return(j, nil)
+	svc.EXPECT().Preempt(gomock.Any(), gomock.Any()).Return(domain.Job{}, service.ErrNoMoreJob).AnyTimes()
+	released := make(chan struct{})
+	// 抢占到的是哪个版本，释放的就是哪个版本
+	svc.EXPECT().Release(gomock.Any(), j).DoAndReturn(func(ctx context.Context, j domain.Job) error {
+		close(released)
+		return nil
+	})
+
+	started := make(chan struct{})
+	exec := NewLocalFuncExecutor()
+	exec.RegisterFunc("mock", func(ctx context.Context, j domain.Job) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	s := NewScheduler(svc)
+	s.RegisterExecutor(exec)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+	err := s.Schedule(ctx)
+	assert.Equal(t, context.Canceled, err)
+	// 调度退出之后正在执行的任务也被取消，并且释放了
+	select {
+	case <-released:
+	case <-time.After(time.Second):
+		t.Fatal("任务没有被取消")
+	}
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository/dao"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+)
+
+var ErrDuplicateCredit = dao.ErrDuplicateCredit
+
+type AccountRepository interface {
+	AddCredit(ctx context.Context, c domain.Credit) error
+	FindByUid(ctx context.Context, uid int64, typ domain.AccountType) ([]domain.Account, error)
+}
+
+type GORMAccountRepository struct {
+	dao dao.AccountDAO
+}
+
+func NewAccountRepository(dao dao.AccountDAO) AccountRepository {
+	return &GORMAccountRepository{
+		dao: dao,
+	}
+}
+
+func (r *GORMAccountRepository) AddCredit(ctx context.Context, c domain.Credit) error {
+	return r.dao.AddEntries(ctx, c.Biz, c.BizId, slice.Map(c.Items, func(idx int, src domain.CreditItem) dao.AccountEntryItem {
+		return dao.AccountEntryItem{
+			Uid:      src.Uid,
+			Type:     src.Type.ToUint8(),
+			Amount:   src.Amt,
+			Currency: src.Currency,
+		}
+	}))
+}
+
+func (r *GORMAccountRepository) FindByUid(ctx context.Context, uid int64, typ domain.AccountType) ([]domain.Account, error) {
+	accs, err := r.dao.FindByUid(ctx, uid, typ.ToUint8())
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(accs, func(idx int, src dao.Account) domain.Account {
+		return domain.Account{
+			Id:       src.Id,
+			Uid:      src.Uid,
+			Type:     domain.AccountType(src.Type),
+			Balance:  src.Balance,
+			Currency: src.Currency,
+			Ctime:    time.UnixMilli(src.Ctime),
+			Utime:    time.UnixMilli(src.Utime),
+		}
+	}), nil
+}
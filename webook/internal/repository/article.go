@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository/dao"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+)
+
+var (
+	ErrArticleNotFound         = dao.ErrArticleNotFound
+	ErrPossibleIncorrectAuthor = dao.ErrPossibleIncorrectAuthor
+)
+
+type ArticleRepository interface {
+	Create(ctx context.Context, art domain.Article) (int64, error)
+	Update(ctx context.Context, art domain.Article) error
+	GetByAuthor(ctx context.Context, uid int64, offset, limit int) ([]domain.Article, error)
+	GetById(ctx context.Context, id int64) (domain.Article, error)
+	// Sync 存储并同步到线上库
+	Sync(ctx context.Context, art domain.Article) (int64, error)
+	SyncStatus(ctx context.Context, uid, id int64, status domain.ArticleStatus) error
+	GetPublishedById(ctx context.Context, id int64) (domain.Article, error)
+	GetPublishedByIds(ctx context.Context, ids []int64) ([]domain.Article, error)
+	// ListPublishedSince 按 id 分页查询 start 之后发表并且还没有撤回的帖子，afterId 是上一批最后一条的 id
+	ListPublishedSince(ctx context.Context, start time.Time, afterId int64, limit int) ([]domain.Article, error)
+}
+
+type CachedArticleRepository struct {
+	dao dao.ArticleDAO
+}
+
+func NewArticleRepository(dao dao.ArticleDAO) ArticleRepository {
+	return &CachedArticleRepository{
+		dao: dao,
+	}
+}
+
+func (r *CachedArticleRepository) Create(ctx context.Context, art domain.Article) (int64, error) {
+	return r.dao.Insert(ctx, r.domainToEntity(art))
+}
+
+func (r *CachedArticleRepository) Update(ctx context.Context, art domain.Article) error {
+	return r.dao.UpdateById(ctx, r.domainToEntity(art))
+}
+
+func (r *CachedArticleRepository) GetByAuthor(ctx context.Context, uid int64, offset, limit int) ([]domain.Article, error) {
+	arts, err := r.dao.GetByAuthor(ctx, uid, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(arts, func(idx int, src dao.Article) domain.Article {
+		return r.entityToDomain(src)
+	}), nil
+}
+
+func (r *CachedArticleRepository) GetById(ctx context.Context, id int64) (domain.Article, error) {
+	art, err := r.dao.GetById(ctx, id)
+	if err != nil {
+		return domain.Article{}, err
+	}
+	return r.entityToDomain(art), nil
+}
+
+func (r *CachedArticleRepository) Sync(ctx context.Context, art domain.Article) (int64, error) {
+	return r.dao.Sync(ctx, r.domainToEntity(art))
+}
+
+func (r *CachedArticleRepository) SyncStatus(ctx context.Context, uid, id int64, status domain.ArticleStatus) error {
+	return r.dao.SyncStatus(ctx, uid, id, status.ToUint8())
+}
+
+func (r *CachedArticleRepository) GetPublishedById(ctx context.Context, id int64) (domain.Article, error) {
+	art, err := r.dao.GetPubById(ctx, id)
+	if err != nil {
+		return domain.Article{}, err
+	}
+	return r.entityToDomain(dao.Article(art)), nil
+}
+
+func (r *CachedArticleRepository) GetPublishedByIds(ctx context.Context, ids []int64) ([]domain.Article, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	arts, err := r.dao.GetPubByIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(arts, func(idx int, src dao.PublishedArticle) domain.Article {
+		return r.entityToDomain(dao.Article(src))
+	}), nil
+}
+
+func (r *CachedArticleRepository) ListPublishedSince(ctx context.Context, start time.Time, afterId int64, limit int) ([]domain.Article, error) {
+	arts, err := r.dao.ListPub(ctx, domain.ArticleStatusPublished.ToUint8(), start.UnixMilli(), afterId, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(arts, func(idx int, src dao.PublishedArticle) domain.Article {
+		return r.entityToDomain(dao.Article(src))
+	}), nil
+}
+
+func (r *CachedArticleRepository) entityToDomain(art dao.Article) domain.Article {
+	return domain.Article{
+		Id:      art.Id,
+		Title:   art.Title,
+		Content: art.Content,
+		Author: domain.Author{
+			Id: art.AuthorId,
+		},
+		Status: domain.ArticleStatus(art.Status),
+		Ctime:  time.UnixMilli(art.Ctime),
+		Utime:  time.UnixMilli(art.Utime),
+	}
+}
+
+func (r *CachedArticleRepository) domainToEntity(art domain.Article) dao.Article {
+	return dao.Article{
+		Id:       art.Id,
+		Title:    art.Title,
+		Content:  art.Content,
+		AuthorId: art.Author.Id,
+		Status:   art.Status.ToUint8(),
+	}
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository/dao"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+)
+
+var ErrRevisionNotFound = dao.ErrRevisionNotFound
+
+type ArticleRevisionRepository interface {
+	ListByArticle(ctx context.Context, artId int64, offset, limit int) ([]domain.ArticleRevision, error)
+	GetById(ctx context.Context, id int64) (domain.ArticleRevision, error)
+}
+
+type CachedArticleRevisionRepository struct {
+	dao dao.ArticleRevisionDAO
+}
+
+func NewArticleRevisionRepository(dao dao.ArticleRevisionDAO) ArticleRevisionRepository {
+	return &CachedArticleRevisionRepository{
+		dao: dao,
+	}
+}
+
+func (r *CachedArticleRevisionRepository) ListByArticle(ctx context.Context, artId int64, offset, limit int) ([]domain.ArticleRevision, error) {
+	revs, err := r.dao.ListByArticle(ctx, artId, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(revs, func(idx int, src dao.ArticleRevision) domain.ArticleRevision {
+		return r.entityToDomain(src)
+	}), nil
+}
+
+func (r *CachedArticleRevisionRepository) GetById(ctx context.Context, id int64) (domain.ArticleRevision, error) {
+	rev, err := r.dao.GetById(ctx, id)
+	if err != nil {
+		return domain.ArticleRevision{}, err
+	}
+	return r.entityToDomain(rev), nil
+}
+
+func (r *CachedArticleRevisionRepository) entityToDomain(rev dao.ArticleRevision) domain.ArticleRevision {
+	return domain.ArticleRevision{
+		Id:        rev.Id,
+		ArticleId: rev.ArticleId,
+		Author: domain.Author{
+			Id: rev.AuthorId,
+		},
+		Title:   rev.Title,
+		Content: rev.Content,
+		Hash:    rev.Hash,
+		Ctime:   time.UnixMilli(rev.Ctime),
+	}
+}
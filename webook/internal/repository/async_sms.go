@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository/dao"
+	"encoding/json"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+)
+
+var ErrNoMoreAsyncSMS = dao.ErrNoMoreAsyncSMS
+
+type AsyncSMSRepository interface {
+	Add(ctx context.Context, s domain.AsyncSMS) error
+	// Preempt 抢占一条到期的短信，lease 之内要上报发送结果
+	Preempt(ctx context.Context, lease time.Duration) (domain.AsyncSMS, error)
+	// ReportSuccess 和 ReportFailed 之后都不会再保存这条短信
+	ReportSuccess(ctx context.Context, id int64) error
+	ReportRetry(ctx context.Context, id int64, next time.Time) error
+	ReportFailed(ctx context.Context, id int64) error
+}
+
+type GORMAsyncSMSRepository struct {
+	dao dao.AsyncSMSDAO
+}
+
+func NewAsyncSMSRepository(dao dao.AsyncSMSDAO) AsyncSMSRepository {
+	return &GORMAsyncSMSRepository{
+		dao: dao,
+	}
+}
+
+// smsConfig 存在 Config 字段里面
+type smsConfig struct {
+	Tpl  string   `json:"tpl"`
+	Args []string `json:"args"`
+	// NamedArgs 之前存的数据没有这个字段，按照位置参数发送
+	NamedArgs []smsNamedArg `json:"named_args,omitempty"`
+	Numbers   []string      `json:"numbers"`
+}
+
+type smsNamedArg struct {
+	Name string `json:"name"`
+	Val  string `json:"val"`
+}
+
+func (r *GORMAsyncSMSRepository) Add(ctx context.Context, s domain.AsyncSMS) error {
+	cfg, err := json.Marshal(smsConfig{
+		Tpl:  s.Tpl,
+		Args: s.Args,
+		NamedArgs: slice.Map(s.NamedArgs, func(idx int, src domain.SMSNamedArg) smsNamedArg {
+			return smsNamedArg{Name: src.Name, Val: src.Val}
+		}),
+		Numbers: s.Numbers,
+	})
+	if err != nil {
+		return err
+	}
+	return r.dao.Insert(ctx, dao.AsyncSMS{
+		Config:   string(cfg),
+		RetryMax: s.RetryMax,
+		Deadline: s.Deadline.UnixMilli(),
+	})
+}
+
+func (r *GORMAsyncSMSRepository) Preempt(ctx context.Context, lease time.Duration) (domain.AsyncSMS, error) {
+	s, err := r.dao.Preempt(ctx, lease)
+	if err != nil {
+		return domain.AsyncSMS{}, err
+	}
+	var cfg smsConfig
+	if err = json.Unmarshal([]byte(s.Config), &cfg); err != nil {
+		// 数据坏了重试也没用
+		_ = r.dao.Delete(ctx, s.Id)
+		return domain.AsyncSMS{}, err
+	}
+	return domain.AsyncSMS{
+		Id:   s.Id,
+		Tpl:  cfg.Tpl,
+		Args: cfg.Args,
+		NamedArgs: slice.Map(cfg.NamedArgs, func(idx int, src smsNamedArg) domain.SMSNamedArg {
+			return domain.SMSNamedArg{Name: src.Name, Val: src.Val}
+		}),
+		Numbers:  cfg.Numbers,
+		Deadline: time.UnixMilli(s.Deadline),
+		RetryCnt: s.RetryCnt,
+		RetryMax: s.RetryMax,
+	}, nil
+}
+
+func (r *GORMAsyncSMSRepository) ReportSuccess(ctx context.Context, id int64) error {
+	return r.dao.Delete(ctx, id)
+}
+
+func (r *GORMAsyncSMSRepository) ReportRetry(ctx context.Context, id int64, next time.Time) error {
+	return r.dao.MarkRetry(ctx, id, next.UnixMilli())
+}
+
+func (r *GORMAsyncSMSRepository) ReportFailed(ctx context.Context, id int64) error {
+	return r.dao.Delete(ctx, id)
+}
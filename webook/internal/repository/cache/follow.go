@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	fieldFollowers = "followers"
+	fieldFollowees = "followees"
+)
+
+type FollowCache interface {
+	// Follow 缓存里有才更新两个人的计数，没有就什么都不做
+	Follow(ctx context.Context, follower, followee int64) error
+	CancelFollow(ctx context.Context, follower, followee int64) error
+	GetStatics(ctx context.Context, uid int64) (domain.FollowStatics, error)
+	SetStatics(ctx context.Context, uid int64, s domain.FollowStatics) error
+}
+
+type RedisFollowCache struct {
+	client     redis.Cmdable
+	expiration time.Duration
+}
+
+func NewFollowCache(client redis.Cmdable) FollowCache {
+	return &RedisFollowCache{
+		client:     client,
+		expiration: time.Minute * 15,
+	}
+}
+
+func (cache *RedisFollowCache) Follow(ctx context.Context, follower, followee int64) error {
+	return cache.update(ctx, follower, followee, 1)
+}
+
+func (cache *RedisFollowCache) CancelFollow(ctx context.Context, follower, followee int64) error {
+	return cache.update(ctx, follower, followee, -1)
+}
+
+// update 计数的脚本和交互数据是同一个
+func (cache *RedisFollowCache) update(ctx context.Context, follower, followee int64, delta int) error {
+	err := cache.client.Eval(ctx, luaIncrCnt, []string{cache.key(follower)}, fieldFollowees, delta).Err()
+	if err != nil {
+		return err
+	}
+	return cache.client.Eval(ctx, luaIncrCnt, []string{cache.key(followee)}, fieldFollowers, delta).Err()
+}
+
+func (cache *RedisFollowCache) GetStatics(ctx context.Context, uid int64) (domain.FollowStatics, error) {
+	data, err := cache.client.HGetAll(ctx, cache.key(uid)).Result()
+	if err != nil {
+		return domain.FollowStatics{}, err
+	}
+	if len(data) == 0 {
+		return domain.FollowStatics{}, ErrKeyNotExist
+	}
+	followers, _ := strconv.ParseInt(data[fieldFollowers], 10, 64)
+	followees, _ := strconv.ParseInt(data[fieldFollowees], 10, 64)
+	return domain.FollowStatics{
+		Followers: followers,
+		Followees: followees,
+	}, nil
+}
+
+func (cache *RedisFollowCache) SetStatics(ctx context.Context, uid int64, s domain.FollowStatics) error {
+	key := cache.key(uid)
+	err := cache.client.HSet(ctx, key,
+		fieldFollowers, s.Followers,
+		fieldFollowees, s.Followees).Err()
+	if err != nil {
+		return err
+	}
+	return cache.client.Expire(ctx, key, cache.expiration).Err()
+}
+
+func (cache *RedisFollowCache) key(uid int64) string {
+	return fmt.Sprintf("follow:statics:%d", uid)
+}
@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	_ "embed"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+//go:embed lua/interactive_incr_cnt.lua
+var luaIncrCnt string
+
+const (
+	fieldReadCnt    = "read_cnt"
+	fieldLikeCnt    = "like_cnt"
+	fieldCollectCnt = "collect_cnt"
+)
+
+type InteractiveCache interface {
+	// IncrReadCntIfPresent 缓存里有才自增，没有就什么都不做
+	IncrReadCntIfPresent(ctx context.Context, biz string, bizId int64) error
+	// BatchIncrReadCntIfPresent 批量版本，key 是 bizId，value 是增加的数量
+	BatchIncrReadCntIfPresent(ctx context.Context, biz string, cnts map[int64]int64) error
+	IncrLikeCntIfPresent(ctx context.Context, biz string, bizId int64) error
+	DecrLikeCntIfPresent(ctx context.Context, biz string, bizId int64) error
+	IncrCollectCntIfPresent(ctx context.Context, biz string, bizId int64) error
+	DecrCollectCntIfPresent(ctx context.Context, biz string, bizId int64) error
+	Get(ctx context.Context, biz string, bizId int64) (domain.Interactive, error)
+	Set(ctx context.Context, intr domain.Interactive) error
+}
+
+type RedisInteractiveCache struct {
+	client     redis.Cmdable
+	expiration time.Duration
+}
+
+func NewInteractiveCache(client redis.Cmdable) InteractiveCache {
+	return &RedisInteractiveCache{
+		client:     client,
+		expiration: time.Minute * 15,
+	}
+}
+
+func (cache *RedisInteractiveCache) IncrReadCntIfPresent(ctx context.Context, biz string, bizId int64) error {
+	return cache.incr(ctx, biz, bizId, fieldReadCnt, 1)
+}
+
+func (cache *RedisInteractiveCache) BatchIncrReadCntIfPresent(ctx context.Context, biz string, cnts map[int64]int64) error {
+	var err error
+	for bizId, cnt := range cnts {
+		// 某一个失败了不影响其它的
+		if er := cache.incr(ctx, biz, bizId, fieldReadCnt, int(cnt)); er != nil {
+			err = er
+		}
+	}
+	return err
+}
+
+func (cache *RedisInteractiveCache) IncrLikeCntIfPresent(ctx context.Context, biz string, bizId int64) error {
+	return cache.incr(ctx, biz, bizId, fieldLikeCnt, 1)
+}
+
+func (cache *RedisInteractiveCache) DecrLikeCntIfPresent(ctx context.Context, biz string, bizId int64) error {
+	return cache.incr(ctx, biz, bizId, fieldLikeCnt, -1)
+}
+
+func (cache *RedisInteractiveCache) IncrCollectCntIfPresent(ctx context.Context, biz string, bizId int64) error {
+	return cache.incr(ctx, biz, bizId, fieldCollectCnt, 1)
+}
+
+func (cache *RedisInteractiveCache) DecrCollectCntIfPresent(ctx context.Context, biz string, bizId int64) error {
+	return cache.incr(ctx, biz, bizId, fieldCollectCnt, -1)
+}
+
+// incr 用 lua 脚本保证判断 key 存在和自增是原子的
+func (cache *RedisInteractiveCache) incr(ctx context.Context, biz string, bizId int64, field string, delta int) error {
+	return cache.client.Eval(ctx, luaIncrCnt, []string{cache.key(biz, bizId)}, field, delta).Err()
+}
+
+func (cache *RedisInteractiveCache) Get(ctx context.Context, biz string, bizId int64) (domain.Interactive, error) {
+	data, err := cache.client.HGetAll(ctx, cache.key(biz, bizId)).Result()
+	if err != nil {
+		return domain.Interactive{}, err
+	}
+	// HGetAll key 不存在的时候不会返回 redis.Nil，而是空的 map
+	if len(data) == 0 {
+		return domain.Interactive{}, ErrKeyNotExist
+	}
+	// 这里不处理解析错误，缓存里的数据都是我们自己写进去的
+	readCnt, _ := strconv.ParseInt(data[fieldReadCnt], 10, 64)
+	likeCnt, _ := strconv.ParseInt(data[fieldLikeCnt], 10, 64)
+	collectCnt, _ := strconv.ParseInt(data[fieldCollectCnt], 10, 64)
+	return domain.Interactive{
+		Biz:        biz,
+		BizId:      bizId,
+		ReadCnt:    readCnt,
+		LikeCnt:    likeCnt,
+		CollectCnt: collectCnt,
+	}, nil
+}
+
+func (cache *RedisInteractiveCache) Set(ctx context.Context, intr domain.Interactive) error {
+	key := cache.key(intr.Biz, intr.BizId)
+	err := cache.client.HSet(ctx, key,
+		fieldReadCnt, intr.ReadCnt,
+		fieldLikeCnt, intr.LikeCnt,
+		fieldCollectCnt, intr.CollectCnt).Err()
+	if err != nil {
+		return err
+	}
+	return cache.client.Expire(ctx, key, cache.expiration).Err()
+}
+
+func (cache *RedisInteractiveCache) key(biz string, bizId int64) string {
+	return fmt.Sprintf("interactive:%s:%d", biz, bizId)
+}
@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository/cache/redismocks"
+	"errors"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRedisInteractiveCache_IncrLikeCntIfPresent(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) redis.Cmdable
+
+		wantErr error
+	}{
+		{
+			name: "自增成功",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				cmd := redismocks.NewMockCmdable(ctrl)
+				res := redis.NewCmd(context.Background())
+				res.SetVal(int64(1))
+				cmd.EXPECT().Eval(gomock.Any(), luaIncrCnt,
+					[]string{"interactive:article:1"}, []any{"like_cnt", 1}).Return(res)
+				return cmd
+			},
+		},
+		{
+			name: "redis出错",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				cmd := redismocks.NewMockCmdable(ctrl)
+				res := redis.NewCmd(context.Background())
+				res.SetErr(errors.New("mock redis error"))
+				cmd.EXPECT().Eval(gomock.Any(), luaIncrCnt,
+					[]string{"interactive:article:1"}, []any{"like_cnt", 1}).Return(res)
+				return cmd
+			},
+			wantErr: errors.New("mock redis error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			c := NewInteractiveCache(tc.mock(ctrl))
+			err := c.IncrLikeCntIfPresent(context.Background(), "article", 1)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestRedisInteractiveCache_Get(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) redis.Cmdable
+
+		wantIntr domain.Interactive
+		wantErr  error
+	}{
+		{
+			name: "命中缓存",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				cmd := redismocks.NewMockCmdable(ctrl)
+				res := redis.NewMapStringStringCmd(context.Background())
+				res.SetVal(map[string]string{
+					"read_cnt":    "10",
+					"like_cnt":    "3",
+					"collect_cnt": "2",
+				})
+				cmd.EXPECT().HGetAll(gomock.Any(), "interactive:article:1").Return(res)
+				return cmd
+			},
+			wantIntr: domain.Interactive{
+				Biz:        "article",
+				BizId:      1,
+				ReadCnt:    10,
+				LikeCnt:    3,
+				CollectCnt: 2,
+			},
+		},
+		{
+			name: "缓存没有",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				cmd := redismocks.NewMockCmdable(ctrl)
+				res := redis.NewMapStringStringCmd(context.Background())
+				res.SetVal(map[string]string{})
+				cmd.EXPECT().HGetAll(gomock.Any(), "interactive:article:1").Return(res)
+				return cmd
+			},
+			wantErr: ErrKeyNotExist,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			c := NewInteractiveCache(tc.mock(ctrl))
+			intr, err := c.Get(context.Background(), "article", 1)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantIntr, intr)
+		})
+	}
+}
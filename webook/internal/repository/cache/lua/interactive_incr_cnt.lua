@@ -0,0 +1,15 @@
+-- 具体业务的 key，比如 interactive:article:1
+local key = KEYS[1]
+-- 是阅读数、点赞数还是收藏数
+local cntKey = ARGV[1]
+-- +1 或者 -1
+local delta = tonumber(ARGV[2])
+local exists = redis.call("EXISTS", key)
+if exists == 1 then
+    redis.call("HINCRBY", key, cntKey, delta)
+    -- 自增成功
+    return 1
+else
+    -- 缓存里没有，不需要自增，等下次查询的时候从数据库加载
+    return 0
+end
@@ -0,0 +1,9 @@
+-- 更新一篇帖子的热度，然后只保留热度最高的 capacity 篇
+local key = KEYS[1]
+local member = ARGV[1]
+local score = ARGV[2]
+local capacity = tonumber(ARGV[3])
+redis.call("ZADD", key, score, member)
+-- 按分数从低到高排，删掉排在前面多出来的部分
+redis.call("ZREMRANGEBYRANK", key, 0, -capacity - 1)
+return 1
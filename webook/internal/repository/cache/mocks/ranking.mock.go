@@ -0,0 +1,85 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: webook/internal/repository/cache/ranking.go
+//
+// Generated by this command:
+//
+//	mockgen -source=webook/internal/repository/cache/ranking.go -package=cachemocks -destination=webook/internal/repository/cache/mocks/ranking.mock.go
+//
+
+// Package cachemocks is a generated GoMock package.
+package cachemocks
+
+import (
+	context "context"
+	domain "dream/webook/internal/domain"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRankingCache is a mock of RankingCache interface.
+type MockRankingCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockRankingCacheMockRecorder
+	isgomock struct{}
+}
+
+// MockRankingCacheMockRecorder is the mock recorder for MockRankingCache.
+type MockRankingCacheMockRecorder struct {
+	mock *MockRankingCache
+}
+
+// NewMockRankingCache creates a new mock instance.
+func NewMockRankingCache(ctrl *gomock.Controller) *MockRankingCache {
+	mock := &MockRankingCache{ctrl: ctrl}
+	mock.recorder = &MockRankingCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRankingCache) EXPECT() *MockRankingCacheMockRecorder {
+	return m.recorder
+}
+
+// Replace mocks base method.
+func (m *MockRankingCache) Replace(ctx context.Context, arts []domain.HotArticle) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", ctx, arts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockRankingCacheMockRecorder) Replace(ctx, arts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockRankingCache)(nil).Replace), ctx, arts)
+}
+
+// SetScore mocks base method.
+func (m *MockRankingCache) SetScore(ctx context.Context, id int64, score float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetScore", ctx, id, score)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetScore indicates an expected call of SetScore.
+func (mr *MockRankingCacheMockRecorder) SetScore(ctx, id, score any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetScore", reflect.TypeOf((*MockRankingCache)(nil).SetScore), ctx, id, score)
+}
+
+// TopN mocks base method.
+func (m *MockRankingCache) TopN(ctx context.Context, n int) ([]domain.HotArticle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TopN", ctx, n)
+	ret0, _ := ret[0].([]domain.HotArticle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TopN indicates an expected call of TopN.
+func (mr *MockRankingCacheMockRecorder) TopN(ctx, n any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TopN", reflect.TypeOf((*MockRankingCache)(nil).TopN), ctx, n)
+}
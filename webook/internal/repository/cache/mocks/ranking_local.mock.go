@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: webook/internal/repository/cache/ranking_local.go
+//
+// Generated by this command:
+//
+//	mockgen -source=webook/internal/repository/cache/ranking_local.go -package=cachemocks -destination=webook/internal/repository/cache/mocks/ranking_local.mock.go
+//
+
+// Package cachemocks is a generated GoMock package.
+package cachemocks
+
+import (
+	context "context"
+	domain "dream/webook/internal/domain"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRankingLocalCache is a mock of RankingLocalCache interface.
+type MockRankingLocalCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockRankingLocalCacheMockRecorder
+	isgomock struct{}
+}
+
+// MockRankingLocalCacheMockRecorder is the mock recorder for MockRankingLocalCache.
+type MockRankingLocalCacheMockRecorder struct {
+	mock *MockRankingLocalCache
+}
+
+// NewMockRankingLocalCache creates a new mock instance.
+func NewMockRankingLocalCache(ctrl *gomock.Controller) *MockRankingLocalCache {
+	mock := &MockRankingLocalCache{ctrl: ctrl}
+	mock.recorder = &MockRankingLocalCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRankingLocalCache) EXPECT() *MockRankingLocalCacheMockRecorder {
+	return m.recorder
+}
+
+// ForceGet mocks base method.
+func (m *MockRankingLocalCache) ForceGet(ctx context.Context) ([]domain.HotArticle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForceGet", ctx)
+	ret0, _ := ret[0].([]domain.HotArticle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ForceGet indicates an expected call of ForceGet.
+func (mr *MockRankingLocalCacheMockRecorder) ForceGet(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForceGet", reflect.TypeOf((*MockRankingLocalCache)(nil).ForceGet), ctx)
+}
+
+// Get mocks base method.
+func (m *MockRankingLocalCache) Get(ctx context.Context) ([]domain.HotArticle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx)
+	ret0, _ := ret[0].([]domain.HotArticle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockRankingLocalCacheMockRecorder) Get(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRankingLocalCache)(nil).Get), ctx)
+}
+
+// Set mocks base method.
+func (m *MockRankingLocalCache) Set(ctx context.Context, arts []domain.HotArticle) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, arts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockRankingLocalCacheMockRecorder) Set(ctx, arts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockRankingLocalCache)(nil).Set), ctx, arts)
+}
@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	_ "embed"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+//go:embed lua/ranking_set_score.lua
+var luaSetScore string
+
+// RankingCache 热榜，redis 里面是一个 zset，member 是帖子 id
+type RankingCache interface {
+	// SetScore 更新一篇帖子的热度，超过容量的部分会被淘汰
+	SetScore(ctx context.Context, id int64, score float64) error
+	// Replace 用全量计算的结果整个替换掉热榜
+	Replace(ctx context.Context, arts []domain.HotArticle) error
+	// TopN 热度从高到低，只有帖子 id 和热度
+	TopN(ctx context.Context, n int) ([]domain.HotArticle, error)
+}
+
+type RedisRankingCache struct {
+	client redis.Cmdable
+	key    string
+	// capacity 热榜里面最多保留多少篇帖子
+	capacity int
+}
+
+func NewRankingCache(client redis.Cmdable) RankingCache {
+	return &RedisRankingCache{
+		client:   client,
+		key:      "ranking:article",
+		capacity: 100,
+	}
+}
+
+func (cache *RedisRankingCache) SetScore(ctx context.Context, id int64, score float64) error {
+	return cache.client.Eval(ctx, luaSetScore, []string{cache.key},
+		strconv.FormatInt(id, 10), score, cache.capacity).Err()
+}
+
+func (cache *RedisRankingCache) Replace(ctx context.Context, arts []domain.HotArticle) error {
+	if len(arts) == 0 {
+		return cache.client.Del(ctx, cache.key).Err()
+	}
+	if len(arts) > cache.capacity {
+		arts = arts[:cache.capacity]
+	}
+	scores := make([]redis.Z, 0, len(arts))
+	for _, art := range arts {
+		scores = append(scores, redis.Z{
+			Score:  art.Score,
+			Member: strconv.FormatInt(art.Article.Id, 10),
+		})
+	}
+	// 先写到临时的 key 里面，再原子地 RENAME 过去，读的人不会看到写了一半的热榜
+	tmpKey := cache.key + ":tmp"
+	_, err := cache.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, tmpKey)
+		pipe.ZAdd(ctx, tmpKey, scores...)
+		pipe.Rename(ctx, tmpKey, cache.key)
+		return nil
+	})
+	return err
+}
+
+func (cache *RedisRankingCache) TopN(ctx context.Context, n int) ([]domain.HotArticle, error) {
+	zs, err := cache.client.ZRevRangeWithScores(ctx, cache.key, 0, int64(n-1)).Result()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]domain.HotArticle, 0, len(zs))
+	for _, z := range zs {
+		// member 都是我们自己写进去的帖子 id
+		member, _ := z.Member.(string)
+		id, _ := strconv.ParseInt(member, 10, 64)
+		res = append(res, domain.HotArticle{
+			Article: domain.Article{Id: id},
+			Score:   z.Score,
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"sync"
+	"time"
+)
+
+// RankingLocalCache 进程内的热榜副本，redis 出问题的时候兜底
+type RankingLocalCache interface {
+	Set(ctx context.Context, arts []domain.HotArticle) error
+	// Get 只返回没有过期的数据
+	Get(ctx context.Context) ([]domain.HotArticle, error)
+	// ForceGet 不管有没有过期，有数据就返回
+	ForceGet(ctx context.Context) ([]domain.HotArticle, error)
+}
+
+type MemoryRankingLocalCache struct {
+	mu         sync.RWMutex
+	arts       []domain.HotArticle
+	ddl        time.Time
+	expiration time.Duration
+}
+
+func NewRankingLocalCache() RankingLocalCache {
+	return &MemoryRankingLocalCache{
+		// 热榜本身就不要求实时，本地多缓存一会儿也没关系
+		expiration: time.Minute,
+	}
+}
+
+func (cache *MemoryRankingLocalCache) Set(ctx context.Context, arts []domain.HotArticle) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.arts = arts
+	cache.ddl = time.Now().Add(cache.expiration)
+	return nil
+}
+
+func (cache *MemoryRankingLocalCache) Get(ctx context.Context) ([]domain.HotArticle, error) {
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+	if len(cache.arts) == 0 || time.Now().After(cache.ddl) {
+		return nil, ErrKeyNotExist
+	}
+	return cache.arts, nil
+}
+
+func (cache *MemoryRankingLocalCache) ForceGet(ctx context.Context) ([]domain.HotArticle, error) {
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+	if len(cache.arts) == 0 {
+		return nil, ErrKeyNotExist
+	}
+	return cache.arts, nil
+}
@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRankingLocalCache(t *testing.T) {
+	ctx := context.Background()
+	c := NewRankingLocalCache().(*MemoryRankingLocalCache)
+	c.expiration = time.Millisecond * 50
+
+	// 还没有数据
+	_, err := c.Get(ctx)
+	assert.Equal(t, ErrKeyNotExist, err)
+	_, err = c.ForceGet(ctx)
+	assert.Equal(t, ErrKeyNotExist, err)
+
+	arts := []domain.HotArticle{
+		{Article: domain.Article{Id: 1}, Score: 2},
+		{Article: domain.Article{Id: 2}, Score: 1},
+	}
+	require.NoError(t, c.Set(ctx, arts))
+	res, err := c.Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, arts, res)
+
+	// 过期之后 Get 拿不到，兜底的 ForceGet 还能拿到
+	time.Sleep(time.Millisecond * 60)
+	_, err = c.Get(ctx)
+	assert.Equal(t, ErrKeyNotExist, err)
+	res, err = c.ForceGet(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, arts, res)
+}
@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RewardCache 缓存支付二维码，同一个人重复打赏同一个东西的时候不用重新下单
+type RewardCache interface {
+	GetCachedCodeURL(ctx context.Context, r domain.Reward) (domain.CodeURL, error)
+	CachedCodeURL(ctx context.Context, cu domain.CodeURL, r domain.Reward) error
+	// DelCodeURL 订单结束了，二维码不能再给下一次打赏用
+	DelCodeURL(ctx context.Context, r domain.Reward) error
+}
+
+type RedisRewardCache struct {
+	client redis.Cmdable
+	// expiration 要比支付平台二维码的有效期短
+	expiration time.Duration
+}
+
+func NewRewardCache(client redis.Cmdable) RewardCache {
+	return &RedisRewardCache{
+		client:     client,
+		expiration: time.Minute * 29,
+	}
+}
+
+func (cache *RedisRewardCache) GetCachedCodeURL(ctx context.Context, r domain.Reward) (domain.CodeURL, error) {
+	data, err := cache.client.Get(ctx, cache.codeURLKey(r)).Bytes()
+	if err != nil {
+		return domain.CodeURL{}, err
+	}
+	var cu domain.CodeURL
+	err = json.Unmarshal(data, &cu)
+	return cu, err
+}
+
+func (cache *RedisRewardCache) CachedCodeURL(ctx context.Context, cu domain.CodeURL, r domain.Reward) error {
+	data, err := json.Marshal(cu)
+	if err != nil {
+		return err
+	}
+	return cache.client.Set(ctx, cache.codeURLKey(r), data, cache.expiration).Err()
+}
+
+func (cache *RedisRewardCache) DelCodeURL(ctx context.Context, r domain.Reward) error {
+	return cache.client.Del(ctx, cache.codeURLKey(r)).Err()
+}
+
+func (cache *RedisRewardCache) codeURLKey(r domain.Reward) string {
+	// 金额不一样就是不同的订单
+	return fmt.Sprintf("reward:code_url:%s:%d:%d:%d", r.Target.Biz, r.Target.BizId, r.Uid, r.Amt)
+}
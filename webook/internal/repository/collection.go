@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository/dao"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+)
+
+var (
+	ErrCollectionNotFound     = dao.ErrCollectionNotFound
+	ErrPossibleIncorrectOwner = dao.ErrPossibleIncorrectOwner
+)
+
+type CollectionRepository interface {
+	Create(ctx context.Context, c domain.Collection) (int64, error)
+	Update(ctx context.Context, c domain.Collection) error
+	// Delete 删除收藏夹，同时删除里面的收藏记录
+	Delete(ctx context.Context, uid, id int64) error
+	FindById(ctx context.Context, id int64) (domain.Collection, error)
+	FindByUid(ctx context.Context, uid int64) ([]domain.Collection, error)
+	FindOrCreateDefault(ctx context.Context, uid int64) (domain.Collection, error)
+	ListItems(ctx context.Context, cid int64, offset, limit int) ([]domain.CollectionItem, error)
+	MoveItem(ctx context.Context, uid int64, biz string, bizId, from, to int64) error
+}
+
+type CachedCollectionRepository struct {
+	dao      dao.CollectionDAO
+	intrRepo InteractiveRepository
+}
+
+func NewCollectionRepository(dao dao.CollectionDAO, intrRepo InteractiveRepository) CollectionRepository {
+	return &CachedCollectionRepository{
+		dao:      dao,
+		intrRepo: intrRepo,
+	}
+}
+
+func (r *CachedCollectionRepository) Create(ctx context.Context, c domain.Collection) (int64, error) {
+	return r.dao.Insert(ctx, r.domainToEntity(c))
+}
+
+func (r *CachedCollectionRepository) Update(ctx context.Context, c domain.Collection) error {
+	return r.dao.Update(ctx, r.domainToEntity(c))
+}
+
+func (r *CachedCollectionRepository) Delete(ctx context.Context, uid, id int64) error {
+	items, err := r.dao.Delete(ctx, uid, id)
+	if err != nil {
+		return err
+	}
+	// 数据库里的收藏数已经减掉了，缓存也要跟上
+	for _, item := range items {
+		r.intrRepo.DecrCollectCntCache(ctx, item.Biz, item.BizId)
+	}
+	return nil
+}
+
+func (r *CachedCollectionRepository) FindById(ctx context.Context, id int64) (domain.Collection, error) {
+	c, err := r.dao.FindById(ctx, id)
+	if err != nil {
+		return domain.Collection{}, err
+	}
+	return r.entityToDomain(c), nil
+}
+
+func (r *CachedCollectionRepository) FindByUid(ctx context.Context, uid int64) ([]domain.Collection, error) {
+	cs, err := r.dao.FindByUid(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(cs, func(idx int, src dao.Collection) domain.Collection {
+		return r.entityToDomain(src)
+	}), nil
+}
+
+func (r *CachedCollectionRepository) FindOrCreateDefault(ctx context.Context, uid int64) (domain.Collection, error) {
+	c, err := r.dao.FindOrCreateDefault(ctx, uid)
+	if err != nil {
+		return domain.Collection{}, err
+	}
+	return r.entityToDomain(c), nil
+}
+
+// ListItems 只填充收藏记录本身，帖子内容和交互数据由调用者补充
+func (r *CachedCollectionRepository) ListItems(ctx context.Context, cid int64, offset, limit int) ([]domain.CollectionItem, error) {
+	items, err := r.dao.ListItems(ctx, cid, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(items, func(idx int, src dao.UserCollectionBiz) domain.CollectionItem {
+		return domain.CollectionItem{
+			Cid: src.Cid,
+			Article: domain.Article{
+				Id: src.BizId,
+			},
+			Interactive: domain.Interactive{
+				Biz:   src.Biz,
+				BizId: src.BizId,
+			},
+			Ctime: time.UnixMilli(src.Ctime),
+		}
+	}), nil
+}
+
+func (r *CachedCollectionRepository) MoveItem(ctx context.Context, uid int64, biz string, bizId, from, to int64) error {
+	return r.dao.MoveItem(ctx, uid, biz, bizId, from, to)
+}
+
+func (r *CachedCollectionRepository) entityToDomain(c dao.Collection) domain.Collection {
+	return domain.Collection{
+		Id:      c.Id,
+		Uid:     c.Uid,
+		Name:    c.Name,
+		Private: c.Private,
+		Default: c.Default,
+		Ctime:   time.UnixMilli(c.Ctime),
+		Utime:   time.UnixMilli(c.Utime),
+	}
+}
+
+func (r *CachedCollectionRepository) domainToEntity(c domain.Collection) dao.Collection {
+	return dao.Collection{
+		Id:      c.Id,
+		Uid:     c.Uid,
+		Name:    c.Name,
+		Private: c.Private,
+		Default: c.Default,
+	}
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository/dao"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+)
+
+var ErrCommentNotFound = dao.ErrCommentNotFound
+
+type CommentRepository interface {
+	Create(ctx context.Context, c domain.Comment) (int64, error)
+	FindById(ctx context.Context, id int64) (domain.Comment, error)
+	FindRoots(ctx context.Context, biz string, bizId, maxId int64, limit int) ([]domain.Comment, error)
+	FindReplies(ctx context.Context, rootId, minId int64, limit int) ([]domain.Comment, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type GORMCommentRepository struct {
+	dao dao.CommentDAO
+}
+
+func NewCommentRepository(dao dao.CommentDAO) CommentRepository {
+	return &GORMCommentRepository{
+		dao: dao,
+	}
+}
+
+func (r *GORMCommentRepository) Create(ctx context.Context, c domain.Comment) (int64, error) {
+	return r.dao.Insert(ctx, dao.Comment{
+		Uid:      c.Uid,
+		Biz:      c.Biz,
+		BizId:    c.BizId,
+		RootId:   c.RootId,
+		ParentId: c.ParentId,
+		Content:  c.Content,
+	})
+}
+
+func (r *GORMCommentRepository) FindById(ctx context.Context, id int64) (domain.Comment, error) {
+	c, err := r.dao.FindById(ctx, id)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+	return r.entityToDomain(c), nil
+}
+
+func (r *GORMCommentRepository) FindRoots(ctx context.Context, biz string, bizId, maxId int64, limit int) ([]domain.Comment, error) {
+	cs, err := r.dao.FindRoots(ctx, biz, bizId, maxId, limit)
+	if err != nil {
+		return nil, err
+	}
+	return r.toDomains(cs), nil
+}
+
+func (r *GORMCommentRepository) FindReplies(ctx context.Context, rootId, minId int64, limit int) ([]domain.Comment, error) {
+	cs, err := r.dao.FindReplies(ctx, rootId, minId, limit)
+	if err != nil {
+		return nil, err
+	}
+	return r.toDomains(cs), nil
+}
+
+func (r *GORMCommentRepository) Delete(ctx context.Context, id int64) error {
+	return r.dao.SoftDelete(ctx, id)
+}
+
+func (r *GORMCommentRepository) toDomains(cs []dao.Comment) []domain.Comment {
+	return slice.Map(cs, func(idx int, src dao.Comment) domain.Comment {
+		return r.entityToDomain(src)
+	})
+}
+
+func (r *GORMCommentRepository) entityToDomain(c dao.Comment) domain.Comment {
+	res := domain.Comment{
+		Id:       c.Id,
+		Uid:      c.Uid,
+		Biz:      c.Biz,
+		BizId:    c.BizId,
+		Content:  c.Content,
+		RootId:   c.RootId,
+		ParentId: c.ParentId,
+		Deleted:  c.IsDeleted(),
+		Ctime:    time.UnixMilli(c.Ctime),
+		Utime:    time.UnixMilli(c.Utime),
+	}
+	// 删除的评论不能再把内容带出去
+	if res.Deleted {
+		res.Content = ""
+	}
+	return res
+}
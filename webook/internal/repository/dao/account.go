@@ -0,0 +1,123 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrDuplicateCredit 这笔支付已经入过账了
+var ErrDuplicateCredit = errors.New("重复入账")
+
+type AccountDAO interface {
+	// AddEntries 在一个事务里面记流水、改余额，流水的金额加起来必须是 0
+	AddEntries(ctx context.Context, biz string, bizId int64, items []AccountEntryItem) error
+	FindByUid(ctx context.Context, uid int64, typ uint8) ([]Account, error)
+}
+
+type GORMAccountDAO struct {
+	db *gorm.DB
+}
+
+func NewAccountDAO(db *gorm.DB) AccountDAO {
+	return &GORMAccountDAO{
+		db: db,
+	}
+}
+
+func (dao *GORMAccountDAO) AddEntries(ctx context.Context, biz string, bizId int64, items []AccountEntryItem) error {
+	now := time.Now().UnixMilli()
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, item := range items {
+			// 第一次入账的时候创建账户，已经有了就什么都不做
+			err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&Account{
+				Uid:      item.Uid,
+				Type:     item.Type,
+				Currency: item.Currency,
+				Ctime:    now,
+				Utime:    now,
+			}).Error
+			if err != nil {
+				return err
+			}
+			// 锁住账户，要用当前读才能看到别的事务刚刚创建的账户
+			var acc Account
+			err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("uid = ? AND type = ? AND currency = ?", item.Uid, item.Type, item.Currency).
+				First(&acc).Error
+			if err != nil {
+				return err
+			}
+			// 流水上的唯一索引保证同一笔支付只会入账一次
+			err = tx.Create(&AccountEntry{
+				AccountId: acc.Id,
+				Biz:       biz,
+				BizId:     bizId,
+				Amount:    item.Amount,
+				Currency:  item.Currency,
+				Ctime:     now,
+			}).Error
+			if err != nil {
+				return err
+			}
+			err = tx.Model(&Account{}).Where("id = ?", acc.Id).
+				Updates(map[string]any{
+					"balance": gorm.Expr("`balance` + ?", item.Amount),
+					"utime":   now,
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if mysqlErr, ok := err.(*mysql.MySQLError); ok {
+		const uniqueConflictErr uint16 = 1062
+		// 账户用的是 DoNothing，这里只可能是流水冲突
+		if mysqlErr.Number == uniqueConflictErr {
+			return ErrDuplicateCredit
+		}
+	}
+	return err
+}
+
+func (dao *GORMAccountDAO) FindByUid(ctx context.Context, uid int64, typ uint8) ([]Account, error) {
+	var accs []Account
+	err := dao.db.WithContext(ctx).Where("uid = ? AND type = ?", uid, typ).
+		Order("id ASC").Find(&accs).Error
+	return accs, err
+}
+
+// AccountEntryItem 一个账户的一条流水
+type AccountEntryItem struct {
+	Uid      int64
+	Type     uint8
+	Amount   int64
+	Currency string
+}
+
+// Account 账户，余额只能跟着流水一起改
+type Account struct {
+	Id       int64  `gorm:"primaryKey,autoIncrement"`
+	Uid      int64  `gorm:"uniqueIndex:uid_type_currency"`
+	Type     uint8  `gorm:"uniqueIndex:uid_type_currency"`
+	Currency string `gorm:"type:varchar(16);uniqueIndex:uid_type_currency"`
+	Balance  int64
+	Ctime    int64
+	Utime    int64
+}
+
+// AccountEntry 流水，只插入不修改
+type AccountEntry struct {
+	Id        int64  `gorm:"primaryKey,autoIncrement"`
+	AccountId int64  `gorm:"uniqueIndex:biz_type_id_account"`
+	Biz       string `gorm:"type:varchar(128);uniqueIndex:biz_type_id_account"`
+	BizId     int64  `gorm:"uniqueIndex:biz_type_id_account"`
+	Amount    int64
+	Currency  string `gorm:"type:varchar(16)"`
+	Ctime     int64
+}
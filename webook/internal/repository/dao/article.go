@@ -0,0 +1,216 @@
+package dao
+
+import (
+	"context"
+	"dream/webook/internal/events"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrArticleNotFound = gorm.ErrRecordNotFound
+
+// ErrPossibleIncorrectAuthor 更新不到数据，要么 id 不对，要么作者不对
+var ErrPossibleIncorrectAuthor = errors.New("更新数据失败，ID不对或者作者不对")
+
+type ArticleDAO interface {
+	Insert(ctx context.Context, art Article) (int64, error)
+	UpdateById(ctx context.Context, art Article) error
+	GetByAuthor(ctx context.Context, uid int64, offset, limit int) ([]Article, error)
+	GetById(ctx context.Context, id int64) (Article, error)
+	// Sync 把制作库的帖子同步到线上库，两张表和发表事件在同一个事务里面
+	Sync(ctx context.Context, art Article) (int64, error)
+	SyncStatus(ctx context.Context, uid, id int64, status uint8) error
+	GetPubById(ctx context.Context, id int64) (PublishedArticle, error)
+	GetPubByIds(ctx context.Context, ids []int64) ([]PublishedArticle, error)
+	// ListPub 查询 start 之后发表的、状态是 status 的帖子，按 id 排序，
+	// afterId 是上一批最后一条的 id，边查边有新帖子发表也不会跳过或者重复
+	ListPub(ctx context.Context, status uint8, start int64, afterId int64, limit int) ([]PublishedArticle, error)
+}
+
+type GORMArticleDAO struct {
+	db *gorm.DB
+}
+
+func NewArticleDAO(db *gorm.DB) ArticleDAO {
+	return &GORMArticleDAO{
+		db: db,
+	}
+}
+
+func (dao *GORMArticleDAO) Insert(ctx context.Context, art Article) (int64, error) {
+	var id int64
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		id, err = dao.save(tx, art)
+		return err
+	})
+	return id, err
+}
+
+func (dao *GORMArticleDAO) UpdateById(ctx context.Context, art Article) error {
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		_, err := dao.save(tx, art)
+		return err
+	})
+}
+
+// save 新建或者更新制作库，同时记录一个历史版本
+func (dao *GORMArticleDAO) save(tx *gorm.DB, art Article) (int64, error) {
+	var err error
+	if art.Id > 0 {
+		err = dao.updateById(tx, art)
+	} else {
+		now := time.Now().UnixMilli()
+		art.Ctime = now
+		art.Utime = now
+		err = tx.Create(&art).Error
+	}
+	if err != nil {
+		return art.Id, err
+	}
+	return art.Id, insertRevision(tx, art)
+}
+
+func (dao *GORMArticleDAO) updateById(db *gorm.DB, art Article) error {
+	now := time.Now().UnixMilli()
+	// 带上 author_id 作为条件，防止别人修改不属于自己的帖子
+	res := db.Model(&Article{}).
+		Where("id = ? AND author_id = ?", art.Id, art.AuthorId).
+		Updates(map[string]any{
+			"title":   art.Title,
+			"content": art.Content,
+			"status":  art.Status,
+			"utime":   now,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrPossibleIncorrectAuthor
+	}
+	return nil
+}
+
+func (dao *GORMArticleDAO) GetByAuthor(ctx context.Context, uid int64, offset, limit int) ([]Article, error) {
+	var arts []Article
+	err := dao.db.WithContext(ctx).
+		Where("author_id = ?", uid).
+		Offset(offset).Limit(limit).
+		// 按照更新时间倒序，刚编辑过的排在前面
+		Order("utime DESC").
+		Find(&arts).Error
+	return arts, err
+}
+
+func (dao *GORMArticleDAO) GetById(ctx context.Context, id int64) (Article, error) {
+	var art Article
+	err := dao.db.WithContext(ctx).Where("id = ?", id).First(&art).Error
+	return art, err
+}
+
+func (dao *GORMArticleDAO) Sync(ctx context.Context, art Article) (int64, error) {
+	id := art.Id
+	now := time.Now().UnixMilli()
+	// 闭包形态，GORM 帮我们管理了事务的生命周期
+	// 返回 error 就回滚，否则提交
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		id, err = dao.save(tx, art)
+		if err != nil {
+			return err
+		}
+		art.Id = id
+		err = dao.upsertPub(tx, PublishedArticle(art), now)
+		if err != nil {
+			return err
+		}
+		return insertOutbox(tx, events.TopicArticlePublished, id, events.ArticlePublishedEvent{
+			Aid:   id,
+			Uid:   art.AuthorId,
+			Title: art.Title,
+			Ctime: now,
+		})
+	})
+	return id, err
+}
+
+// upsertPub 线上库有就更新，没有就插入
+func (dao *GORMArticleDAO) upsertPub(tx *gorm.DB, art PublishedArticle, now int64) error {
+	art.Ctime = now
+	art.Utime = now
+	// 对应 MySQL 的 INSERT ... ON DUPLICATE KEY UPDATE
+	return tx.Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{
+			"title":   art.Title,
+			"content": art.Content,
+			"status":  art.Status,
+			"utime":   now,
+		}),
+	}).Create(&art).Error
+}
+
+func (dao *GORMArticleDAO) SyncStatus(ctx context.Context, uid, id int64, status uint8) error {
+	now := time.Now().UnixMilli()
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&Article{}).
+			Where("id = ? AND author_id = ?", id, uid).
+			Updates(map[string]any{
+				"status": status,
+				"utime":  now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrPossibleIncorrectAuthor
+		}
+		// 制作库已经校验过作者了，线上库可能还没有这条数据，比如从来没发表过
+		return tx.Model(&PublishedArticle{}).
+			Where("id = ?", id).
+			Updates(map[string]any{
+				"status": status,
+				"utime":  now,
+			}).Error
+	})
+}
+
+func (dao *GORMArticleDAO) GetPubById(ctx context.Context, id int64) (PublishedArticle, error) {
+	var art PublishedArticle
+	err := dao.db.WithContext(ctx).Where("id = ?", id).First(&art).Error
+	return art, err
+}
+
+func (dao *GORMArticleDAO) GetPubByIds(ctx context.Context, ids []int64) ([]PublishedArticle, error) {
+	var arts []PublishedArticle
+	err := dao.db.WithContext(ctx).Where("id IN ?", ids).Find(&arts).Error
+	return arts, err
+}
+
+func (dao *GORMArticleDAO) ListPub(ctx context.Context, status uint8, start int64, afterId int64, limit int) ([]PublishedArticle, error) {
+	var arts []PublishedArticle
+	err := dao.db.WithContext(ctx).
+		Where("id > ? AND ctime >= ? AND status = ?", afterId, start, status).
+		Order("id ASC").
+		Limit(limit).
+		Find(&arts).Error
+	return arts, err
+}
+
+// Article 制作库，作者看到的都是这张表里的数据
+type Article struct {
+	Id      int64  `gorm:"primaryKey,autoIncrement"`
+	Title   string `gorm:"type:varchar(1024)"`
+	Content string `gorm:"type:BLOB"`
+	// 按作者查询列表，并且按照更新时间排序
+	AuthorId int64 `gorm:"index:aid_utime"`
+	Status   uint8
+	Ctime    int64
+	Utime    int64 `gorm:"index:aid_utime"`
+}
+
+// PublishedArticle 线上库，读者看到的都是这张表里的数据
+// 和制作库同一个 id
+type PublishedArticle Article
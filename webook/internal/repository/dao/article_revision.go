@@ -0,0 +1,71 @@
+package dao
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrRevisionNotFound = gorm.ErrRecordNotFound
+
+// ArticleRevisionDAO 只读，写入是在 ArticleDAO 保存帖子的事务里面完成的
+type ArticleRevisionDAO interface {
+	ListByArticle(ctx context.Context, artId int64, offset, limit int) ([]ArticleRevision, error)
+	GetById(ctx context.Context, id int64) (ArticleRevision, error)
+}
+
+type GORMArticleRevisionDAO struct {
+	db *gorm.DB
+}
+
+func NewArticleRevisionDAO(db *gorm.DB) ArticleRevisionDAO {
+	return &GORMArticleRevisionDAO{
+		db: db,
+	}
+}
+
+func (dao *GORMArticleRevisionDAO) ListByArticle(ctx context.Context, artId int64, offset, limit int) ([]ArticleRevision, error) {
+	var revs []ArticleRevision
+	// 列表不需要内容
+	err := dao.db.WithContext(ctx).
+		Select("id", "article_id", "author_id", "title", "hash", "ctime").
+		Where("article_id = ?", artId).
+		Order("id DESC").
+		Offset(offset).Limit(limit).
+		Find(&revs).Error
+	return revs, err
+}
+
+func (dao *GORMArticleRevisionDAO) GetById(ctx context.Context, id int64) (ArticleRevision, error) {
+	var rev ArticleRevision
+	err := dao.db.WithContext(ctx).Where("id = ?", id).First(&rev).Error
+	return rev, err
+}
+
+// insertRevision 在保存帖子的事务里面调用，帖子保存成功才会有这个版本
+func insertRevision(tx *gorm.DB, art Article) error {
+	sum := sha256.Sum256([]byte(art.Content))
+	return tx.Create(&ArticleRevision{
+		ArticleId: art.Id,
+		AuthorId:  art.AuthorId,
+		Title:     art.Title,
+		Content:   art.Content,
+		Hash:      hex.EncodeToString(sum[:]),
+		Ctime:     time.Now().UnixMilli(),
+	}).Error
+}
+
+// ArticleRevision 帖子的历史版本，只插入不更新
+type ArticleRevision struct {
+	Id int64 `gorm:"primaryKey,autoIncrement"`
+	// 按照帖子查询版本列表
+	ArticleId int64 `gorm:"index"`
+	AuthorId  int64
+	Title     string `gorm:"type:varchar(1024)"`
+	Content   string `gorm:"type:BLOB"`
+	Hash      string `gorm:"type:char(64)"`
+	Ctime     int64
+}
@@ -0,0 +1,139 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+func TestGORMArticleDAO_Sync(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(t *testing.T) *sql.DB
+		art  Article
+
+		wantId  int64
+		wantErr error
+	}{
+		{
+			name: "新建并发表成功",
+			mock: func(t *testing.T) *sql.DB {
+				mockDB, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `articles` .*").
+					WillReturnResult(sqlmock.NewResult(11, 1))
+				mock.ExpectExec("INSERT INTO `article_revisions` .*").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO `published_articles` .* ON DUPLICATE KEY UPDATE .*").
+					WillReturnResult(sqlmock.NewResult(11, 1))
+				mock.ExpectExec("INSERT INTO `outbox_messages` .*").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+				return mockDB
+			},
+			art: Article{
+				Title:    "我的标题",
+				Content:  "我的内容",
+				AuthorId: 123,
+				Status:   2,
+			},
+			wantId: 11,
+		},
+		{
+			name: "修改并发表成功",
+			mock: func(t *testing.T) *sql.DB {
+				mockDB, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE `articles` SET .*").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO `article_revisions` .*").
+					WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectExec("INSERT INTO `published_articles` .* ON DUPLICATE KEY UPDATE .*").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec("INSERT INTO `outbox_messages` .*").
+					WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectCommit()
+				return mockDB
+			},
+			art: Article{
+				Id:       11,
+				Title:    "新的标题",
+				Content:  "新的内容",
+				AuthorId: 123,
+				Status:   2,
+			},
+			wantId: 11,
+		},
+		{
+			name: "修改别人的帖子，回滚",
+			mock: func(t *testing.T) *sql.DB {
+				mockDB, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE `articles` SET .*").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+				return mockDB
+			},
+			art: Article{
+				Id:       11,
+				Title:    "新的标题",
+				Content:  "新的内容",
+				AuthorId: 234,
+				Status:   2,
+			},
+			wantId:  11,
+			wantErr: ErrPossibleIncorrectAuthor,
+		},
+		{
+			name: "同步线上库失败，回滚",
+			mock: func(t *testing.T) *sql.DB {
+				mockDB, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE `articles` SET .*").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO `article_revisions` .*").
+					WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectExec("INSERT INTO `published_articles` .*").
+					WillReturnError(errors.New("mock db error"))
+				mock.ExpectRollback()
+				return mockDB
+			},
+			art: Article{
+				Id:       11,
+				Title:    "新的标题",
+				Content:  "新的内容",
+				AuthorId: 123,
+				Status:   2,
+			},
+			wantId:  11,
+			wantErr: errors.New("mock db error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      tc.mock(t),
+				SkipInitializeWithVersion: true,
+			}), &gorm.Config{
+				SkipDefaultTransaction: true,
+				DisableAutomaticPing:   true,
+			})
+			require.NoError(t, err)
+			d := NewArticleDAO(db)
+			id, err := d.Sync(context.Background(), tc.art)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantId, id)
+		})
+	}
+}
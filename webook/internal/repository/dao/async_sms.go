@@ -0,0 +1,104 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrNoMoreAsyncSMS 没有到期需要发送的短信
+var ErrNoMoreAsyncSMS = errors.New("没有需要发送的短信")
+
+type AsyncSMSDAO interface {
+	Insert(ctx context.Context, s AsyncSMS) error
+	// Preempt 抢占一条到期的短信，lease 之内没有上报结果的话别的实例可以再抢
+	Preempt(ctx context.Context, lease time.Duration) (AsyncSMS, error)
+	// MarkRetry 重试次数 +1，nextTime 之后再发
+	MarkRetry(ctx context.Context, id int64, nextTime int64) error
+	// Delete 发送成功或者放弃了都直接删掉，短信里面有验证码，不能一直留着
+	Delete(ctx context.Context, id int64) error
+}
+
+type GORMAsyncSMSDAO struct {
+	db *gorm.DB
+}
+
+func NewAsyncSMSDAO(db *gorm.DB) AsyncSMSDAO {
+	return &GORMAsyncSMSDAO{
+		db: db,
+	}
+}
+
+func (dao *GORMAsyncSMSDAO) Insert(ctx context.Context, s AsyncSMS) error {
+	now := time.Now().UnixMilli()
+	s.NextTime = now
+	s.Ctime = now
+	s.Utime = now
+	return dao.db.WithContext(ctx).Create(&s).Error
+}
+
+func (dao *GORMAsyncSMSDAO) Preempt(ctx context.Context, lease time.Duration) (AsyncSMS, error) {
+	db := dao.db.WithContext(ctx)
+	for {
+		now := time.Now().UnixMilli()
+		var s AsyncSMS
+		err := db.Where("next_time <= ?", now).
+			Order("next_time ASC").
+			First(&s).Error
+		if err == gorm.ErrRecordNotFound {
+			return AsyncSMS{}, ErrNoMoreAsyncSMS
+		}
+		if err != nil {
+			return AsyncSMS{}, err
+		}
+		// 乐观锁，version 变了说明被别人抢先了
+		// 抢到之后把 next_time 往后推，发送的实例挂了的话，过了 lease 还能被别人抢到
+		res := db.Model(&AsyncSMS{}).
+			Where("id = ? AND version = ?", s.Id, s.Version).
+			Updates(map[string]any{
+				"next_time": now + lease.Milliseconds(),
+				"version":   s.Version + 1,
+				"utime":     now,
+			})
+		if res.Error != nil {
+			return AsyncSMS{}, res.Error
+		}
+		if res.RowsAffected == 0 {
+			continue
+		}
+		s.Version++
+		return s, nil
+	}
+}
+
+func (dao *GORMAsyncSMSDAO) MarkRetry(ctx context.Context, id int64, nextTime int64) error {
+	return dao.db.WithContext(ctx).Model(&AsyncSMS{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"retry_cnt": gorm.Expr("`retry_cnt` + 1"),
+			"next_time": nextTime,
+			"utime":     time.Now().UnixMilli(),
+		}).Error
+}
+
+func (dao *GORMAsyncSMSDAO) Delete(ctx context.Context, id int64) error {
+	return dao.db.WithContext(ctx).Where("id = ?", id).Delete(&AsyncSMS{}).Error
+}
+
+// AsyncSMS 等待异步发送的短信，发完了就删掉
+type AsyncSMS struct {
+	Id int64 `gorm:"primaryKey,autoIncrement"`
+	// Config 模板、参数和手机号，JSON 格式
+	Config   string `gorm:"type:varchar(4096)"`
+	RetryCnt int
+	RetryMax int
+	// Deadline 过了这个时间就不用发了
+	Deadline int64
+	// NextTime 按照下次发送时间抢占
+	NextTime int64 `gorm:"index"`
+	Version  int64
+	Ctime    int64
+	Utime    int64
+}
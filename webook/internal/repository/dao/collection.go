@@ -0,0 +1,189 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCollectionNotFound = gorm.ErrRecordNotFound
+	// ErrPossibleIncorrectOwner 操作不到数据，要么 id 不对，要么收藏夹不是这个用户的
+	ErrPossibleIncorrectOwner = errors.New("操作数据失败，ID不对或者用户不对")
+)
+
+type CollectionDAO interface {
+	Insert(ctx context.Context, c Collection) (int64, error)
+	Update(ctx context.Context, c Collection) error
+	// Delete 删除收藏夹以及里面的收藏记录，返回被删除的收藏记录
+	Delete(ctx context.Context, uid, id int64) ([]UserCollectionBiz, error)
+	FindById(ctx context.Context, id int64) (Collection, error)
+	FindByUid(ctx context.Context, uid int64) ([]Collection, error)
+	FindOrCreateDefault(ctx context.Context, uid int64) (Collection, error)
+	ListItems(ctx context.Context, cid int64, offset, limit int) ([]UserCollectionBiz, error)
+	MoveItem(ctx context.Context, uid int64, biz string, bizId, from, to int64) error
+}
+
+type GORMCollectionDAO struct {
+	db *gorm.DB
+}
+
+func NewCollectionDAO(db *gorm.DB) CollectionDAO {
+	return &GORMCollectionDAO{
+		db: db,
+	}
+}
+
+func (dao *GORMCollectionDAO) Insert(ctx context.Context, c Collection) (int64, error) {
+	now := time.Now().UnixMilli()
+	c.Ctime = now
+	c.Utime = now
+	err := dao.db.WithContext(ctx).Create(&c).Error
+	return c.Id, err
+}
+
+func (dao *GORMCollectionDAO) Update(ctx context.Context, c Collection) error {
+	res := dao.db.WithContext(ctx).Model(&Collection{}).
+		Where("id = ? AND uid = ?", c.Id, c.Uid).
+		Updates(map[string]any{
+			"name":    c.Name,
+			"private": c.Private,
+			"utime":   time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrPossibleIncorrectOwner
+	}
+	return nil
+}
+
+func (dao *GORMCollectionDAO) Delete(ctx context.Context, uid, id int64) ([]UserCollectionBiz, error) {
+	var items []UserCollectionBiz
+	now := time.Now().UnixMilli()
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 默认收藏夹不能删除
+		res := tx.Where("id = ? AND uid = ? AND `default` = ?", id, uid, false).
+			Delete(&Collection{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrPossibleIncorrectOwner
+		}
+		err := tx.Where("cid = ?", id).Find(&items).Error
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		err = tx.Where("cid = ?", id).Delete(&UserCollectionBiz{}).Error
+		if err != nil {
+			return err
+		}
+		// 收藏夹里面的每个资源，收藏数都要 -1
+		for _, item := range items {
+			err = tx.Model(&Interactive{}).
+				Where("biz = ? AND biz_id = ? AND collect_cnt > 0", item.Biz, item.BizId).
+				Updates(map[string]any{
+					"collect_cnt": gorm.Expr("`collect_cnt` - 1"),
+					"utime":       now,
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return items, err
+}
+
+func (dao *GORMCollectionDAO) FindById(ctx context.Context, id int64) (Collection, error) {
+	var c Collection
+	err := dao.db.WithContext(ctx).Where("id = ?", id).First(&c).Error
+	return c, err
+}
+
+func (dao *GORMCollectionDAO) FindByUid(ctx context.Context, uid int64) ([]Collection, error) {
+	var cs []Collection
+	err := dao.db.WithContext(ctx).Where("uid = ?", uid).
+		Order("id ASC").Find(&cs).Error
+	return cs, err
+}
+
+func (dao *GORMCollectionDAO) FindOrCreateDefault(ctx context.Context, uid int64) (Collection, error) {
+	c, err := dao.findDefault(ctx, uid)
+	if err != gorm.ErrRecordNotFound {
+		return c, err
+	}
+	// 用户第一次收藏的时候才创建默认收藏夹
+	now := time.Now().UnixMilli()
+	c = Collection{
+		Uid:        uid,
+		Name:       "默认收藏夹",
+		Default:    true,
+		DefaultUid: sql.NullInt64{Int64: uid, Valid: true},
+		Ctime:      now,
+		Utime:      now,
+	}
+	err = dao.db.WithContext(ctx).Create(&c).Error
+	if mysqlErr, ok := err.(*mysql.MySQLError); ok {
+		const uniqueConflictErr uint16 = 1062
+		// 同一个用户并发收藏，别的请求已经建好了
+		if mysqlErr.Number == uniqueConflictErr {
+			return dao.findDefault(ctx, uid)
+		}
+	}
+	return c, err
+}
+
+func (dao *GORMCollectionDAO) findDefault(ctx context.Context, uid int64) (Collection, error) {
+	var c Collection
+	err := dao.db.WithContext(ctx).Where("default_uid = ?", uid).First(&c).Error
+	return c, err
+}
+
+func (dao *GORMCollectionDAO) ListItems(ctx context.Context, cid int64, offset, limit int) ([]UserCollectionBiz, error) {
+	var items []UserCollectionBiz
+	err := dao.db.WithContext(ctx).Where("cid = ?", cid).
+		// 最近收藏的排在前面
+		Order("id DESC").
+		Offset(offset).Limit(limit).
+		Find(&items).Error
+	return items, err
+}
+
+func (dao *GORMCollectionDAO) MoveItem(ctx context.Context, uid int64, biz string, bizId, from, to int64) error {
+	res := dao.db.WithContext(ctx).Model(&UserCollectionBiz{}).
+		Where("uid = ? AND biz = ? AND biz_id = ? AND cid = ?", uid, biz, bizId, from).
+		Updates(map[string]any{
+			"cid":   to,
+			"utime": time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrPossibleIncorrectOwner
+	}
+	return nil
+}
+
+// Collection 收藏夹
+type Collection struct {
+	Id      int64  `gorm:"primaryKey,autoIncrement"`
+	Uid     int64  `gorm:"index"`
+	Name    string `gorm:"type:varchar(128)"`
+	Private bool
+	Default bool
+	// DefaultUid 只有默认收藏夹才有值，唯一索引保证一个用户只有一个默认收藏夹
+	DefaultUid sql.NullInt64 `gorm:"unique"`
+	Ctime      int64
+	Utime      int64
+}
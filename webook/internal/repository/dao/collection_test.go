@@ -0,0 +1,94 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+func TestGORMCollectionDAO_FindOrCreateDefault(t *testing.T) {
+	columns := []string{"id", "uid", "name", "default", "default_uid"}
+	testCases := []struct {
+		name string
+		mock func(t *testing.T) *sql.DB
+
+		wantId  int64
+		wantErr error
+	}{
+		{
+			name: "已经有了",
+			mock: func(t *testing.T) *sql.DB {
+				mockDB, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				mock.ExpectQuery("SELECT \\* FROM `collections` WHERE default_uid = \\?.*").
+					WithArgs(int64(123), 1).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(10, 123, "默认收藏夹", true, 123))
+				return mockDB
+			},
+			wantId: 10,
+		},
+		{
+			name: "第一次收藏，创建",
+			mock: func(t *testing.T) *sql.DB {
+				mockDB, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				mock.ExpectQuery("SELECT \\* FROM `collections` WHERE default_uid = \\?.*").
+					WillReturnRows(sqlmock.NewRows(columns))
+				mock.ExpectExec("INSERT INTO `collections` .*").
+					WillReturnResult(sqlmock.NewResult(11, 1))
+				return mockDB
+			},
+			wantId: 11,
+		},
+		{
+			name: "并发创建，别人先建好了",
+			mock: func(t *testing.T) *sql.DB {
+				mockDB, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				mock.ExpectQuery("SELECT \\* FROM `collections` WHERE default_uid = \\?.*").
+					WillReturnRows(sqlmock.NewRows(columns))
+				mock.ExpectExec("INSERT INTO `collections` .*").
+					WillReturnError(&mysqldriver.MySQLError{Number: 1062})
+				mock.ExpectQuery("SELECT \\* FROM `collections` WHERE default_uid = \\?.*").
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(12, 123, "默认收藏夹", true, 123))
+				return mockDB
+			},
+			wantId: 12,
+		},
+		{
+			name: "数据库错误",
+			mock: func(t *testing.T) *sql.DB {
+				mockDB, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				mock.ExpectQuery("SELECT \\* FROM `collections` WHERE default_uid = \\?.*").
+					WillReturnError(errors.New("mock db error"))
+				return mockDB
+			},
+			wantErr: errors.New("mock db error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      tc.mock(t),
+				SkipInitializeWithVersion: true,
+			}), &gorm.Config{
+				SkipDefaultTransaction: true,
+				DisableAutomaticPing:   true,
+			})
+			require.NoError(t, err)
+			d := NewCollectionDAO(db)
+			c, err := d.FindOrCreateDefault(context.Background(), 123)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantId, c.Id)
+		})
+	}
+}
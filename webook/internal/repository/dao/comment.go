@@ -0,0 +1,101 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrCommentNotFound = gorm.ErrRecordNotFound
+
+const (
+	commentStatusNormal uint8 = iota + 1
+	commentStatusDeleted
+)
+
+type CommentDAO interface {
+	Insert(ctx context.Context, c Comment) (int64, error)
+	FindById(ctx context.Context, id int64) (Comment, error)
+	// FindRoots 根评论按照 id 倒序，maxId 是上一页最后一条的 id，0 表示第一页
+	FindRoots(ctx context.Context, biz string, bizId, maxId int64, limit int) ([]Comment, error)
+	// FindReplies 一条根评论下面的回复按照 id 正序，minId 是上一页最后一条的 id
+	FindReplies(ctx context.Context, rootId, minId int64, limit int) ([]Comment, error)
+	// SoftDelete 只改状态，不删数据，回复还挂在下面
+	SoftDelete(ctx context.Context, id int64) error
+}
+
+type GORMCommentDAO struct {
+	db *gorm.DB
+}
+
+func NewCommentDAO(db *gorm.DB) CommentDAO {
+	return &GORMCommentDAO{
+		db: db,
+	}
+}
+
+func (dao *GORMCommentDAO) Insert(ctx context.Context, c Comment) (int64, error) {
+	now := time.Now().UnixMilli()
+	c.Status = commentStatusNormal
+	c.Ctime = now
+	c.Utime = now
+	err := dao.db.WithContext(ctx).Create(&c).Error
+	return c.Id, err
+}
+
+func (dao *GORMCommentDAO) FindById(ctx context.Context, id int64) (Comment, error) {
+	var c Comment
+	err := dao.db.WithContext(ctx).Where("id = ?", id).First(&c).Error
+	return c, err
+}
+
+func (dao *GORMCommentDAO) FindRoots(ctx context.Context, biz string, bizId, maxId int64, limit int) ([]Comment, error) {
+	db := dao.db.WithContext(ctx).
+		Where("biz = ? AND biz_id = ? AND root_id = 0", biz, bizId)
+	if maxId > 0 {
+		db = db.Where("id < ?", maxId)
+	}
+	var cs []Comment
+	// 最新的评论在前面
+	err := db.Order("id DESC").Limit(limit).Find(&cs).Error
+	return cs, err
+}
+
+func (dao *GORMCommentDAO) FindReplies(ctx context.Context, rootId, minId int64, limit int) ([]Comment, error) {
+	var cs []Comment
+	// 回复按照时间顺序看，才能看懂在聊什么
+	err := dao.db.WithContext(ctx).
+		Where("root_id = ? AND id > ?", rootId, minId).
+		Order("id ASC").Limit(limit).Find(&cs).Error
+	return cs, err
+}
+
+func (dao *GORMCommentDAO) SoftDelete(ctx context.Context, id int64) error {
+	return dao.db.WithContext(ctx).Model(&Comment{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"status": commentStatusDeleted,
+			"utime":  time.Now().UnixMilli(),
+		}).Error
+}
+
+// IsDeleted 软删除的评论
+func (c Comment) IsDeleted() bool {
+	return c.Status == commentStatusDeleted
+}
+
+// Comment 评论，回复也在这张表里面
+type Comment struct {
+	Id  int64 `gorm:"primaryKey,autoIncrement"`
+	Uid int64
+	// 按照资源查根评论
+	Biz      string `gorm:"type:varchar(128);index:biz_type_id_root"`
+	BizId    int64  `gorm:"index:biz_type_id_root"`
+	RootId   int64  `gorm:"index:biz_type_id_root;index"`
+	ParentId int64
+	Content  string `gorm:"type:text"`
+	Status   uint8
+	Ctime    int64
+	Utime    int64
+}
@@ -0,0 +1,107 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrDeadLetterNotFound = gorm.ErrRecordNotFound
+	// ErrDeadLetterStatusChanged 状态已经被别人改了，比如同时重放
+	ErrDeadLetterStatusChanged = errors.New("死信状态已经变了")
+)
+
+type DeadLetterDAO interface {
+	Insert(ctx context.Context, dl DeadLetter) error
+	FindById(ctx context.Context, id int64) (DeadLetter, error)
+	// List 最新的在前面，group 为空就是所有消费者组
+	List(ctx context.Context, group string, offset, limit int) ([]DeadLetter, error)
+	// MarkReplayed 已经重放过了返回 ErrDeadLetterStatusChanged
+	MarkReplayed(ctx context.Context, id int64) error
+	// UnmarkReplayed 重放失败了，改回来
+	UnmarkReplayed(ctx context.Context, id int64) error
+}
+
+const (
+	deadLetterStatusPending uint8 = iota
+	deadLetterStatusReplayed
+)
+
+type GORMDeadLetterDAO struct {
+	db *gorm.DB
+}
+
+func NewDeadLetterDAO(db *gorm.DB) DeadLetterDAO {
+	return &GORMDeadLetterDAO{
+		db: db,
+	}
+}
+
+func (dao *GORMDeadLetterDAO) Insert(ctx context.Context, dl DeadLetter) error {
+	now := time.Now().UnixMilli()
+	dl.Ctime = now
+	dl.Utime = now
+	dl.Status = deadLetterStatusPending
+	return dao.db.WithContext(ctx).Create(&dl).Error
+}
+
+func (dao *GORMDeadLetterDAO) FindById(ctx context.Context, id int64) (DeadLetter, error) {
+	var dl DeadLetter
+	err := dao.db.WithContext(ctx).Where("id = ?", id).First(&dl).Error
+	return dl, err
+}
+
+func (dao *GORMDeadLetterDAO) List(ctx context.Context, group string, offset, limit int) ([]DeadLetter, error) {
+	var dls []DeadLetter
+	db := dao.db.WithContext(ctx)
+	if group != "" {
+		db = db.Where("group_name = ?", group)
+	}
+	err := db.Order("id DESC").Offset(offset).Limit(limit).Find(&dls).Error
+	return dls, err
+}
+
+func (dao *GORMDeadLetterDAO) MarkReplayed(ctx context.Context, id int64) error {
+	return dao.updateStatus(ctx, id, deadLetterStatusPending, deadLetterStatusReplayed)
+}
+
+func (dao *GORMDeadLetterDAO) UnmarkReplayed(ctx context.Context, id int64) error {
+	return dao.updateStatus(ctx, id, deadLetterStatusReplayed, deadLetterStatusPending)
+}
+
+func (dao *GORMDeadLetterDAO) updateStatus(ctx context.Context, id int64, from, to uint8) error {
+	res := dao.db.WithContext(ctx).Model(&DeadLetter{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(map[string]any{
+			"status": to,
+			"utime":  time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrDeadLetterStatusChanged
+	}
+	return nil
+}
+
+// DeadLetter 死信，group 是 MySQL 的关键字，所以叫 group_name
+type DeadLetter struct {
+	Id        int64  `gorm:"primaryKey,autoIncrement"`
+	GroupName string `gorm:"type:varchar(128);index"`
+	Topic     string `gorm:"type:varchar(128)"`
+	Key       []byte `gorm:"type:varbinary(1024)"`
+	Value     []byte `gorm:"type:BLOB"`
+	Err       string `gorm:"type:varchar(1024)"`
+	Status    uint8
+	Ctime     int64
+	Utime     int64
+}
+
+// IsReplayed 已经重放过了
+func (dl DeadLetter) IsReplayed() bool {
+	return dl.Status == deadLetterStatusReplayed
+}
@@ -0,0 +1,77 @@
+package dao
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FeedDAO interface {
+	// InsertPushEvents 写到粉丝的收件箱，重复发表不会重复写
+	InsertPushEvents(ctx context.Context, events []FeedPushEvent) error
+	// InsertPullEvent 写到作者自己的发件箱，读的时候再拉
+	InsertPullEvent(ctx context.Context, event FeedPullEvent) error
+	// FindPushEvents uid 收件箱里排在 (before, beforeId) 后面的，按照 (ctime, biz_id) 倒序
+	FindPushEvents(ctx context.Context, uid, before, beforeId int64, limit int) ([]FeedPushEvent, error)
+	// FindPullEvents 这些作者发件箱里排在 (before, beforeId) 后面的，按照 (ctime, biz_id) 倒序
+	FindPullEvents(ctx context.Context, uids []int64, before, beforeId int64, limit int) ([]FeedPullEvent, error)
+}
+
+type GORMFeedDAO struct {
+	db *gorm.DB
+}
+
+func NewFeedDAO(db *gorm.DB) FeedDAO {
+	return &GORMFeedDAO{
+		db: db,
+	}
+}
+
+func (dao *GORMFeedDAO) InsertPushEvents(ctx context.Context, events []FeedPushEvent) error {
+	return dao.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(events, 500).Error
+}
+
+func (dao *GORMFeedDAO) InsertPullEvent(ctx context.Context, event FeedPullEvent) error {
+	return dao.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&event).Error
+}
+
+// FindPushEvents 同一毫秒可能有好几篇帖子，只按照 ctime 翻页会漏掉，所以游标带上 biz_id
+func (dao *GORMFeedDAO) FindPushEvents(ctx context.Context, uid, before, beforeId int64, limit int) ([]FeedPushEvent, error) {
+	var events []FeedPushEvent
+	err := dao.db.WithContext(ctx).
+		Where("uid = ? AND (ctime < ? OR (ctime = ? AND biz_id < ?))", uid, before, before, beforeId).
+		Order("ctime DESC, biz_id DESC").Limit(limit).
+		Find(&events).Error
+	return events, err
+}
+
+func (dao *GORMFeedDAO) FindPullEvents(ctx context.Context, uids []int64, before, beforeId int64, limit int) ([]FeedPullEvent, error) {
+	var events []FeedPullEvent
+	err := dao.db.WithContext(ctx).
+		Where("uid IN ? AND (ctime < ? OR (ctime = ? AND biz_id < ?))", uids, before, before, beforeId).
+		Order("ctime DESC, biz_id DESC").Limit(limit).
+		Find(&events).Error
+	return events, err
+}
+
+// FeedPushEvent 收件箱，一个粉丝一条
+type FeedPushEvent struct {
+	Id int64 `gorm:"primaryKey,autoIncrement"`
+	// Uid 收件人
+	Uid    int64 `gorm:"uniqueIndex:uid_biz_id;index:uid_ctime,priority:1"`
+	BizId  int64 `gorm:"uniqueIndex:uid_biz_id;index:uid_ctime,priority:3"`
+	Author int64
+	Ctime  int64 `gorm:"index:uid_ctime,priority:2"`
+}
+
+// FeedPullEvent 发件箱，粉丝很多的作者发表的帖子只写这里
+type FeedPullEvent struct {
+	Id int64 `gorm:"primaryKey,autoIncrement"`
+	// Uid 作者
+	Uid   int64 `gorm:"uniqueIndex:uid_biz_id;index:uid_ctime,priority:1"`
+	BizId int64 `gorm:"uniqueIndex:uid_biz_id;index:uid_ctime,priority:3"`
+	Ctime int64 `gorm:"index:uid_ctime,priority:2"`
+}
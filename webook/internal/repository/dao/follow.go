@@ -0,0 +1,181 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrFollowStaticsNotFound = gorm.ErrRecordNotFound
+	// ErrFollowUnchanged 重复关注，或者取消的时候本来就没关注
+	ErrFollowUnchanged = errors.New("关注关系没有变化")
+)
+
+type FollowDAO interface {
+	// Follow 关注，同时更新两个人的计数
+	Follow(ctx context.Context, follower, followee int64) error
+	CancelFollow(ctx context.Context, follower, followee int64) error
+	// ListFollowers 关注 followee 的人，最近关注的在前面
+	ListFollowers(ctx context.Context, followee int64, offset, limit int) ([]FollowRelation, error)
+	// ListFollowees followee 关注的人，最近关注的在前面
+	ListFollowees(ctx context.Context, follower int64, offset, limit int) ([]FollowRelation, error)
+	// FindRelations 查 followers 里面的人有没有关注 followees 里面的人
+	FindRelations(ctx context.Context, followers, followees []int64) ([]FollowRelation, error)
+	GetStatics(ctx context.Context, uid int64) (FollowStatics, error)
+}
+
+type GORMFollowDAO struct {
+	db *gorm.DB
+}
+
+func NewFollowDAO(db *gorm.DB) FollowDAO {
+	return &GORMFollowDAO{
+		db: db,
+	}
+}
+
+func (dao *GORMFollowDAO) Follow(ctx context.Context, follower, followee int64) error {
+	now := time.Now().UnixMilli()
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 取消过关注的话，记录还在，只是 status 是 0
+		res := tx.Model(&FollowRelation{}).
+			Where("follower = ? AND followee = ? AND status = ?", follower, followee, 0).
+			Updates(map[string]any{
+				"status": 1,
+				"utime":  now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			res = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&FollowRelation{
+				Follower: follower,
+				Followee: followee,
+				Status:   1,
+				Ctime:    now,
+				Utime:    now,
+			})
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				return ErrFollowUnchanged
+			}
+		}
+		return dao.updateStatics(tx, follower, followee, 1, now)
+	})
+}
+
+func (dao *GORMFollowDAO) CancelFollow(ctx context.Context, follower, followee int64) error {
+	now := time.Now().UnixMilli()
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&FollowRelation{}).
+			Where("follower = ? AND followee = ? AND status = ?", follower, followee, 1).
+			Updates(map[string]any{
+				"status": 0,
+				"utime":  now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrFollowUnchanged
+		}
+		return dao.updateStatics(tx, follower, followee, -1, now)
+	})
+}
+
+// updateStatics 按照 uid 从小到大更新，互相关注同时发生的时候不会死锁
+func (dao *GORMFollowDAO) updateStatics(tx *gorm.DB, follower, followee int64, delta int, now int64) error {
+	type update struct {
+		uid   int64
+		field string
+	}
+	updates := []update{{uid: follower, field: "followees"}, {uid: followee, field: "followers"}}
+	sort.Slice(updates, func(i, j int) bool {
+		return updates[i].uid < updates[j].uid
+	})
+	for _, u := range updates {
+		s := FollowStatics{Uid: u.uid, Ctime: now, Utime: now}
+		if delta > 0 {
+			if u.field == "followers" {
+				s.Followers = 1
+			} else {
+				s.Followees = 1
+			}
+		}
+		err := tx.Clauses(clause.OnConflict{
+			DoUpdates: clause.Assignments(map[string]any{
+				// 不会减成负数
+				u.field: gorm.Expr("GREATEST(`"+u.field+"` + ?, 0)", delta),
+				"utime": now,
+			}),
+		}).Create(&s).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (dao *GORMFollowDAO) ListFollowers(ctx context.Context, followee int64, offset, limit int) ([]FollowRelation, error) {
+	var rs []FollowRelation
+	err := dao.db.WithContext(ctx).
+		Where("followee = ? AND status = ?", followee, 1).
+		Order("utime DESC").
+		Offset(offset).Limit(limit).
+		Find(&rs).Error
+	return rs, err
+}
+
+func (dao *GORMFollowDAO) ListFollowees(ctx context.Context, follower int64, offset, limit int) ([]FollowRelation, error) {
+	var rs []FollowRelation
+	err := dao.db.WithContext(ctx).
+		Where("follower = ? AND status = ?", follower, 1).
+		Order("utime DESC").
+		Offset(offset).Limit(limit).
+		Find(&rs).Error
+	return rs, err
+}
+
+func (dao *GORMFollowDAO) FindRelations(ctx context.Context, followers, followees []int64) ([]FollowRelation, error) {
+	var rs []FollowRelation
+	err := dao.db.WithContext(ctx).
+		Where("follower IN ? AND followee IN ? AND status = ?", followers, followees, 1).
+		Find(&rs).Error
+	return rs, err
+}
+
+func (dao *GORMFollowDAO) GetStatics(ctx context.Context, uid int64) (FollowStatics, error) {
+	var s FollowStatics
+	err := dao.db.WithContext(ctx).Where("uid = ?", uid).First(&s).Error
+	return s, err
+}
+
+// FollowRelation 关注关系，取消关注只是把 status 改成 0
+type FollowRelation struct {
+	Id       int64 `gorm:"primaryKey,autoIncrement"`
+	Follower int64 `gorm:"uniqueIndex:follower_followee"`
+	// 查粉丝列表
+	Followee int64 `gorm:"uniqueIndex:follower_followee;index"`
+	// 1 是关注中，0 是取消了
+	Status uint8
+	Ctime  int64
+	// Utime 最近一次关注或者取消关注的时间
+	Utime int64
+}
+
+// FollowStatics 关注数和粉丝数，跟关注关系在同一个事务里面更新
+type FollowStatics struct {
+	Id        int64 `gorm:"primaryKey,autoIncrement"`
+	Uid       int64 `gorm:"unique"`
+	Followers int64
+	Followees int64
+	Ctime     int64
+	Utime     int64
+}
@@ -3,5 +3,10 @@ package dao
 import "gorm.io/gorm"
 
 func InitTables(db *gorm.DB) error {
-	return db.AutoMigrate(&User{})
+	return db.AutoMigrate(&User{}, &Article{}, &PublishedArticle{}, &ArticleRevision{},
+		&Interactive{}, &UserLikeBiz{}, &UserCollectionBiz{}, &Collection{}, &Job{},
+		&Reward{}, &Reconciliation{},
+		&Account{}, &AccountEntry{}, &Comment{}, &FollowRelation{}, &FollowStatics{},
+		&FeedPushEvent{}, &FeedPullEvent{}, &OutboxMessage{}, &DeadLetter{},
+		&AsyncSMS{})
 }
@@ -0,0 +1,278 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrRecordNotFound = gorm.ErrRecordNotFound
+
+// ErrInteractiveUnchanged 重复点赞、重复取消点赞、重复收藏，计数没有变化
+// 上层拿到这个错误就不需要更新缓存了
+var ErrInteractiveUnchanged = errors.New("交互数据没有变化")
+
+type InteractiveDAO interface {
+	IncrReadCnt(ctx context.Context, biz string, bizId int64) error
+	// BatchIncrReadCnt 一条语句批量增加阅读数，key 是 bizId，value 是增加的数量
+	BatchIncrReadCnt(ctx context.Context, biz string, cnts map[int64]int64) error
+	InsertLikeInfo(ctx context.Context, biz string, bizId, uid int64) error
+	DeleteLikeInfo(ctx context.Context, biz string, bizId, uid int64) error
+	InsertCollectionBiz(ctx context.Context, cb UserCollectionBiz) error
+	DeleteCollectionBiz(ctx context.Context, biz string, bizId, uid int64) error
+	GetLikeInfo(ctx context.Context, biz string, bizId, uid int64) (UserLikeBiz, error)
+	GetCollectionInfo(ctx context.Context, biz string, bizId, uid int64) (UserCollectionBiz, error)
+	Get(ctx context.Context, biz string, bizId int64) (Interactive, error)
+	GetByIds(ctx context.Context, biz string, ids []int64) ([]Interactive, error)
+}
+
+type GORMInteractiveDAO struct {
+	db *gorm.DB
+}
+
+func NewInteractiveDAO(db *gorm.DB) InteractiveDAO {
+	return &GORMInteractiveDAO{
+		db: db,
+	}
+}
+
+func (dao *GORMInteractiveDAO) IncrReadCnt(ctx context.Context, biz string, bizId int64) error {
+	now := time.Now().UnixMilli()
+	// 第一次阅读的时候还没有这条记录，所以用 upsert
+	// 并发安全依赖于 MySQL 的 read_cnt = read_cnt + 1
+	return dao.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{
+			"read_cnt": gorm.Expr("`read_cnt` + 1"),
+			"utime":    now,
+		}),
+	}).Create(&Interactive{
+		Biz:     biz,
+		BizId:   bizId,
+		ReadCnt: 1,
+		Ctime:   now,
+		Utime:   now,
+	}).Error
+}
+
+func (dao *GORMInteractiveDAO) BatchIncrReadCnt(ctx context.Context, biz string, cnts map[int64]int64) error {
+	if len(cnts) == 0 {
+		return nil
+	}
+	now := time.Now().UnixMilli()
+	intrs := make([]Interactive, 0, len(cnts))
+	for bizId, cnt := range cnts {
+		intrs = append(intrs, Interactive{
+			Biz:     biz,
+			BizId:   bizId,
+			ReadCnt: cnt,
+			Ctime:   now,
+			Utime:   now,
+		})
+	}
+	// 按照 id 排序，并发批量更新的时候加锁顺序一致，不会死锁
+	sort.Slice(intrs, func(i, j int) bool {
+		return intrs[i].BizId < intrs[j].BizId
+	})
+	return dao.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{
+			"read_cnt": gorm.Expr("`read_cnt` + VALUES(`read_cnt`)"),
+			"utime":    now,
+		}),
+	}).Create(&intrs).Error
+}
+
+// InsertLikeInfo 插入点赞记录，并且点赞数 +1，在同一个事务里面
+func (dao *GORMInteractiveDAO) InsertLikeInfo(ctx context.Context, biz string, bizId, uid int64) error {
+	now := time.Now().UnixMilli()
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 取消过点赞的话，记录还在，只是 status 是 0
+		res := tx.Model(&UserLikeBiz{}).
+			Where("uid = ? AND biz = ? AND biz_id = ? AND status = ?", uid, biz, bizId, 0).
+			Updates(map[string]any{
+				"status": 1,
+				"utime":  now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			// 要么从来没点过赞，要么已经点过赞了
+			res = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&UserLikeBiz{
+				Uid:    uid,
+				Biz:    biz,
+				BizId:  bizId,
+				Status: 1,
+				Ctime:  now,
+				Utime:  now,
+			})
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				// 已经点过赞了，重复点赞不能重复计数
+				return ErrInteractiveUnchanged
+			}
+		}
+		return tx.Clauses(clause.OnConflict{
+			DoUpdates: clause.Assignments(map[string]any{
+				"like_cnt": gorm.Expr("`like_cnt` + 1"),
+				"utime":    now,
+			}),
+		}).Create(&Interactive{
+			Biz:     biz,
+			BizId:   bizId,
+			LikeCnt: 1,
+			Ctime:   now,
+			Utime:   now,
+		}).Error
+	})
+}
+
+// DeleteLikeInfo 软删除点赞记录，并且点赞数 -1
+func (dao *GORMInteractiveDAO) DeleteLikeInfo(ctx context.Context, biz string, bizId, uid int64) error {
+	now := time.Now().UnixMilli()
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&UserLikeBiz{}).
+			Where("uid = ? AND biz = ? AND biz_id = ? AND status = ?", uid, biz, bizId, 1).
+			Updates(map[string]any{
+				"status": 0,
+				"utime":  now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			// 本来就没有点赞
+			return ErrInteractiveUnchanged
+		}
+		return tx.Model(&Interactive{}).
+			Where("biz = ? AND biz_id = ? AND like_cnt > 0", biz, bizId).
+			Updates(map[string]any{
+				"like_cnt": gorm.Expr("`like_cnt` - 1"),
+				"utime":    now,
+			}).Error
+	})
+}
+
+// InsertCollectionBiz 插入收藏记录，并且收藏数 +1
+func (dao *GORMInteractiveDAO) InsertCollectionBiz(ctx context.Context, cb UserCollectionBiz) error {
+	now := time.Now().UnixMilli()
+	cb.Ctime = now
+	cb.Utime = now
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&cb)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			// 已经收藏过了
+			return ErrInteractiveUnchanged
+		}
+		return tx.Clauses(clause.OnConflict{
+			DoUpdates: clause.Assignments(map[string]any{
+				"collect_cnt": gorm.Expr("`collect_cnt` + 1"),
+				"utime":       now,
+			}),
+		}).Create(&Interactive{
+			Biz:        cb.Biz,
+			BizId:      cb.BizId,
+			CollectCnt: 1,
+			Ctime:      now,
+			Utime:      now,
+		}).Error
+	})
+}
+
+// DeleteCollectionBiz 取消收藏，删除收藏记录，并且收藏数 -1
+func (dao *GORMInteractiveDAO) DeleteCollectionBiz(ctx context.Context, biz string, bizId, uid int64) error {
+	now := time.Now().UnixMilli()
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("uid = ? AND biz = ? AND biz_id = ?", uid, biz, bizId).
+			Delete(&UserCollectionBiz{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrInteractiveUnchanged
+		}
+		return tx.Model(&Interactive{}).
+			Where("biz = ? AND biz_id = ? AND collect_cnt > 0", biz, bizId).
+			Updates(map[string]any{
+				"collect_cnt": gorm.Expr("`collect_cnt` - 1"),
+				"utime":       now,
+			}).Error
+	})
+}
+
+func (dao *GORMInteractiveDAO) GetLikeInfo(ctx context.Context, biz string, bizId, uid int64) (UserLikeBiz, error) {
+	var res UserLikeBiz
+	err := dao.db.WithContext(ctx).
+		Where("uid = ? AND biz = ? AND biz_id = ? AND status = ?", uid, biz, bizId, 1).
+		First(&res).Error
+	return res, err
+}
+
+func (dao *GORMInteractiveDAO) GetCollectionInfo(ctx context.Context, biz string, bizId, uid int64) (UserCollectionBiz, error) {
+	var res UserCollectionBiz
+	err := dao.db.WithContext(ctx).
+		Where("uid = ? AND biz = ? AND biz_id = ?", uid, biz, bizId).
+		First(&res).Error
+	return res, err
+}
+
+func (dao *GORMInteractiveDAO) Get(ctx context.Context, biz string, bizId int64) (Interactive, error) {
+	var res Interactive
+	err := dao.db.WithContext(ctx).
+		Where("biz = ? AND biz_id = ?", biz, bizId).
+		First(&res).Error
+	return res, err
+}
+
+func (dao *GORMInteractiveDAO) GetByIds(ctx context.Context, biz string, ids []int64) ([]Interactive, error) {
+	var res []Interactive
+	err := dao.db.WithContext(ctx).
+		Where("biz = ? AND biz_id IN ?", biz, ids).
+		Find(&res).Error
+	return res, err
+}
+
+// Interactive 计数，一个资源一条记录
+type Interactive struct {
+	Id int64 `gorm:"primaryKey,autoIncrement"`
+	// <biz, biz_id> 联合唯一索引
+	BizId      int64  `gorm:"uniqueIndex:biz_type_id"`
+	Biz        string `gorm:"type:varchar(128);uniqueIndex:biz_type_id"`
+	ReadCnt    int64
+	LikeCnt    int64
+	CollectCnt int64
+	Ctime      int64
+	Utime      int64
+}
+
+// UserLikeBiz 用户点赞记录
+type UserLikeBiz struct {
+	Id    int64  `gorm:"primaryKey,autoIncrement"`
+	Uid   int64  `gorm:"uniqueIndex:uid_biz_type_id"`
+	BizId int64  `gorm:"uniqueIndex:uid_biz_type_id"`
+	Biz   string `gorm:"type:varchar(128);uniqueIndex:uid_biz_type_id"`
+	// 1 是点赞，0 是取消点赞，软删除
+	Status uint8
+	Ctime  int64
+	Utime  int64
+}
+
+// UserCollectionBiz 用户收藏记录
+type UserCollectionBiz struct {
+	Id    int64  `gorm:"primaryKey,autoIncrement"`
+	Uid   int64  `gorm:"uniqueIndex:uid_biz_type_id"`
+	BizId int64  `gorm:"uniqueIndex:uid_biz_type_id"`
+	Biz   string `gorm:"type:varchar(128);uniqueIndex:uid_biz_type_id"`
+	// 收藏夹 id
+	Cid   int64 `gorm:"index"`
+	Ctime int64
+	Utime int64
+}
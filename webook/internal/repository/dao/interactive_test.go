@@ -0,0 +1,75 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+func TestGORMInteractiveDAO_BatchIncrReadCnt(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(t *testing.T) *sql.DB
+		cnts map[int64]int64
+
+		wantErr error
+	}{
+		{
+			name: "一条语句批量更新",
+			mock: func(t *testing.T) *sql.DB {
+				mockDB, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				// 按照 biz_id 排好序
+				mock.ExpectExec("INSERT INTO `interactives` .* ON DUPLICATE KEY UPDATE .*`read_cnt` \\+ VALUES\\(`read_cnt`\\).*").
+					WithArgs(int64(1), "article", int64(3), int64(0), int64(0), sqlmock.AnyArg(), sqlmock.AnyArg(),
+						int64(2), "article", int64(1), int64(0), int64(0), sqlmock.AnyArg(), sqlmock.AnyArg(),
+						sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 3))
+				return mockDB
+			},
+			cnts: map[int64]int64{2: 1, 1: 3},
+		},
+		{
+			name: "没有数据",
+			mock: func(t *testing.T) *sql.DB {
+				mockDB, _, err := sqlmock.New()
+				require.NoError(t, err)
+				return mockDB
+			},
+		},
+		{
+			name: "数据库错误",
+			mock: func(t *testing.T) *sql.DB {
+				mockDB, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				mock.ExpectExec("INSERT INTO `interactives` .*").
+					WillReturnError(errors.New("mock db error"))
+				return mockDB
+			},
+			cnts:    map[int64]int64{1: 1},
+			wantErr: errors.New("mock db error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      tc.mock(t),
+				SkipInitializeWithVersion: true,
+			}), &gorm.Config{
+				SkipDefaultTransaction: true,
+				DisableAutomaticPing:   true,
+			})
+			require.NoError(t, err)
+			d := NewInteractiveDAO(db)
+			err = d.BatchIncrReadCnt(context.Background(), "article", tc.cnts)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
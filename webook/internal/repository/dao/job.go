@@ -0,0 +1,196 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrNoMoreJob 没有可以抢占的任务
+	ErrNoMoreJob = errors.New("没有可以抢占的任务")
+	// ErrJobNotOwned 任务已经不属于这个实例了，比如心跳超时被别人抢走
+	ErrJobNotOwned = errors.New("任务不属于当前实例")
+	ErrJobNotFound = gorm.ErrRecordNotFound
+)
+
+// 和 domain.JobStatus 保持一致
+const (
+	jobStatusWaiting uint8 = iota + 1
+	jobStatusRunning
+	jobStatusPaused
+)
+
+type JobDAO interface {
+	// Preempt 抢占一个到期的任务，或者心跳已经超过 timeout 的任务
+	Preempt(ctx context.Context, owner string, timeout time.Duration) (Job, error)
+	// Heartbeat 续约，version 是抢占时拿到的版本，任务被重新抢占、暂停过的话返回 ErrJobNotOwned
+	Heartbeat(ctx context.Context, id int64, owner string, version int64) error
+	// Release 释放任务，同时设置下一次执行时间，和 Heartbeat 一样要校验 version
+	Release(ctx context.Context, id int64, owner string, version int64, nextTime int64) error
+	// InsertIfNotExist 按照名字判断，已经有了就不会覆盖
+	InsertIfNotExist(ctx context.Context, j Job) error
+	List(ctx context.Context, offset, limit int) ([]Job, error)
+	GetById(ctx context.Context, id int64) (Job, error)
+	Pause(ctx context.Context, id int64) error
+	Resume(ctx context.Context, id int64, nextTime int64) error
+}
+
+type GORMJobDAO struct {
+	db *gorm.DB
+}
+
+func NewJobDAO(db *gorm.DB) JobDAO {
+	return &GORMJobDAO{
+		db: db,
+	}
+}
+
+func (dao *GORMJobDAO) Preempt(ctx context.Context, owner string, timeout time.Duration) (Job, error) {
+	db := dao.db.WithContext(ctx)
+	for {
+		now := time.Now().UnixMilli()
+		var j Job
+		// 到期的任务，或者持有任务的实例很久没有续约了，多半是挂了
+		err := db.Where("(status = ? AND next_time <= ?) OR (status = ? AND utime <= ?)",
+			jobStatusWaiting, now, jobStatusRunning, now-timeout.Milliseconds()).
+			First(&j).Error
+		if err == gorm.ErrRecordNotFound {
+			return Job{}, ErrNoMoreJob
+		}
+		if err != nil {
+			return Job{}, err
+		}
+		// 乐观锁，version 变了说明被别人抢先了
+		res := db.Model(&Job{}).
+			Where("id = ? AND version = ?", j.Id, j.Version).
+			Updates(map[string]any{
+				"status":  jobStatusRunning,
+				"owner":   owner,
+				"version": j.Version + 1,
+				"utime":   now,
+			})
+		if res.Error != nil {
+			return Job{}, res.Error
+		}
+		if res.RowsAffected == 0 {
+			continue
+		}
+		j.Status = jobStatusRunning
+		j.Owner = owner
+		j.Version++
+		j.Utime = now
+		return j, nil
+	}
+}
+
+func (dao *GORMJobDAO) Heartbeat(ctx context.Context, id int64, owner string, version int64) error {
+	// 同一个进程 owner 是一样的，只能靠 version 区分是不是这一次抢占
+	res := dao.db.WithContext(ctx).Model(&Job{}).
+		Where("id = ? AND owner = ? AND status = ? AND version = ?", id, owner, jobStatusRunning, version).
+		Update("utime", time.Now().UnixMilli())
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrJobNotOwned
+	}
+	return nil
+}
+
+func (dao *GORMJobDAO) Release(ctx context.Context, id int64, owner string, version int64, nextTime int64) error {
+	res := dao.db.WithContext(ctx).Model(&Job{}).
+		Where("id = ? AND owner = ? AND status = ? AND version = ?", id, owner, jobStatusRunning, version).
+		Updates(map[string]any{
+			"status":    jobStatusWaiting,
+			"next_time": nextTime,
+			"utime":     time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrJobNotOwned
+	}
+	return nil
+}
+
+func (dao *GORMJobDAO) InsertIfNotExist(ctx context.Context, j Job) error {
+	now := time.Now().UnixMilli()
+	j.Status = jobStatusWaiting
+	j.Ctime = now
+	j.Utime = now
+	return dao.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoNothing: true,
+	}).Create(&j).Error
+}
+
+func (dao *GORMJobDAO) List(ctx context.Context, offset, limit int) ([]Job, error) {
+	var js []Job
+	err := dao.db.WithContext(ctx).Order("id ASC").
+		Offset(offset).Limit(limit).Find(&js).Error
+	return js, err
+}
+
+func (dao *GORMJobDAO) GetById(ctx context.Context, id int64) (Job, error) {
+	var j Job
+	err := dao.db.WithContext(ctx).Where("id = ?", id).First(&j).Error
+	return j, err
+}
+
+func (dao *GORMJobDAO) Pause(ctx context.Context, id int64) error {
+	// 正在执行的任务也可以暂停，释放的时候 version 对不上，就不会被改回等待状态
+	res := dao.db.WithContext(ctx).Model(&Job{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"status":  jobStatusPaused,
+			"owner":   "",
+			"version": gorm.Expr("`version` + 1"),
+			"utime":   time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrJobNotFound
+	}
+	return nil
+}
+
+func (dao *GORMJobDAO) Resume(ctx context.Context, id int64, nextTime int64) error {
+	res := dao.db.WithContext(ctx).Model(&Job{}).
+		Where("id = ? AND status = ?", id, jobStatusPaused).
+		Updates(map[string]any{
+			"status":    jobStatusWaiting,
+			"next_time": nextTime,
+			"version":   gorm.Expr("`version` + 1"),
+			"utime":     time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrJobNotFound
+	}
+	return nil
+}
+
+// Job 抢占式调度的任务
+type Job struct {
+	Id         int64  `gorm:"primaryKey,autoIncrement"`
+	Name       string `gorm:"type:varchar(128);unique"`
+	Executor   string `gorm:"type:varchar(128)"`
+	Cfg        string `gorm:"type:varchar(4096)"`
+	Expression string `gorm:"type:varchar(128)"`
+	// 抢占的时候按照状态和下一次执行时间查询
+	Status   uint8 `gorm:"index:status_next_time"`
+	NextTime int64 `gorm:"index:status_next_time"`
+	Version  int64
+	Owner    string `gorm:"type:varchar(128)"`
+	Ctime    int64
+	// Utime 兼做心跳时间
+	Utime int64
+}
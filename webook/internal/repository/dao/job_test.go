@@ -0,0 +1,184 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+func TestGORMJobDAO_Preempt(t *testing.T) {
+	columns := []string{"id", "name", "executor", "status", "next_time", "version", "owner"}
+	testCases := []struct {
+		name string
+		mock func(t *testing.T) *sql.DB
+
+		wantJob Job
+		wantErr error
+	}{
+		{
+			name: "抢占成功",
+			mock: func(t *testing.T) *sql.DB {
+				mockDB, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				mock.ExpectQuery("SELECT \\* FROM `jobs` WHERE .*").
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(1, "ranking", "local", jobStatusWaiting, 100, 3, ""))
+				mock.ExpectExec("UPDATE `jobs` SET .* WHERE id = \\? AND version = \\?").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				return mockDB
+			},
+			wantJob: Job{Id: 1, Name: "ranking", Executor: "local",
+				Status: jobStatusRunning, NextTime: 100, Version: 4, Owner: "instance-1"},
+		},
+		{
+			name: "被别人抢先了，换下一个",
+			mock: func(t *testing.T) *sql.DB {
+				mockDB, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				mock.ExpectQuery("SELECT \\* FROM `jobs` WHERE .*").
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(1, "ranking", "local", jobStatusWaiting, 100, 3, ""))
+				mock.ExpectExec("UPDATE `jobs` SET .* WHERE id = \\? AND version = \\?").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT \\* FROM `jobs` WHERE .*").
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(2, "cleanup", "local", jobStatusRunning, 100, 7, "instance-2"))
+				mock.ExpectExec("UPDATE `jobs` SET .* WHERE id = \\? AND version = \\?").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				return mockDB
+			},
+			wantJob: Job{Id: 2, Name: "cleanup", Executor: "local",
+				Status: jobStatusRunning, NextTime: 100, Version: 8, Owner: "instance-1"},
+		},
+		{
+			name: "没有到期的任务",
+			mock: func(t *testing.T) *sql.DB {
+				mockDB, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				mock.ExpectQuery("SELECT \\* FROM `jobs` WHERE .*").
+					WillReturnRows(sqlmock.NewRows(columns))
+				return mockDB
+			},
+			wantErr: ErrNoMoreJob,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      tc.mock(t),
+				SkipInitializeWithVersion: true,
+			}), &gorm.Config{
+				SkipDefaultTransaction: true,
+				DisableAutomaticPing:   true,
+			})
+			require.NoError(t, err)
+			d := NewJobDAO(db)
+			j, err := d.Preempt(context.Background(), "instance-1", time.Minute)
+			assert.Equal(t, tc.wantErr, err)
+			// utime 是抢占的时间，不好断言
+			j.Utime = 0
+			assert.Equal(t, tc.wantJob, j)
+		})
+	}
+}
+
+func TestGORMJobDAO_Heartbeat(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(t *testing.T) *sql.DB
+
+		wantErr error
+	}{
+		{
+			name: "续约成功",
+			mock: func(t *testing.T) *sql.DB {
+				mockDB, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				mock.ExpectExec("UPDATE `jobs` SET .* WHERE id = \\? AND owner = \\? AND status = \\? AND version = \\?").
+					WithArgs(sqlmock.AnyArg(), int64(1), "instance-1", jobStatusRunning, int64(4)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				return mockDB
+			},
+		},
+		{
+			name: "同一个进程里面被重新抢占了",
+			mock: func(t *testing.T) *sql.DB {
+				mockDB, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				mock.ExpectExec("UPDATE `jobs` SET .* WHERE id = \\? AND owner = \\? AND status = \\? AND version = \\?").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				return mockDB
+			},
+			wantErr: ErrJobNotOwned,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      tc.mock(t),
+				SkipInitializeWithVersion: true,
+			}), &gorm.Config{
+				SkipDefaultTransaction: true,
+				DisableAutomaticPing:   true,
+			})
+			require.NoError(t, err)
+			d := NewJobDAO(db)
+			err = d.Heartbeat(context.Background(), 1, "instance-1", 4)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestGORMJobDAO_Release(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(t *testing.T) *sql.DB
+
+		wantErr error
+	}{
+		{
+			name: "释放成功",
+			mock: func(t *testing.T) *sql.DB {
+				mockDB, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				mock.ExpectExec("UPDATE `jobs` SET .* WHERE id = \\? AND owner = \\? AND status = \\? AND version = \\?").
+					WithArgs(int64(200), jobStatusWaiting, sqlmock.AnyArg(), int64(1), "instance-1", jobStatusRunning, int64(4)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				return mockDB
+			},
+		},
+		{
+			name: "已经被新的一次抢占了，不能释放",
+			mock: func(t *testing.T) *sql.DB {
+				mockDB, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				mock.ExpectExec("UPDATE `jobs` SET .* WHERE id = \\? AND owner = \\? AND status = \\? AND version = \\?").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				return mockDB
+			},
+			wantErr: ErrJobNotOwned,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, err := gorm.Open(mysql.New(mysql.Config{
+				Conn:                      tc.mock(t),
+				SkipInitializeWithVersion: true,
+			}), &gorm.Config{
+				SkipDefaultTransaction: true,
+				DisableAutomaticPing:   true,
+			})
+			require.NoError(t, err)
+			d := NewJobDAO(db)
+			err = d.Release(context.Background(), 1, "instance-1", 4, 200)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package dao
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type OutboxDAO interface {
+	// FindPending 找出到了发送时间还没发出去的消息，按照 id 排序
+	FindPending(ctx context.Context, now int64, limit int) ([]OutboxMessage, error)
+	// Delete 发出去了就删掉，发件箱不会一直变大
+	Delete(ctx context.Context, id int64) error
+	// MarkRetry 发送失败，nextTime 之后再试
+	MarkRetry(ctx context.Context, id int64, nextTime int64) error
+}
+
+type GORMOutboxDAO struct {
+	db *gorm.DB
+}
+
+func NewOutboxDAO(db *gorm.DB) OutboxDAO {
+	return &GORMOutboxDAO{
+		db: db,
+	}
+}
+
+func (dao *GORMOutboxDAO) FindPending(ctx context.Context, now int64, limit int) ([]OutboxMessage, error) {
+	var msgs []OutboxMessage
+	err := dao.db.WithContext(ctx).
+		Where("next_time <= ?", now).
+		Order("id ASC").
+		Limit(limit).
+		Find(&msgs).Error
+	return msgs, err
+}
+
+func (dao *GORMOutboxDAO) Delete(ctx context.Context, id int64) error {
+	return dao.db.WithContext(ctx).Where("id = ?", id).Delete(&OutboxMessage{}).Error
+}
+
+func (dao *GORMOutboxDAO) MarkRetry(ctx context.Context, id int64, nextTime int64) error {
+	return dao.db.WithContext(ctx).Model(&OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"retries":   gorm.Expr("`retries` + 1"),
+			"next_time": nextTime,
+			"utime":     time.Now().UnixMilli(),
+		}).Error
+}
+
+// insertOutbox 在业务的事务里面写发件箱，业务提交了消息就一定在
+func insertOutbox(tx *gorm.DB, topic string, key int64, evt any) error {
+	val, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UnixMilli()
+	return tx.Create(&OutboxMessage{
+		Topic:    topic,
+		Key:      strconv.FormatInt(key, 10),
+		Value:    val,
+		NextTime: now,
+		Ctime:    now,
+		Utime:    now,
+	}).Error
+}
+
+// OutboxMessage 发件箱，由 relay 转发到消息队列，表里只有还没发出去的
+type OutboxMessage struct {
+	Id    int64  `gorm:"primaryKey,autoIncrement"`
+	Topic string `gorm:"type:varchar(128)"`
+	Key   string `gorm:"type:varchar(128)"`
+	Value []byte `gorm:"type:BLOB"`
+	// relay 按照下次发送时间扫描
+	NextTime int64 `gorm:"index"`
+	Retries  int
+	Ctime    int64
+	Utime    int64
+}
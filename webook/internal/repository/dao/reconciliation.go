@@ -0,0 +1,57 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ReconciliationDAO interface {
+	Insert(ctx context.Context, r Reconciliation) error
+	// Summary 按照对账结果汇总 [start, end) 之间的对账记录
+	Summary(ctx context.Context, start, end int64) ([]ReconciliationSummary, error)
+}
+
+type GORMReconciliationDAO struct {
+	db *gorm.DB
+}
+
+func NewReconciliationDAO(db *gorm.DB) ReconciliationDAO {
+	return &GORMReconciliationDAO{
+		db: db,
+	}
+}
+
+func (dao *GORMReconciliationDAO) Insert(ctx context.Context, r Reconciliation) error {
+	r.Ctime = time.Now().UnixMilli()
+	return dao.db.WithContext(ctx).Create(&r).Error
+}
+
+func (dao *GORMReconciliationDAO) Summary(ctx context.Context, start, end int64) ([]ReconciliationSummary, error) {
+	var res []ReconciliationSummary
+	err := dao.db.WithContext(ctx).Model(&Reconciliation{}).
+		Select("result, COUNT(*) AS cnt, SUM(amount) AS amt").
+		Where("ctime >= ? AND ctime < ?", start, end).
+		Group("result").
+		Scan(&res).Error
+	return res, err
+}
+
+// Reconciliation 对账记录，只插入不修改
+type Reconciliation struct {
+	Id         int64  `gorm:"primaryKey,autoIncrement"`
+	Biz        string `gorm:"type:varchar(128)"`
+	BizId      int64
+	BizTradeNo string `gorm:"type:varchar(256);index"`
+	Amount     int64
+	Result     uint8
+	Err        string `gorm:"type:varchar(1024)"`
+	Ctime      int64  `gorm:"index"`
+}
+
+type ReconciliationSummary struct {
+	Result uint8
+	Cnt    int64
+	Amt    int64
+}
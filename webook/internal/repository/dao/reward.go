@@ -0,0 +1,78 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrRewardNotFound = gorm.ErrRecordNotFound
+
+type RewardDAO interface {
+	Insert(ctx context.Context, r Reward) (int64, error)
+	GetById(ctx context.Context, id int64) (Reward, error)
+	// UpdateStatus 只有当前状态是 from 的时候才更新，已经有结果的订单不会被覆盖
+	UpdateStatus(ctx context.Context, id int64, from, to uint8) error
+	// FindByStatus 按照 id 从小到大，查询 ctime 在 before 之前、状态是 status 的订单
+	FindByStatus(ctx context.Context, status uint8, before int64, lastId int64, limit int) ([]Reward, error)
+}
+
+type GORMRewardDAO struct {
+	db *gorm.DB
+}
+
+func NewRewardDAO(db *gorm.DB) RewardDAO {
+	return &GORMRewardDAO{
+		db: db,
+	}
+}
+
+func (dao *GORMRewardDAO) Insert(ctx context.Context, r Reward) (int64, error) {
+	now := time.Now().UnixMilli()
+	r.Ctime = now
+	r.Utime = now
+	err := dao.db.WithContext(ctx).Create(&r).Error
+	return r.Id, err
+}
+
+func (dao *GORMRewardDAO) GetById(ctx context.Context, id int64) (Reward, error) {
+	var r Reward
+	err := dao.db.WithContext(ctx).Where("id = ?", id).First(&r).Error
+	return r, err
+}
+
+func (dao *GORMRewardDAO) UpdateStatus(ctx context.Context, id int64, from, to uint8) error {
+	return dao.db.WithContext(ctx).Model(&Reward{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(map[string]any{
+			"status": to,
+			"utime":  time.Now().UnixMilli(),
+		}).Error
+}
+
+func (dao *GORMRewardDAO) FindByStatus(ctx context.Context, status uint8, before int64, lastId int64, limit int) ([]Reward, error) {
+	var rs []Reward
+	err := dao.db.WithContext(ctx).
+		Where("status = ? AND ctime < ? AND id > ?", status, before, lastId).
+		Order("id ASC").
+		Limit(limit).
+		Find(&rs).Error
+	return rs, err
+}
+
+// Reward 打赏订单
+type Reward struct {
+	Id      int64  `gorm:"primaryKey,autoIncrement"`
+	Biz     string `gorm:"type:varchar(128);index:biz_biz_id"`
+	BizId   int64  `gorm:"index:biz_biz_id"`
+	BizName string `gorm:"type:varchar(1024)"`
+	// TargetUid 收钱的人
+	TargetUid int64 `gorm:"index"`
+	// Uid 打赏的人
+	Uid    int64 `gorm:"index"`
+	Amount int64
+	Status uint8
+	Ctime  int64
+	Utime  int64
+}
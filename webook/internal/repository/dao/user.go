@@ -3,6 +3,7 @@ package dao
 import (
 	"context"
 	"database/sql"
+	"dream/webook/internal/events"
 	"errors"
 	"time"
 
@@ -40,7 +41,13 @@ func (dao *GORMUserDAO) Insert(ctx context.Context, u User) error {
 	now := time.Now().UnixMilli()
 	u.Utime = now
 	u.Ctime = now
-	err := dao.db.WithContext(ctx).Create(&u).Error
+	// 注册事件和用户在同一个事务里面，不会出现注册成功了事件丢了
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&u).Error; err != nil {
+			return err
+		}
+		return insertOutbox(tx, events.TopicUserSignup, u.Id, events.UserSignupEvent{Uid: u.Id})
+	})
 	if mysqlErr, ok := err.(*mysql.MySQLError); ok {
 		const uniqueConflictErr uint16 = 1062 // 唯一索引错误吗
 		if mysqlErr.Number == uniqueConflictErr {
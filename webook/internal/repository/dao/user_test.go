@@ -3,6 +3,7 @@ package dao
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -30,7 +31,11 @@ func TestGORMUserDAO_Insert(t *testing.T) {
 
 				res := sqlmock.NewResult(3, 1)
 				// 输入正则表达式
+				mock.ExpectBegin()
 				mock.ExpectExec("INSERT INTO `users` .*").WillReturnResult(res)
+				mock.ExpectExec("INSERT INTO `outbox_messages` .*").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 				require.NoError(t, err)
 				return mockDB
 			},
@@ -44,6 +49,28 @@ func TestGORMUserDAO_Insert(t *testing.T) {
 			wantId:  3,
 			wantErr: nil,
 		},
+		{
+			name: "写发件箱失败，回滚",
+			mock: func(t *testing.T) *sql.DB {
+				mockDB, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `users` .*").
+					WillReturnResult(sqlmock.NewResult(3, 1))
+				mock.ExpectExec("INSERT INTO `outbox_messages` .*").
+					WillReturnError(errors.New("mock db error"))
+				mock.ExpectRollback()
+				return mockDB
+			},
+			user: User{
+				Email: sql.NullString{
+					String: "123@qq.com",
+					Valid:  true,
+				},
+				Password: "123",
+			},
+			wantErr: errors.New("mock db error"),
+		},
 	}
 	for _, tc := range testCase {
 		t.Run(tc.name, func(t *testing.T) {
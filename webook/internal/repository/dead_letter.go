@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository/dao"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+)
+
+var (
+	ErrDeadLetterNotFound      = dao.ErrDeadLetterNotFound
+	ErrDeadLetterStatusChanged = dao.ErrDeadLetterStatusChanged
+)
+
+type DeadLetterRepository interface {
+	Create(ctx context.Context, dl domain.DeadLetter) error
+	FindById(ctx context.Context, id int64) (domain.DeadLetter, error)
+	List(ctx context.Context, group string, offset, limit int) ([]domain.DeadLetter, error)
+	// MarkReplayed 已经重放过了返回 ErrDeadLetterStatusChanged
+	MarkReplayed(ctx context.Context, id int64) error
+	// UnmarkReplayed 重放失败了，改回来
+	UnmarkReplayed(ctx context.Context, id int64) error
+}
+
+type GORMDeadLetterRepository struct {
+	dao dao.DeadLetterDAO
+}
+
+func NewDeadLetterRepository(dao dao.DeadLetterDAO) DeadLetterRepository {
+	return &GORMDeadLetterRepository{
+		dao: dao,
+	}
+}
+
+func (r *GORMDeadLetterRepository) Create(ctx context.Context, dl domain.DeadLetter) error {
+	errMsg := []rune(dl.Err)
+	if len(errMsg) > 1024 {
+		errMsg = errMsg[:1024]
+	}
+	return r.dao.Insert(ctx, dao.DeadLetter{
+		GroupName: dl.Group,
+		Topic:     dl.Topic,
+		Key:       dl.Key,
+		Value:     dl.Value,
+		Err:       string(errMsg),
+	})
+}
+
+func (r *GORMDeadLetterRepository) FindById(ctx context.Context, id int64) (domain.DeadLetter, error) {
+	dl, err := r.dao.FindById(ctx, id)
+	if err != nil {
+		return domain.DeadLetter{}, err
+	}
+	return r.entityToDomain(dl), nil
+}
+
+func (r *GORMDeadLetterRepository) List(ctx context.Context, group string, offset, limit int) ([]domain.DeadLetter, error) {
+	dls, err := r.dao.List(ctx, group, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(dls, func(idx int, src dao.DeadLetter) domain.DeadLetter {
+		return r.entityToDomain(src)
+	}), nil
+}
+
+func (r *GORMDeadLetterRepository) MarkReplayed(ctx context.Context, id int64) error {
+	return r.dao.MarkReplayed(ctx, id)
+}
+
+func (r *GORMDeadLetterRepository) UnmarkReplayed(ctx context.Context, id int64) error {
+	return r.dao.UnmarkReplayed(ctx, id)
+}
+
+func (r *GORMDeadLetterRepository) entityToDomain(dl dao.DeadLetter) domain.DeadLetter {
+	return domain.DeadLetter{
+		Id:       dl.Id,
+		Group:    dl.GroupName,
+		Topic:    dl.Topic,
+		Key:      dl.Key,
+		Value:    dl.Value,
+		Err:      dl.Err,
+		Replayed: dl.IsReplayed(),
+		Ctime:    time.UnixMilli(dl.Ctime),
+		Utime:    time.UnixMilli(dl.Utime),
+	}
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository/dao"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+)
+
+// FeedRepository 时间线只存帖子 id，帖子内容读的时候再查
+type FeedRepository interface {
+	// CreatePushEvents 推给这些粉丝
+	CreatePushEvents(ctx context.Context, art domain.Article, followers []int64, ctime time.Time) error
+	CreatePullEvent(ctx context.Context, art domain.Article, ctime time.Time) error
+	// FindPushEvents 游标是 (before, beforeId)，beforeId 是上一页最后一篇帖子的 id
+	FindPushEvents(ctx context.Context, uid int64, before time.Time, beforeId int64, limit int) ([]domain.FeedEvent, error)
+	FindPullEvents(ctx context.Context, authors []int64, before time.Time, beforeId int64, limit int) ([]domain.FeedEvent, error)
+}
+
+type GORMFeedRepository struct {
+	dao dao.FeedDAO
+}
+
+func NewFeedRepository(dao dao.FeedDAO) FeedRepository {
+	return &GORMFeedRepository{
+		dao: dao,
+	}
+}
+
+func (r *GORMFeedRepository) CreatePushEvents(ctx context.Context, art domain.Article, followers []int64, ctime time.Time) error {
+	if len(followers) == 0 {
+		return nil
+	}
+	return r.dao.InsertPushEvents(ctx, slice.Map(followers, func(idx int, src int64) dao.FeedPushEvent {
+		return dao.FeedPushEvent{
+			Uid:    src,
+			BizId:  art.Id,
+			Author: art.Author.Id,
+			Ctime:  ctime.UnixMilli(),
+		}
+	}))
+}
+
+func (r *GORMFeedRepository) CreatePullEvent(ctx context.Context, art domain.Article, ctime time.Time) error {
+	return r.dao.InsertPullEvent(ctx, dao.FeedPullEvent{
+		Uid:   art.Author.Id,
+		BizId: art.Id,
+		Ctime: ctime.UnixMilli(),
+	})
+}
+
+func (r *GORMFeedRepository) FindPushEvents(ctx context.Context, uid int64, before time.Time, beforeId int64, limit int) ([]domain.FeedEvent, error) {
+	events, err := r.dao.FindPushEvents(ctx, uid, before.UnixMilli(), beforeId, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(events, func(idx int, src dao.FeedPushEvent) domain.FeedEvent {
+		return domain.FeedEvent{
+			Uid: src.Uid,
+			Article: domain.Article{
+				Id:     src.BizId,
+				Author: domain.Author{Id: src.Author},
+			},
+			Ctime: time.UnixMilli(src.Ctime),
+		}
+	}), nil
+}
+
+func (r *GORMFeedRepository) FindPullEvents(ctx context.Context, authors []int64, before time.Time, beforeId int64, limit int) ([]domain.FeedEvent, error) {
+	if len(authors) == 0 {
+		return nil, nil
+	}
+	events, err := r.dao.FindPullEvents(ctx, authors, before.UnixMilli(), beforeId, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(events, func(idx int, src dao.FeedPullEvent) domain.FeedEvent {
+		return domain.FeedEvent{
+			Uid: src.Uid,
+			Article: domain.Article{
+				Id:     src.BizId,
+				Author: domain.Author{Id: src.Uid},
+			},
+			Ctime: time.UnixMilli(src.Ctime),
+		}
+	}), nil
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository/cache"
+	"dream/webook/internal/repository/dao"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+)
+
+type FollowRepository interface {
+	// AddFollow 重复关注直接返回成功
+	AddFollow(ctx context.Context, follower, followee int64) error
+	// CancelFollow 本来就没关注直接返回成功
+	CancelFollow(ctx context.Context, follower, followee int64) error
+	GetFollowers(ctx context.Context, followee int64, offset, limit int) ([]domain.FollowRelation, error)
+	GetFollowees(ctx context.Context, follower int64, offset, limit int) ([]domain.FollowRelation, error)
+	// GetRelations followers 里面的人有没有关注 followees 里面的人
+	GetRelations(ctx context.Context, followers, followees []int64) ([]domain.FollowRelation, error)
+	GetStatics(ctx context.Context, uid int64) (domain.FollowStatics, error)
+}
+
+type CachedFollowRepository struct {
+	dao   dao.FollowDAO
+	cache cache.FollowCache
+}
+
+func NewFollowRepository(dao dao.FollowDAO, c cache.FollowCache) FollowRepository {
+	return &CachedFollowRepository{
+		dao:   dao,
+		cache: c,
+	}
+}
+
+func (r *CachedFollowRepository) AddFollow(ctx context.Context, follower, followee int64) error {
+	err := r.dao.Follow(ctx, follower, followee)
+	if err == dao.ErrFollowUnchanged {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_ = r.cache.Follow(ctx, follower, followee)
+	return nil
+}
+
+func (r *CachedFollowRepository) CancelFollow(ctx context.Context, follower, followee int64) error {
+	err := r.dao.CancelFollow(ctx, follower, followee)
+	if err == dao.ErrFollowUnchanged {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_ = r.cache.CancelFollow(ctx, follower, followee)
+	return nil
+}
+
+func (r *CachedFollowRepository) GetFollowers(ctx context.Context, followee int64, offset, limit int) ([]domain.FollowRelation, error) {
+	rs, err := r.dao.ListFollowers(ctx, followee, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return r.toDomains(rs), nil
+}
+
+func (r *CachedFollowRepository) GetFollowees(ctx context.Context, follower int64, offset, limit int) ([]domain.FollowRelation, error) {
+	rs, err := r.dao.ListFollowees(ctx, follower, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return r.toDomains(rs), nil
+}
+
+func (r *CachedFollowRepository) GetRelations(ctx context.Context, followers, followees []int64) ([]domain.FollowRelation, error) {
+	if len(followers) == 0 || len(followees) == 0 {
+		return nil, nil
+	}
+	rs, err := r.dao.FindRelations(ctx, followers, followees)
+	if err != nil {
+		return nil, err
+	}
+	return r.toDomains(rs), nil
+}
+
+func (r *CachedFollowRepository) GetStatics(ctx context.Context, uid int64) (domain.FollowStatics, error) {
+	s, err := r.cache.GetStatics(ctx, uid)
+	if err == nil {
+		return s, nil
+	}
+	se, err := r.dao.GetStatics(ctx, uid)
+	if err == dao.ErrFollowStaticsNotFound {
+		// 还没有关注过别人，也没有人关注
+		return domain.FollowStatics{}, nil
+	}
+	if err != nil {
+		return domain.FollowStatics{}, err
+	}
+	s = domain.FollowStatics{
+		Followers: se.Followers,
+		Followees: se.Followees,
+	}
+	_ = r.cache.SetStatics(ctx, uid, s)
+	return s, nil
+}
+
+func (r *CachedFollowRepository) toDomains(rs []dao.FollowRelation) []domain.FollowRelation {
+	return slice.Map(rs, func(idx int, src dao.FollowRelation) domain.FollowRelation {
+		return domain.FollowRelation{
+			Follower: src.Follower,
+			Followee: src.Followee,
+			// 取消之后重新关注的，utime 才是关注的时间
+			Ctime: time.UnixMilli(src.Utime),
+		}
+	})
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository/cache"
+	"dream/webook/internal/repository/dao"
+)
+
+type InteractiveRepository interface {
+	IncrReadCnt(ctx context.Context, biz string, bizId int64) error
+	// BatchIncrReadCnt key 是 bizId，value 是增加的数量
+	BatchIncrReadCnt(ctx context.Context, biz string, cnts map[int64]int64) error
+	IncrLike(ctx context.Context, biz string, bizId, uid int64) error
+	DecrLike(ctx context.Context, biz string, bizId, uid int64) error
+	AddCollectionItem(ctx context.Context, biz string, bizId, cid, uid int64) error
+	DeleteCollectionItem(ctx context.Context, biz string, bizId, uid int64) error
+	// DecrCollectCntCache 收藏记录被批量删除之后，同步缓存里的收藏数
+	DecrCollectCntCache(ctx context.Context, biz string, bizId int64)
+	Get(ctx context.Context, biz string, bizId int64) (domain.Interactive, error)
+	// GetByIds 批量查询，key 是 bizId，没有交互数据的资源不在结果里面
+	GetByIds(ctx context.Context, biz string, ids []int64) (map[int64]domain.Interactive, error)
+	Liked(ctx context.Context, biz string, bizId, uid int64) (bool, error)
+	Collected(ctx context.Context, biz string, bizId, uid int64) (bool, error)
+}
+
+type CachedInteractiveRepository struct {
+	dao   dao.InteractiveDAO
+	cache cache.InteractiveCache
+}
+
+func NewInteractiveRepository(dao dao.InteractiveDAO, c cache.InteractiveCache) InteractiveRepository {
+	return &CachedInteractiveRepository{
+		dao:   dao,
+		cache: c,
+	}
+}
+
+func (r *CachedInteractiveRepository) IncrReadCnt(ctx context.Context, biz string, bizId int64) error {
+	// 先更新数据库，再更新缓存
+	err := r.dao.IncrReadCnt(ctx, biz, bizId)
+	if err != nil {
+		return err
+	}
+	// 缓存更新失败了，数据库已经成功了，不算失败
+	// 最多就是缓存里的数据少了一点，阅读数不需要那么精确
+	_ = r.cache.IncrReadCntIfPresent(ctx, biz, bizId)
+	return nil
+}
+
+func (r *CachedInteractiveRepository) BatchIncrReadCnt(ctx context.Context, biz string, cnts map[int64]int64) error {
+	err := r.dao.BatchIncrReadCnt(ctx, biz, cnts)
+	if err != nil {
+		return err
+	}
+	_ = r.cache.BatchIncrReadCntIfPresent(ctx, biz, cnts)
+	return nil
+}
+
+func (r *CachedInteractiveRepository) IncrLike(ctx context.Context, biz string, bizId, uid int64) error {
+	err := r.dao.InsertLikeInfo(ctx, biz, bizId, uid)
+	if err == dao.ErrInteractiveUnchanged {
+		// 重复点赞
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_ = r.cache.IncrLikeCntIfPresent(ctx, biz, bizId)
+	return nil
+}
+
+func (r *CachedInteractiveRepository) DecrLike(ctx context.Context, biz string, bizId, uid int64) error {
+	err := r.dao.DeleteLikeInfo(ctx, biz, bizId, uid)
+	if err == dao.ErrInteractiveUnchanged {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_ = r.cache.DecrLikeCntIfPresent(ctx, biz, bizId)
+	return nil
+}
+
+func (r *CachedInteractiveRepository) AddCollectionItem(ctx context.Context, biz string, bizId, cid, uid int64) error {
+	err := r.dao.InsertCollectionBiz(ctx, dao.UserCollectionBiz{
+		Uid:   uid,
+		Biz:   biz,
+		BizId: bizId,
+		Cid:   cid,
+	})
+	if err == dao.ErrInteractiveUnchanged {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_ = r.cache.IncrCollectCntIfPresent(ctx, biz, bizId)
+	return nil
+}
+
+func (r *CachedInteractiveRepository) DeleteCollectionItem(ctx context.Context, biz string, bizId, uid int64) error {
+	err := r.dao.DeleteCollectionBiz(ctx, biz, bizId, uid)
+	if err == dao.ErrInteractiveUnchanged {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	r.DecrCollectCntCache(ctx, biz, bizId)
+	return nil
+}
+
+func (r *CachedInteractiveRepository) DecrCollectCntCache(ctx context.Context, biz string, bizId int64) {
+	_ = r.cache.DecrCollectCntIfPresent(ctx, biz, bizId)
+}
+
+func (r *CachedInteractiveRepository) Get(ctx context.Context, biz string, bizId int64) (domain.Interactive, error) {
+	intr, err := r.cache.Get(ctx, biz, bizId)
+	if err == nil {
+		return intr, nil
+	}
+	ie, err := r.dao.Get(ctx, biz, bizId)
+	if err == dao.ErrRecordNotFound {
+		// 还没有任何人阅读、点赞、收藏过
+		return domain.Interactive{Biz: biz, BizId: bizId}, nil
+	}
+	if err != nil {
+		return domain.Interactive{}, err
+	}
+	intr = r.entityToDomain(ie)
+	_ = r.cache.Set(ctx, intr)
+	return intr, nil
+}
+
+func (r *CachedInteractiveRepository) GetByIds(ctx context.Context, biz string, ids []int64) (map[int64]domain.Interactive, error) {
+	if len(ids) == 0 {
+		return map[int64]domain.Interactive{}, nil
+	}
+	// 批量查询直接走数据库，列表页对实时性要求不高
+	ies, err := r.dao.GetByIds(ctx, biz, ids)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[int64]domain.Interactive, len(ies))
+	for _, ie := range ies {
+		res[ie.BizId] = r.entityToDomain(ie)
+	}
+	return res, nil
+}
+
+func (r *CachedInteractiveRepository) Liked(ctx context.Context, biz string, bizId, uid int64) (bool, error) {
+	_, err := r.dao.GetLikeInfo(ctx, biz, bizId, uid)
+	switch err {
+	case nil:
+		return true, nil
+	case dao.ErrRecordNotFound:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+func (r *CachedInteractiveRepository) Collected(ctx context.Context, biz string, bizId, uid int64) (bool, error) {
+	_, err := r.dao.GetCollectionInfo(ctx, biz, bizId, uid)
+	switch err {
+	case nil:
+		return true, nil
+	case dao.ErrRecordNotFound:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+func (r *CachedInteractiveRepository) entityToDomain(ie dao.Interactive) domain.Interactive {
+	return domain.Interactive{
+		Biz:        ie.Biz,
+		BizId:      ie.BizId,
+		ReadCnt:    ie.ReadCnt,
+		LikeCnt:    ie.LikeCnt,
+		CollectCnt: ie.CollectCnt,
+	}
+}
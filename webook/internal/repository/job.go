@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository/dao"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+)
+
+var (
+	ErrNoMoreJob   = dao.ErrNoMoreJob
+	ErrJobNotOwned = dao.ErrJobNotOwned
+	ErrJobNotFound = dao.ErrJobNotFound
+)
+
+type JobRepository interface {
+	Preempt(ctx context.Context, owner string, timeout time.Duration) (domain.Job, error)
+	Heartbeat(ctx context.Context, id int64, owner string, version int64) error
+	Release(ctx context.Context, id int64, owner string, version int64, nextTime time.Time) error
+	AddIfNotExist(ctx context.Context, j domain.Job) error
+	List(ctx context.Context, offset, limit int) ([]domain.Job, error)
+	FindById(ctx context.Context, id int64) (domain.Job, error)
+	Pause(ctx context.Context, id int64) error
+	Resume(ctx context.Context, id int64, nextTime time.Time) error
+}
+
+type PreemptJobRepository struct {
+	dao dao.JobDAO
+}
+
+func NewJobRepository(dao dao.JobDAO) JobRepository {
+	return &PreemptJobRepository{
+		dao: dao,
+	}
+}
+
+func (r *PreemptJobRepository) Preempt(ctx context.Context, owner string, timeout time.Duration) (domain.Job, error) {
+	j, err := r.dao.Preempt(ctx, owner, timeout)
+	if err != nil {
+		return domain.Job{}, err
+	}
+	return r.entityToDomain(j), nil
+}
+
+func (r *PreemptJobRepository) Heartbeat(ctx context.Context, id int64, owner string, version int64) error {
+	return r.dao.Heartbeat(ctx, id, owner, version)
+}
+
+func (r *PreemptJobRepository) Release(ctx context.Context, id int64, owner string, version int64, nextTime time.Time) error {
+	return r.dao.Release(ctx, id, owner, version, nextTime.UnixMilli())
+}
+
+func (r *PreemptJobRepository) AddIfNotExist(ctx context.Context, j domain.Job) error {
+	return r.dao.InsertIfNotExist(ctx, dao.Job{
+		Name:       j.Name,
+		Executor:   j.Executor,
+		Cfg:        j.Cfg,
+		Expression: j.Expression,
+		NextTime:   j.NextTime.UnixMilli(),
+	})
+}
+
+func (r *PreemptJobRepository) List(ctx context.Context, offset, limit int) ([]domain.Job, error) {
+	js, err := r.dao.List(ctx, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(js, func(idx int, src dao.Job) domain.Job {
+		return r.entityToDomain(src)
+	}), nil
+}
+
+func (r *PreemptJobRepository) FindById(ctx context.Context, id int64) (domain.Job, error) {
+	j, err := r.dao.GetById(ctx, id)
+	if err != nil {
+		return domain.Job{}, err
+	}
+	return r.entityToDomain(j), nil
+}
+
+func (r *PreemptJobRepository) Pause(ctx context.Context, id int64) error {
+	return r.dao.Pause(ctx, id)
+}
+
+func (r *PreemptJobRepository) Resume(ctx context.Context, id int64, nextTime time.Time) error {
+	return r.dao.Resume(ctx, id, nextTime.UnixMilli())
+}
+
+func (r *PreemptJobRepository) entityToDomain(j dao.Job) domain.Job {
+	return domain.Job{
+		Id:         j.Id,
+		Name:       j.Name,
+		Executor:   j.Executor,
+		Cfg:        j.Cfg,
+		Expression: j.Expression,
+		NextTime:   time.UnixMilli(j.NextTime),
+		Status:     domain.JobStatus(j.Status),
+		Version:    j.Version,
+		Owner:      j.Owner,
+		Utime:      time.UnixMilli(j.Utime),
+	}
+}
@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: webook/internal/repository/account.go
+//
+// Generated by this command:
+//
+//	mockgen -source=webook/internal/repository/account.go -package=repomocks -destination=webook/internal/repository/mocks/account.mock.go
+//
+
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	domain "dream/webook/internal/domain"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAccountRepository is a mock of AccountRepository interface.
+type MockAccountRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAccountRepositoryMockRecorder is the mock recorder for MockAccountRepository.
+type MockAccountRepositoryMockRecorder struct {
+	mock *MockAccountRepository
+}
+
+// NewMockAccountRepository creates a new mock instance.
+func NewMockAccountRepository(ctrl *gomock.Controller) *MockAccountRepository {
+	mock := &MockAccountRepository{ctrl: ctrl}
+	mock.recorder = &MockAccountRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountRepository) EXPECT() *MockAccountRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddCredit mocks base method.
+func (m *MockAccountRepository) AddCredit(ctx context.Context, c domain.Credit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCredit", ctx, c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddCredit indicates an expected call of AddCredit.
+func (mr *MockAccountRepositoryMockRecorder) AddCredit(ctx, c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCredit", reflect.TypeOf((*MockAccountRepository)(nil).AddCredit), ctx, c)
+}
+
+// FindByUid mocks base method.
+func (m *MockAccountRepository) FindByUid(ctx context.Context, uid int64, typ domain.AccountType) ([]domain.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUid", ctx, uid, typ)
+	ret0, _ := ret[0].([]domain.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUid indicates an expected call of FindByUid.
+func (mr *MockAccountRepositoryMockRecorder) FindByUid(ctx, uid, typ any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUid", reflect.TypeOf((*MockAccountRepository)(nil).FindByUid), ctx, uid, typ)
+}
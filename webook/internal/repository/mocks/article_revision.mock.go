@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: webook/internal/repository/article_revision.go
+//
+// Generated by this command:
+//
+//	mockgen -source=webook/internal/repository/article_revision.go -package=repomocks -destination=webook/internal/repository/mocks/article_revision.mock.go
+//
+
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	domain "dream/webook/internal/domain"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockArticleRevisionRepository is a mock of ArticleRevisionRepository interface.
+type MockArticleRevisionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockArticleRevisionRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockArticleRevisionRepositoryMockRecorder is the mock recorder for MockArticleRevisionRepository.
+type MockArticleRevisionRepositoryMockRecorder struct {
+	mock *MockArticleRevisionRepository
+}
+
+// NewMockArticleRevisionRepository creates a new mock instance.
+func NewMockArticleRevisionRepository(ctrl *gomock.Controller) *MockArticleRevisionRepository {
+	mock := &MockArticleRevisionRepository{ctrl: ctrl}
+	mock.recorder = &MockArticleRevisionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockArticleRevisionRepository) EXPECT() *MockArticleRevisionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetById mocks base method.
+func (m *MockArticleRevisionRepository) GetById(ctx context.Context, id int64) (domain.ArticleRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", ctx, id)
+	ret0, _ := ret[0].(domain.ArticleRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockArticleRevisionRepositoryMockRecorder) GetById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockArticleRevisionRepository)(nil).GetById), ctx, id)
+}
+
+// ListByArticle mocks base method.
+func (m *MockArticleRevisionRepository) ListByArticle(ctx context.Context, artId int64, offset, limit int) ([]domain.ArticleRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByArticle", ctx, artId, offset, limit)
+	ret0, _ := ret[0].([]domain.ArticleRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByArticle indicates an expected call of ListByArticle.
+func (mr *MockArticleRevisionRepositoryMockRecorder) ListByArticle(ctx, artId, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByArticle", reflect.TypeOf((*MockArticleRevisionRepository)(nil).ListByArticle), ctx, artId, offset, limit)
+}
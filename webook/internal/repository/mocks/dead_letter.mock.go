@@ -0,0 +1,114 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: webook/internal/repository/dead_letter.go
+//
+// Generated by this command:
+//
+//	mockgen -source=webook/internal/repository/dead_letter.go -package=repomocks -destination=webook/internal/repository/mocks/dead_letter.mock.go
+//
+
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	domain "dream/webook/internal/domain"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDeadLetterRepository is a mock of DeadLetterRepository interface.
+type MockDeadLetterRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeadLetterRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockDeadLetterRepositoryMockRecorder is the mock recorder for MockDeadLetterRepository.
+type MockDeadLetterRepositoryMockRecorder struct {
+	mock *MockDeadLetterRepository
+}
+
+// NewMockDeadLetterRepository creates a new mock instance.
+func NewMockDeadLetterRepository(ctrl *gomock.Controller) *MockDeadLetterRepository {
+	mock := &MockDeadLetterRepository{ctrl: ctrl}
+	mock.recorder = &MockDeadLetterRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeadLetterRepository) EXPECT() *MockDeadLetterRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockDeadLetterRepository) Create(ctx context.Context, dl domain.DeadLetter) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, dl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDeadLetterRepositoryMockRecorder) Create(ctx, dl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDeadLetterRepository)(nil).Create), ctx, dl)
+}
+
+// FindById mocks base method.
+func (m *MockDeadLetterRepository) FindById(ctx context.Context, id int64) (domain.DeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", ctx, id)
+	ret0, _ := ret[0].(domain.DeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockDeadLetterRepositoryMockRecorder) FindById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockDeadLetterRepository)(nil).FindById), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockDeadLetterRepository) List(ctx context.Context, group string, offset, limit int) ([]domain.DeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, group, offset, limit)
+	ret0, _ := ret[0].([]domain.DeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockDeadLetterRepositoryMockRecorder) List(ctx, group, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockDeadLetterRepository)(nil).List), ctx, group, offset, limit)
+}
+
+// MarkReplayed mocks base method.
+func (m *MockDeadLetterRepository) MarkReplayed(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkReplayed", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkReplayed indicates an expected call of MarkReplayed.
+func (mr *MockDeadLetterRepositoryMockRecorder) MarkReplayed(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkReplayed", reflect.TypeOf((*MockDeadLetterRepository)(nil).MarkReplayed), ctx, id)
+}
+
+// UnmarkReplayed mocks base method.
+func (m *MockDeadLetterRepository) UnmarkReplayed(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnmarkReplayed", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnmarkReplayed indicates an expected call of UnmarkReplayed.
+func (mr *MockDeadLetterRepositoryMockRecorder) UnmarkReplayed(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnmarkReplayed", reflect.TypeOf((*MockDeadLetterRepository)(nil).UnmarkReplayed), ctx, id)
+}
@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: webook/internal/repository/feed.go
+//
+// Generated by this command:
+//
+//	mockgen -source=webook/internal/repository/feed.go -package=repomocks -destination=webook/internal/repository/mocks/feed.mock.go
+//
+
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	domain "dream/webook/internal/domain"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFeedRepository is a mock of FeedRepository interface.
+type MockFeedRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFeedRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockFeedRepositoryMockRecorder is the mock recorder for MockFeedRepository.
+type MockFeedRepositoryMockRecorder struct {
+	mock *MockFeedRepository
+}
+
+// NewMockFeedRepository creates a new mock instance.
+func NewMockFeedRepository(ctrl *gomock.Controller) *MockFeedRepository {
+	mock := &MockFeedRepository{ctrl: ctrl}
+	mock.recorder = &MockFeedRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFeedRepository) EXPECT() *MockFeedRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreatePullEvent mocks base method.
+func (m *MockFeedRepository) CreatePullEvent(ctx context.Context, art domain.Article, ctime time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePullEvent", ctx, art, ctime)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePullEvent indicates an expected call of CreatePullEvent.
+func (mr *MockFeedRepositoryMockRecorder) CreatePullEvent(ctx, art, ctime any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePullEvent", reflect.TypeOf((*MockFeedRepository)(nil).CreatePullEvent), ctx, art, ctime)
+}
+
+// CreatePushEvents mocks base method.
+func (m *MockFeedRepository) CreatePushEvents(ctx context.Context, art domain.Article, followers []int64, ctime time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePushEvents", ctx, art, followers, ctime)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePushEvents indicates an expected call of CreatePushEvents.
+func (mr *MockFeedRepositoryMockRecorder) CreatePushEvents(ctx, art, followers, ctime any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePushEvents", reflect.TypeOf((*MockFeedRepository)(nil).CreatePushEvents), ctx, art, followers, ctime)
+}
+
+// FindPullEvents mocks base method.
+func (m *MockFeedRepository) FindPullEvents(ctx context.Context, authors []int64, before time.Time, beforeId int64, limit int) ([]domain.FeedEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPullEvents", ctx, authors, before, beforeId, limit)
+	ret0, _ := ret[0].([]domain.FeedEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPullEvents indicates an expected call of FindPullEvents.
+func (mr *MockFeedRepositoryMockRecorder) FindPullEvents(ctx, authors, before, beforeId, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPullEvents", reflect.TypeOf((*MockFeedRepository)(nil).FindPullEvents), ctx, authors, before, beforeId, limit)
+}
+
+// FindPushEvents mocks base method.
+func (m *MockFeedRepository) FindPushEvents(ctx context.Context, uid int64, before time.Time, beforeId int64, limit int) ([]domain.FeedEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPushEvents", ctx, uid, before, beforeId, limit)
+	ret0, _ := ret[0].([]domain.FeedEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPushEvents indicates an expected call of FindPushEvents.
+func (mr *MockFeedRepositoryMockRecorder) FindPushEvents(ctx, uid, before, beforeId, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPushEvents", reflect.TypeOf((*MockFeedRepository)(nil).FindPushEvents), ctx, uid, before, beforeId, limit)
+}
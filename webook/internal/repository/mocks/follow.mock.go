@@ -0,0 +1,130 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: webook/internal/repository/follow.go
+//
+// Generated by this command:
+//
+//	mockgen -source=webook/internal/repository/follow.go -package=repomocks -destination=webook/internal/repository/mocks/follow.mock.go
+//
+
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	domain "dream/webook/internal/domain"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFollowRepository is a mock of FollowRepository interface.
+type MockFollowRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFollowRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockFollowRepositoryMockRecorder is the mock recorder for MockFollowRepository.
+type MockFollowRepositoryMockRecorder struct {
+	mock *MockFollowRepository
+}
+
+// NewMockFollowRepository creates a new mock instance.
+func NewMockFollowRepository(ctrl *gomock.Controller) *MockFollowRepository {
+	mock := &MockFollowRepository{ctrl: ctrl}
+	mock.recorder = &MockFollowRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFollowRepository) EXPECT() *MockFollowRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddFollow mocks base method.
+func (m *MockFollowRepository) AddFollow(ctx context.Context, follower, followee int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddFollow", ctx, follower, followee)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddFollow indicates an expected call of AddFollow.
+func (mr *MockFollowRepositoryMockRecorder) AddFollow(ctx, follower, followee any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFollow", reflect.TypeOf((*MockFollowRepository)(nil).AddFollow), ctx, follower, followee)
+}
+
+// CancelFollow mocks base method.
+func (m *MockFollowRepository) CancelFollow(ctx context.Context, follower, followee int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelFollow", ctx, follower, followee)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelFollow indicates an expected call of CancelFollow.
+func (mr *MockFollowRepositoryMockRecorder) CancelFollow(ctx, follower, followee any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelFollow", reflect.TypeOf((*MockFollowRepository)(nil).CancelFollow), ctx, follower, followee)
+}
+
+// GetFollowees mocks base method.
+func (m *MockFollowRepository) GetFollowees(ctx context.Context, follower int64, offset, limit int) ([]domain.FollowRelation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFollowees", ctx, follower, offset, limit)
+	ret0, _ := ret[0].([]domain.FollowRelation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFollowees indicates an expected call of GetFollowees.
+func (mr *MockFollowRepositoryMockRecorder) GetFollowees(ctx, follower, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFollowees", reflect.TypeOf((*MockFollowRepository)(nil).GetFollowees), ctx, follower, offset, limit)
+}
+
+// GetFollowers mocks base method.
+func (m *MockFollowRepository) GetFollowers(ctx context.Context, followee int64, offset, limit int) ([]domain.FollowRelation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFollowers", ctx, followee, offset, limit)
+	ret0, _ := ret[0].([]domain.FollowRelation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFollowers indicates an expected call of GetFollowers.
+func (mr *MockFollowRepositoryMockRecorder) GetFollowers(ctx, followee, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFollowers", reflect.TypeOf((*MockFollowRepository)(nil).GetFollowers), ctx, followee, offset, limit)
+}
+
+// GetRelations mocks base method.
+func (m *MockFollowRepository) GetRelations(ctx context.Context, followers, followees []int64) ([]domain.FollowRelation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRelations", ctx, followers, followees)
+	ret0, _ := ret[0].([]domain.FollowRelation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRelations indicates an expected call of GetRelations.
+func (mr *MockFollowRepositoryMockRecorder) GetRelations(ctx, followers, followees any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRelations", reflect.TypeOf((*MockFollowRepository)(nil).GetRelations), ctx, followers, followees)
+}
+
+// GetStatics mocks base method.
+func (m *MockFollowRepository) GetStatics(ctx context.Context, uid int64) (domain.FollowStatics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatics", ctx, uid)
+	ret0, _ := ret[0].(domain.FollowStatics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatics indicates an expected call of GetStatics.
+func (mr *MockFollowRepositoryMockRecorder) GetStatics(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatics", reflect.TypeOf((*MockFollowRepository)(nil).GetStatics), ctx, uid)
+}
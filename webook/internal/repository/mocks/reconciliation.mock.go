@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: webook/internal/repository/reconciliation.go
+//
+// Generated by this command:
+//
+//	mockgen -source=webook/internal/repository/reconciliation.go -package=repomocks -destination=webook/internal/repository/mocks/reconciliation.mock.go
+//
+
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	domain "dream/webook/internal/domain"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockReconciliationRepository is a mock of ReconciliationRepository interface.
+type MockReconciliationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockReconciliationRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockReconciliationRepositoryMockRecorder is the mock recorder for MockReconciliationRepository.
+type MockReconciliationRepositoryMockRecorder struct {
+	mock *MockReconciliationRepository
+}
+
+// NewMockReconciliationRepository creates a new mock instance.
+func NewMockReconciliationRepository(ctrl *gomock.Controller) *MockReconciliationRepository {
+	mock := &MockReconciliationRepository{ctrl: ctrl}
+	mock.recorder = &MockReconciliationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReconciliationRepository) EXPECT() *MockReconciliationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockReconciliationRepository) Create(ctx context.Context, r domain.Reconciliation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockReconciliationRepositoryMockRecorder) Create(ctx, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockReconciliationRepository)(nil).Create), ctx, r)
+}
+
+// Summary mocks base method.
+func (m *MockReconciliationRepository) Summary(ctx context.Context, start, end time.Time) ([]domain.ReconciliationSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Summary", ctx, start, end)
+	ret0, _ := ret[0].([]domain.ReconciliationSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Summary indicates an expected call of Summary.
+func (mr *MockReconciliationRepositoryMockRecorder) Summary(ctx, start, end any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Summary", reflect.TypeOf((*MockReconciliationRepository)(nil).Summary), ctx, start, end)
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository/dao"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+)
+
+type OutboxRepository interface {
+	FindPending(ctx context.Context, limit int) ([]domain.OutboxMessage, error)
+	// MarkSent 发出去了直接删掉
+	MarkSent(ctx context.Context, id int64) error
+	MarkRetry(ctx context.Context, id int64, next time.Time) error
+}
+
+type GORMOutboxRepository struct {
+	dao dao.OutboxDAO
+}
+
+func NewOutboxRepository(dao dao.OutboxDAO) OutboxRepository {
+	return &GORMOutboxRepository{
+		dao: dao,
+	}
+}
+
+func (r *GORMOutboxRepository) FindPending(ctx context.Context, limit int) ([]domain.OutboxMessage, error) {
+	msgs, err := r.dao.FindPending(ctx, time.Now().UnixMilli(), limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(msgs, func(idx int, src dao.OutboxMessage) domain.OutboxMessage {
+		return domain.OutboxMessage{
+			Id:      src.Id,
+			Topic:   src.Topic,
+			Key:     src.Key,
+			Value:   src.Value,
+			Retries: src.Retries,
+		}
+	}), nil
+}
+
+func (r *GORMOutboxRepository) MarkSent(ctx context.Context, id int64) error {
+	return r.dao.Delete(ctx, id)
+}
+
+func (r *GORMOutboxRepository) MarkRetry(ctx context.Context, id int64, next time.Time) error {
+	return r.dao.MarkRetry(ctx, id, next.UnixMilli())
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository/cache"
+)
+
+type RankingRepository interface {
+	// SetScore 更新一篇帖子的热度
+	SetScore(ctx context.Context, id int64, score float64) error
+	// ReplaceTopN 用全量计算的结果替换热榜，arts 已经按热度从高到低排好
+	ReplaceTopN(ctx context.Context, arts []domain.HotArticle) error
+	// GetTopN 热榜，redis 不可用的时候返回本地的副本
+	GetTopN(ctx context.Context, n int) ([]domain.HotArticle, error)
+}
+
+type CachedRankingRepository struct {
+	redis   cache.RankingCache
+	local   cache.RankingLocalCache
+	artRepo ArticleRepository
+}
+
+func NewRankingRepository(redis cache.RankingCache, local cache.RankingLocalCache,
+	artRepo ArticleRepository) RankingRepository {
+	return &CachedRankingRepository{
+		redis:   redis,
+		local:   local,
+		artRepo: artRepo,
+	}
+}
+
+func (r *CachedRankingRepository) SetScore(ctx context.Context, id int64, score float64) error {
+	return r.redis.SetScore(ctx, id, score)
+}
+
+func (r *CachedRankingRepository) ReplaceTopN(ctx context.Context, arts []domain.HotArticle) error {
+	// 先更新本地，redis 失败了本机也能用上新的结果
+	_ = r.local.Set(ctx, arts)
+	return r.redis.Replace(ctx, arts)
+}
+
+func (r *CachedRankingRepository) GetTopN(ctx context.Context, n int) ([]domain.HotArticle, error) {
+	arts, err := r.local.Get(ctx)
+	if err == nil {
+		return r.head(arts, n), nil
+	}
+	arts, err = r.redis.TopN(ctx, n)
+	if err != nil {
+		return r.fallback(ctx, n, err)
+	}
+	arts, err = r.fillArticles(ctx, arts)
+	if err != nil {
+		return r.fallback(ctx, n, err)
+	}
+	_ = r.local.Set(ctx, arts)
+	return arts, nil
+}
+
+// fallback 查询失败的时候，用本地已经过期的副本兜底
+func (r *CachedRankingRepository) fallback(ctx context.Context, n int, err error) ([]domain.HotArticle, error) {
+	arts, er := r.local.ForceGet(ctx)
+	if er != nil {
+		return nil, err
+	}
+	return r.head(arts, n), nil
+}
+
+// fillArticles redis 里只有 id，补充帖子内容，顺便去掉已经撤回的帖子
+func (r *CachedRankingRepository) fillArticles(ctx context.Context, arts []domain.HotArticle) ([]domain.HotArticle, error) {
+	if len(arts) == 0 {
+		return arts, nil
+	}
+	ids := make([]int64, 0, len(arts))
+	for _, art := range arts {
+		ids = append(ids, art.Article.Id)
+	}
+	pubs, err := r.artRepo.GetPublishedByIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	pubMap := make(map[int64]domain.Article, len(pubs))
+	for _, pub := range pubs {
+		pubMap[pub.Id] = pub
+	}
+	res := make([]domain.HotArticle, 0, len(arts))
+	for _, art := range arts {
+		pub, ok := pubMap[art.Article.Id]
+		if !ok || pub.Status != domain.ArticleStatusPublished {
+			continue
+		}
+		res = append(res, domain.HotArticle{
+			Article: pub,
+			Score:   art.Score,
+		})
+	}
+	return res, nil
+}
+
+func (r *CachedRankingRepository) head(arts []domain.HotArticle, n int) []domain.HotArticle {
+	if len(arts) > n {
+		return arts[:n]
+	}
+	return arts
+}
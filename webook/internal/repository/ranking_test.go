@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository/cache"
+	cachemocks "dream/webook/internal/repository/cache/mocks"
+	repomocks "dream/webook/internal/repository/mocks"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCachedRankingRepository_GetTopN(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (cache.RankingCache, cache.RankingLocalCache, ArticleRepository)
+
+		wantArts []domain.HotArticle
+		wantErr  error
+	}{
+		{
+			name: "本地缓存命中",
+			mock: func(ctrl *gomock.Controller) (cache.RankingCache, cache.RankingLocalCache, ArticleRepository) {
+				local := cachemocks.NewMockRankingLocalCache(ctrl)
+				local.EXPECT().Get(gomock.Any()).Return([]domain.HotArticle{
+					{Article: domain.Article{Id: 1}, Score: 3},
+					{Article: domain.Article{Id: 2}, Score: 2},
+					{Article: domain.Article{Id: 3}, Score: 1},
+				}, nil)
+				return cachemocks.NewMockRankingCache(ctrl), local, repomocks.NewMockArticleRepository(ctrl)
+			},
+			wantArts: []domain.HotArticle{
+				{Article: domain.Article{Id: 1}, Score: 3},
+				{Article: domain.Article{Id: 2}, Score: 2},
+			},
+		},
+		{
+			name: "从redis加载，去掉撤回的帖子",
+			mock: func(ctrl *gomock.Controller) (cache.RankingCache, cache.RankingLocalCache, ArticleRepository) {
+				local := cachemocks.NewMockRankingLocalCache(ctrl)
+				local.EXPECT().Get(gomock.Any()).Return(nil, cache.ErrKeyNotExist)
+				rc := cachemocks.NewMockRankingCache(ctrl)
+				rc.EXPECT().TopN(gomock.Any(), 2).Return([]domain.HotArticle{
+					{Article: domain.Article{Id: 1}, Score: 3},
+					{Article: domain.Article{Id: 2}, Score: 2},
+				}, nil)
+				ar := repomocks.NewMockArticleRepository(ctrl)
+				ar.EXPECT().GetPublishedByIds(gomock.Any(), []int64{1, 2}).Return([]domain.Article{
+					{Id: 2, Title: "标题2", Status: domain.ArticleStatusPublished},
+					{Id: 1, Title: "标题1", Status: domain.ArticleStatusPrivate},
+				}, nil)
+				local.EXPECT().Set(gomock.Any(), []domain.HotArticle{
+					{Article: domain.Article{Id: 2, Title: "标题2", Status: domain.ArticleStatusPublished}, Score: 2},
+				}).Return(nil)
+				return rc, local, ar
+			},
+			wantArts: []domain.HotArticle{
+				{Article: domain.Article{Id: 2, Title: "标题2", Status: domain.ArticleStatusPublished}, Score: 2},
+			},
+		},
+		{
+			name: "redis不可用，用过期的本地副本兜底",
+			mock: func(ctrl *gomock.Controller) (cache.RankingCache, cache.RankingLocalCache, ArticleRepository) {
+				local := cachemocks.NewMockRankingLocalCache(ctrl)
+				local.EXPECT().Get(gomock.Any()).Return(nil, cache.ErrKeyNotExist)
+				rc := cachemocks.NewMockRankingCache(ctrl)
+				rc.EXPECT().TopN(gomock.Any(), 2).Return(nil, errors.New("redis error"))
+				local.EXPECT().ForceGet(gomock.Any()).Return([]domain.HotArticle{
+					{Article: domain.Article{Id: 1}, Score: 3},
+				}, nil)
+				return rc, local, repomocks.NewMockArticleRepository(ctrl)
+			},
+			wantArts: []domain.HotArticle{
+				{Article: domain.Article{Id: 1}, Score: 3},
+			},
+		},
+		{
+			name: "查询帖子失败，也用本地副本兜底",
+			mock: func(ctrl *gomock.Controller) (cache.RankingCache, cache.RankingLocalCache, ArticleRepository) {
+				local := cachemocks.NewMockRankingLocalCache(ctrl)
+				local.EXPECT().Get(gomock.Any()).Return(nil, cache.ErrKeyNotExist)
+				rc := cachemocks.NewMockRankingCache(ctrl)
+				rc.EXPECT().TopN(gomock.Any(), 2).Return([]domain.HotArticle{
+					{Article: domain.Article{Id: 1}, Score: 3},
+				}, nil)
+				ar := repomocks.NewMockArticleRepository(ctrl)
+				ar.EXPECT().GetPublishedByIds(gomock.Any(), []int64{1}).Return(nil, errors.New("mock db error"))
+				local.EXPECT().ForceGet(gomock.Any()).Return([]domain.HotArticle{
+					{Article: domain.Article{Id: 1}, Score: 3},
+					{Article: domain.Article{Id: 2}, Score: 2},
+					{Article: domain.Article{Id: 3}, Score: 1},
+				}, nil)
+				return rc, local, ar
+			},
+			wantArts: []domain.HotArticle{
+				{Article: domain.Article{Id: 1}, Score: 3},
+				{Article: domain.Article{Id: 2}, Score: 2},
+			},
+		},
+		{
+			name: "redis不可用，本地也没有",
+			mock: func(ctrl *gomock.Controller) (cache.RankingCache, cache.RankingLocalCache, ArticleRepository) {
+				local := cachemocks.NewMockRankingLocalCache(ctrl)
+				local.EXPECT().Get(gomock.Any()).Return(nil, cache.ErrKeyNotExist)
+				rc := cachemocks.NewMockRankingCache(ctrl)
+				rc.EXPECT().TopN(gomock.Any(), 2).Return(nil, errors.New("redis error"))
+				local.EXPECT().ForceGet(gomock.Any()).Return(nil, cache.ErrKeyNotExist)
+				return rc, local, repomocks.NewMockArticleRepository(ctrl)
+			},
+			wantErr: errors.New("redis error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := NewRankingRepository(tc.mock(ctrl))
+			arts, err := repo.GetTopN(context.Background(), 2)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantArts, arts)
+		})
+	}
+}
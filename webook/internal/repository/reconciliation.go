@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository/dao"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+)
+
+type ReconciliationRepository interface {
+	Create(ctx context.Context, r domain.Reconciliation) error
+	Summary(ctx context.Context, start, end time.Time) ([]domain.ReconciliationSummary, error)
+}
+
+type GORMReconciliationRepository struct {
+	dao dao.ReconciliationDAO
+}
+
+func NewReconciliationRepository(dao dao.ReconciliationDAO) ReconciliationRepository {
+	return &GORMReconciliationRepository{
+		dao: dao,
+	}
+}
+
+func (r *GORMReconciliationRepository) Create(ctx context.Context, rec domain.Reconciliation) error {
+	errMsg := []rune(rec.Err)
+	if len(errMsg) > 1024 {
+		errMsg = errMsg[:1024]
+	}
+	return r.dao.Insert(ctx, dao.Reconciliation{
+		Biz:        rec.Biz,
+		BizId:      rec.BizId,
+		BizTradeNo: rec.BizTradeNo,
+		Amount:     rec.Amt,
+		Result:     rec.Result.ToUint8(),
+		Err:        string(errMsg),
+	})
+}
+
+func (r *GORMReconciliationRepository) Summary(ctx context.Context, start, end time.Time) ([]domain.ReconciliationSummary, error) {
+	res, err := r.dao.Summary(ctx, start.UnixMilli(), end.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(res, func(idx int, src dao.ReconciliationSummary) domain.ReconciliationSummary {
+		return domain.ReconciliationSummary{
+			Result: domain.ReconciliationResult(src.Result),
+			Cnt:    src.Cnt,
+			Amt:    src.Amt,
+		}
+	}), nil
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository/cache"
+	"dream/webook/internal/repository/dao"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+)
+
+var ErrRewardNotFound = dao.ErrRewardNotFound
+
+type RewardRepository interface {
+	CreateReward(ctx context.Context, r domain.Reward) (int64, error)
+	GetReward(ctx context.Context, rid int64) (domain.Reward, error)
+	// UpdateStatus 只会更新还在支付中的订单，订单结束之后缓存的二维码也删掉
+	UpdateStatus(ctx context.Context, rid int64, status domain.RewardStatus) error
+	// FindPaying 分页查询 before 之前创建、还在支付中的订单，lastId 是上一页最后一个订单的 id
+	FindPaying(ctx context.Context, before time.Time, lastId int64, limit int) ([]domain.Reward, error)
+	GetCachedCodeURL(ctx context.Context, r domain.Reward) (domain.CodeURL, error)
+	CachedCodeURL(ctx context.Context, cu domain.CodeURL, r domain.Reward) error
+}
+
+type CachedRewardRepository struct {
+	dao   dao.RewardDAO
+	cache cache.RewardCache
+}
+
+func NewRewardRepository(dao dao.RewardDAO, c cache.RewardCache) RewardRepository {
+	return &CachedRewardRepository{
+		dao:   dao,
+		cache: c,
+	}
+}
+
+func (r *CachedRewardRepository) CreateReward(ctx context.Context, rwd domain.Reward) (int64, error) {
+	return r.dao.Insert(ctx, r.domainToEntity(rwd))
+}
+
+func (r *CachedRewardRepository) GetReward(ctx context.Context, rid int64) (domain.Reward, error) {
+	rwd, err := r.dao.GetById(ctx, rid)
+	if err != nil {
+		return domain.Reward{}, err
+	}
+	return r.entityToDomain(rwd), nil
+}
+
+func (r *CachedRewardRepository) UpdateStatus(ctx context.Context, rid int64, status domain.RewardStatus) error {
+	err := r.dao.UpdateStatus(ctx, rid, domain.RewardStatusInit.ToUint8(), status.ToUint8())
+	if err != nil {
+		return err
+	}
+	if status == domain.RewardStatusInit {
+		return nil
+	}
+	// 缓存的 key 要用到订单的内容，删除失败了 PreReward 复用之前还会检查订单状态
+	rwd, err := r.dao.GetById(ctx, rid)
+	if err == nil {
+		_ = r.cache.DelCodeURL(ctx, r.entityToDomain(rwd))
+	}
+	return nil
+}
+
+func (r *CachedRewardRepository) FindPaying(ctx context.Context, before time.Time, lastId int64, limit int) ([]domain.Reward, error) {
+	rs, err := r.dao.FindByStatus(ctx, domain.RewardStatusInit.ToUint8(), before.UnixMilli(), lastId, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(rs, func(idx int, src dao.Reward) domain.Reward {
+		return r.entityToDomain(src)
+	}), nil
+}
+
+func (r *CachedRewardRepository) GetCachedCodeURL(ctx context.Context, rwd domain.Reward) (domain.CodeURL, error) {
+	return r.cache.GetCachedCodeURL(ctx, rwd)
+}
+
+func (r *CachedRewardRepository) CachedCodeURL(ctx context.Context, cu domain.CodeURL, rwd domain.Reward) error {
+	return r.cache.CachedCodeURL(ctx, cu, rwd)
+}
+
+func (r *CachedRewardRepository) entityToDomain(rwd dao.Reward) domain.Reward {
+	return domain.Reward{
+		Id:  rwd.Id,
+		Uid: rwd.Uid,
+		Target: domain.Target{
+			Biz:     rwd.Biz,
+			BizId:   rwd.BizId,
+			BizName: rwd.BizName,
+			Uid:     rwd.TargetUid,
+		},
+		Amt:    rwd.Amount,
+		Status: domain.RewardStatus(rwd.Status),
+	}
+}
+
+func (r *CachedRewardRepository) domainToEntity(rwd domain.Reward) dao.Reward {
+	return dao.Reward{
+		Id:        rwd.Id,
+		Biz:       rwd.Target.Biz,
+		BizId:     rwd.Target.BizId,
+		BizName:   rwd.Target.BizName,
+		TargetUid: rwd.Target.Uid,
+		Uid:       rwd.Uid,
+		Amount:    rwd.Amt,
+		Status:    rwd.Status.ToUint8(),
+	}
+}
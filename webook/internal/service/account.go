@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository"
+	"errors"
+	"sort"
+)
+
+// ErrInvalidCredit 入账的金额不对
+var ErrInvalidCredit = errors.New("非法的入账")
+
+// AccountService 复式记账，每一笔入账都会从清算账户记一笔相反的流水
+type AccountService interface {
+	// Credit 入账，同一笔支付重复入账直接返回成功
+	Credit(ctx context.Context, c domain.Credit) error
+	// Balance 用户个人账户的余额，每个币种一个
+	Balance(ctx context.Context, uid int64) ([]domain.Account, error)
+}
+
+type DoubleEntryAccountService struct {
+	repo repository.AccountRepository
+}
+
+func NewAccountService(repo repository.AccountRepository) AccountService {
+	return &DoubleEntryAccountService{
+		repo: repo,
+	}
+}
+
+func (svc *DoubleEntryAccountService) Credit(ctx context.Context, c domain.Credit) error {
+	// 每个币种各自配平
+	totals := make(map[string]int64, 1)
+	for _, item := range c.Items {
+		if item.Amt < 0 || item.Type == domain.AccountTypeClearing {
+			return ErrInvalidCredit
+		}
+		totals[item.Currency] += item.Amt
+	}
+	items := make([]domain.CreditItem, 0, len(c.Items)+len(totals))
+	items = append(items, c.Items...)
+	for currency, total := range totals {
+		items = append(items, domain.CreditItem{
+			Type:     domain.AccountTypeClearing,
+			Amt:      -total,
+			Currency: currency,
+		})
+	}
+	// 固定加锁顺序，避免并发入账的时候死锁
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Type != items[j].Type {
+			return items[i].Type < items[j].Type
+		}
+		if items[i].Uid != items[j].Uid {
+			return items[i].Uid < items[j].Uid
+		}
+		return items[i].Currency < items[j].Currency
+	})
+	c.Items = items
+	err := svc.repo.AddCredit(ctx, c)
+	if err == repository.ErrDuplicateCredit {
+		return nil
+	}
+	return err
+}
+
+func (svc *DoubleEntryAccountService) Balance(ctx context.Context, uid int64) ([]domain.Account, error) {
+	return svc.repo.FindByUid(ctx, uid, domain.AccountTypePersonal)
+}
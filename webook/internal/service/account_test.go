@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository"
+	repomocks "dream/webook/internal/repository/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDoubleEntryAccountService_Credit(t *testing.T) {
+	credit := domain.Credit{
+		Biz:   "reward",
+		BizId: 1,
+		Items: []domain.CreditItem{
+			{Type: domain.AccountTypePlatform, Amt: 100, Currency: "CNY"},
+			{Uid: 456, Type: domain.AccountTypePersonal, Amt: 900, Currency: "CNY"},
+		},
+	}
+	// 加上清算账户之后刚好配平，并且按照账户排好序
+	balanced := domain.Credit{
+		Biz:   "reward",
+		BizId: 1,
+		Items: []domain.CreditItem{
+			{Uid: 456, Type: domain.AccountTypePersonal, Amt: 900, Currency: "CNY"},
+			{Type: domain.AccountTypePlatform, Amt: 100, Currency: "CNY"},
+			{Type: domain.AccountTypeClearing, Amt: -1000, Currency: "CNY"},
+		},
+	}
+	testCases := []struct {
+		name   string
+		mock   func(ctrl *gomock.Controller) repository.AccountRepository
+		credit domain.Credit
+
+		wantErr error
+	}{
+		{
+			name: "入账成功",
+			mock: func(ctrl *gomock.Controller) repository.AccountRepository {
+				repo := repomocks.NewMockAccountRepository(ctrl)
+				repo.EXPECT().AddCredit(gomock.Any(), balanced).Return(nil)
+				return repo
+			},
+			credit: credit,
+		},
+		{
+			name: "重复入账",
+			mock: func(ctrl *gomock.Controller) repository.AccountRepository {
+				repo := repomocks.NewMockAccountRepository(ctrl)
+				repo.EXPECT().AddCredit(gomock.Any(), balanced).Return(repository.ErrDuplicateCredit)
+				return repo
+			},
+			credit: credit,
+		},
+		{
+			name: "金额是负数",
+			mock: func(ctrl *gomock.Controller) repository.AccountRepository {
+				return repomocks.NewMockAccountRepository(ctrl)
+			},
+			credit: domain.Credit{
+				Biz:   "reward",
+				BizId: 1,
+				Items: []domain.CreditItem{
+					{Uid: 456, Type: domain.AccountTypePersonal, Amt: -1, Currency: "CNY"},
+				},
+			},
+			wantErr: ErrInvalidCredit,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewAccountService(tc.mock(ctrl))
+			err := svc.Credit(context.Background(), tc.credit)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository"
+	"errors"
+)
+
+// bizArticle 交互、评论这些按 biz 区分的数据里面帖子的业务标识
+const bizArticle = "article"
+
+var (
+	ErrArticleNotFound = repository.ErrArticleNotFound
+	// ErrArticleNotAuthor 操作的帖子不属于当前用户
+	ErrArticleNotAuthor = errors.New("帖子不属于该用户")
+)
+
+type ArticleService interface {
+	Save(ctx context.Context, art domain.Article) (int64, error)
+	Publish(ctx context.Context, art domain.Article) (int64, error)
+	List(ctx context.Context, uid int64, offset, limit int) ([]domain.Article, error)
+	GetById(ctx context.Context, uid, id int64) (domain.Article, error)
+	// Withdraw 撤回，撤回之后读者就看不到了，作者自己还能看到
+	Withdraw(ctx context.Context, uid, id int64) error
+	// GetPublishedById 读者查看线上库的帖子
+	GetPublishedById(ctx context.Context, id int64) (domain.Article, error)
+}
+
+type NormalArticleService struct {
+	repo     repository.ArticleRepository
+	userRepo repository.UserRepository
+}
+
+func NewArticleService(repo repository.ArticleRepository, userRepo repository.UserRepository) ArticleService {
+	return &NormalArticleService{
+		repo:     repo,
+		userRepo: userRepo,
+	}
+}
+
+// Save 保存草稿，id 为 0 就是新建，否则就是更新
+func (svc *NormalArticleService) Save(ctx context.Context, art domain.Article) (int64, error) {
+	art.Status = domain.ArticleStatusUnpublished
+	return svc.save(ctx, art)
+}
+
+// Publish 制作库和线上库一起更新，作者之后继续编辑的是制作库的草稿
+func (svc *NormalArticleService) Publish(ctx context.Context, art domain.Article) (int64, error) {
+	art.Status = domain.ArticleStatusPublished
+	id, err := svc.repo.Sync(ctx, art)
+	if err == repository.ErrPossibleIncorrectAuthor {
+		return 0, ErrArticleNotAuthor
+	}
+	return id, err
+}
+
+func (svc *NormalArticleService) Withdraw(ctx context.Context, uid, id int64) error {
+	err := svc.repo.SyncStatus(ctx, uid, id, domain.ArticleStatusPrivate)
+	if err == repository.ErrPossibleIncorrectAuthor {
+		return ErrArticleNotAuthor
+	}
+	return err
+}
+
+func (svc *NormalArticleService) save(ctx context.Context, art domain.Article) (int64, error) {
+	if art.Id > 0 {
+		err := svc.repo.Update(ctx, art)
+		if err == repository.ErrPossibleIncorrectAuthor {
+			return 0, ErrArticleNotAuthor
+		}
+		return art.Id, err
+	}
+	return svc.repo.Create(ctx, art)
+}
+
+func (svc *NormalArticleService) List(ctx context.Context, uid int64, offset, limit int) ([]domain.Article, error) {
+	return svc.repo.GetByAuthor(ctx, uid, offset, limit)
+}
+
+// GetById 作者查看自己的帖子，不是自己的不给看
+func (svc *NormalArticleService) GetById(ctx context.Context, uid, id int64) (domain.Article, error) {
+	art, err := svc.repo.GetById(ctx, id)
+	if err != nil {
+		return domain.Article{}, err
+	}
+	if art.Author.Id != uid {
+		// 有人在搞你，要做监控
+		return domain.Article{}, ErrArticleNotAuthor
+	}
+	return art, nil
+}
+
+func (svc *NormalArticleService) GetPublishedById(ctx context.Context, id int64) (domain.Article, error) {
+	art, err := svc.repo.GetPublishedById(ctx, id)
+	if err != nil {
+		return domain.Article{}, err
+	}
+	// 撤回了或者别的状态，对读者来说就是不存在
+	if art.Status != domain.ArticleStatusPublished {
+		return domain.Article{}, ErrArticleNotFound
+	}
+	// 补充作者名字，查不到也不影响读者看帖子
+	author, err := svc.userRepo.FindById(ctx, art.Author.Id)
+	if err == nil {
+		art.Author.Name = author.Nickname
+	}
+	return art, nil
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository"
+	"dream/webook/pkg/diff"
+	"errors"
+)
+
+var (
+	ErrRevisionNotFound = repository.ErrRevisionNotFound
+	// ErrRevisionMismatch 版本不属于指定的帖子
+	ErrRevisionMismatch = errors.New("版本不属于该帖子")
+)
+
+// ArticleRevisionService 帖子的历史版本，版本在每次保存的时候自动生成
+type ArticleRevisionService interface {
+	List(ctx context.Context, uid, artId int64, offset, limit int) ([]domain.ArticleRevision, error)
+	Get(ctx context.Context, uid, id int64) (domain.ArticleRevision, error)
+	// Diff 按行比较同一个帖子的两个版本，from 是旧版本
+	Diff(ctx context.Context, uid, from, to int64) ([]diff.Line, error)
+	// Restore 用历史版本覆盖当前草稿，覆盖本身也会产生一个新的版本
+	Restore(ctx context.Context, uid, artId, id int64) error
+}
+
+type NormalArticleRevisionService struct {
+	repo    repository.ArticleRevisionRepository
+	artRepo repository.ArticleRepository
+}
+
+func NewArticleRevisionService(repo repository.ArticleRevisionRepository,
+	artRepo repository.ArticleRepository) ArticleRevisionService {
+	return &NormalArticleRevisionService{
+		repo:    repo,
+		artRepo: artRepo,
+	}
+}
+
+func (svc *NormalArticleRevisionService) List(ctx context.Context, uid, artId int64, offset, limit int) ([]domain.ArticleRevision, error) {
+	art, err := svc.artRepo.GetById(ctx, artId)
+	if err != nil {
+		return nil, err
+	}
+	if art.Author.Id != uid {
+		return nil, ErrArticleNotAuthor
+	}
+	return svc.repo.ListByArticle(ctx, artId, offset, limit)
+}
+
+func (svc *NormalArticleRevisionService) Get(ctx context.Context, uid, id int64) (domain.ArticleRevision, error) {
+	rev, err := svc.repo.GetById(ctx, id)
+	if err != nil {
+		return domain.ArticleRevision{}, err
+	}
+	if rev.Author.Id != uid {
+		return domain.ArticleRevision{}, ErrArticleNotAuthor
+	}
+	return rev, nil
+}
+
+func (svc *NormalArticleRevisionService) Diff(ctx context.Context, uid, from, to int64) ([]diff.Line, error) {
+	fromRev, err := svc.Get(ctx, uid, from)
+	if err != nil {
+		return nil, err
+	}
+	toRev, err := svc.Get(ctx, uid, to)
+	if err != nil {
+		return nil, err
+	}
+	if fromRev.ArticleId != toRev.ArticleId {
+		return nil, ErrRevisionMismatch
+	}
+	return diff.Lines(fromRev.Content, toRev.Content), nil
+}
+
+func (svc *NormalArticleRevisionService) Restore(ctx context.Context, uid, artId, id int64) error {
+	rev, err := svc.Get(ctx, uid, id)
+	if err != nil {
+		return err
+	}
+	if rev.ArticleId != artId {
+		return ErrRevisionMismatch
+	}
+	// 恢复的是草稿，线上库不受影响，要作者重新发表
+	err = svc.artRepo.Update(ctx, domain.Article{
+		Id:      rev.ArticleId,
+		Title:   rev.Title,
+		Content: rev.Content,
+		Author:  rev.Author,
+		Status:  domain.ArticleStatusUnpublished,
+	})
+	if err == repository.ErrPossibleIncorrectAuthor {
+		return ErrArticleNotAuthor
+	}
+	return err
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository"
+	repomocks "dream/webook/internal/repository/mocks"
+	"dream/webook/pkg/diff"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNormalArticleRevisionService_List(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.ArticleRevisionRepository, repository.ArticleRepository)
+
+		wantRevs []domain.ArticleRevision
+		wantErr  error
+	}{
+		{
+			name: "查看自己帖子的版本",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRevisionRepository, repository.ArticleRepository) {
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().GetById(gomock.Any(), int64(1)).Return(domain.Article{
+					Id: 1, Author: domain.Author{Id: 123},
+				}, nil)
+				repo := repomocks.NewMockArticleRevisionRepository(ctrl)
+				repo.EXPECT().ListByArticle(gomock.Any(), int64(1), 0, 10).Return([]domain.ArticleRevision{
+					{Id: 2, ArticleId: 1}, {Id: 1, ArticleId: 1},
+				}, nil)
+				return repo, artRepo
+			},
+			wantRevs: []domain.ArticleRevision{{Id: 2, ArticleId: 1}, {Id: 1, ArticleId: 1}},
+		},
+		{
+			name: "别人的帖子",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRevisionRepository, repository.ArticleRepository) {
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().GetById(gomock.Any(), int64(1)).Return(domain.Article{
+					Id: 1, Author: domain.Author{Id: 456},
+				}, nil)
+				return repomocks.NewMockArticleRevisionRepository(ctrl), artRepo
+			},
+			wantErr: ErrArticleNotAuthor,
+		},
+		{
+			name: "查询帖子失败",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRevisionRepository, repository.ArticleRepository) {
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().GetById(gomock.Any(), int64(1)).
+					Return(domain.Article{}, errors.New("mock db error"))
+				return repomocks.NewMockArticleRevisionRepository(ctrl), artRepo
+			},
+			wantErr: errors.New("mock db error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewArticleRevisionService(tc.mock(ctrl))
+			revs, err := svc.List(context.Background(), 123, 1, 0, 10)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantRevs, revs)
+		})
+	}
+}
+
+func TestNormalArticleRevisionService_Diff(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.ArticleRevisionRepository
+
+		wantLines []diff.Line
+		wantErr   error
+	}{
+		{
+			name: "比较成功",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRevisionRepository {
+				repo := repomocks.NewMockArticleRevisionRepository(ctrl)
+				repo.EXPECT().GetById(gomock.Any(), int64(1)).Return(domain.ArticleRevision{
+					Id: 1, ArticleId: 10, Author: domain.Author{Id: 123}, Content: "a\nb",
+				}, nil)
+				repo.EXPECT().GetById(gomock.Any(), int64(2)).Return(domain.ArticleRevision{
+					Id: 2, ArticleId: 10, Author: domain.Author{Id: 123}, Content: "a\nc",
+				}, nil)
+				return repo
+			},
+			wantLines: []diff.Line{
+				{Op: diff.OpEqual, Text: "a"},
+				{Op: diff.OpDelete, Text: "b"},
+				{Op: diff.OpInsert, Text: "c"},
+			},
+		},
+		{
+			name: "别人的版本",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRevisionRepository {
+				repo := repomocks.NewMockArticleRevisionRepository(ctrl)
+				repo.EXPECT().GetById(gomock.Any(), int64(1)).Return(domain.ArticleRevision{
+					Id: 1, ArticleId: 10, Author: domain.Author{Id: 456},
+				}, nil)
+				return repo
+			},
+			wantErr: ErrArticleNotAuthor,
+		},
+		{
+			name: "两个版本不是同一个帖子的",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRevisionRepository {
+				repo := repomocks.NewMockArticleRevisionRepository(ctrl)
+				repo.EXPECT().GetById(gomock.Any(), int64(1)).Return(domain.ArticleRevision{
+					Id: 1, ArticleId: 10, Author: domain.Author{Id: 123},
+				}, nil)
+				repo.EXPECT().GetById(gomock.Any(), int64(2)).Return(domain.ArticleRevision{
+					Id: 2, ArticleId: 11, Author: domain.Author{Id: 123},
+				}, nil)
+				return repo
+			},
+			wantErr: ErrRevisionMismatch,
+		},
+		{
+			name: "版本不存在",
+			mock: func(ctrl *gomock.Controller) repository.ArticleRevisionRepository {
+				repo := repomocks.NewMockArticleRevisionRepository(ctrl)
+				repo.EXPECT().GetById(gomock.Any(), int64(1)).
+					Return(domain.ArticleRevision{}, ErrRevisionNotFound)
+				return repo
+			},
+			wantErr: ErrRevisionNotFound,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewArticleRevisionService(tc.mock(ctrl), repomocks.NewMockArticleRepository(ctrl))
+			lines, err := svc.Diff(context.Background(), 123, 1, 2)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantLines, lines)
+		})
+	}
+}
+
+func TestNormalArticleRevisionService_Restore(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.ArticleRevisionRepository, repository.ArticleRepository)
+
+		wantErr error
+	}{
+		{
+			name: "恢复成草稿",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRevisionRepository, repository.ArticleRepository) {
+				repo := repomocks.NewMockArticleRevisionRepository(ctrl)
+				repo.EXPECT().GetById(gomock.Any(), int64(2)).Return(domain.ArticleRevision{
+					Id: 2, ArticleId: 1, Author: domain.Author{Id: 123}, Title: "旧标题", Content: "旧内容",
+				}, nil)
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().Update(gomock.Any(), domain.Article{
+					Id:      1,
+					Title:   "旧标题",
+					Content: "旧内容",
+					Author:  domain.Author{Id: 123},
+					Status:  domain.ArticleStatusUnpublished,
+				}).Return(nil)
+				return repo, artRepo
+			},
+		},
+		{
+			name: "别人的版本",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRevisionRepository, repository.ArticleRepository) {
+				repo := repomocks.NewMockArticleRevisionRepository(ctrl)
+				repo.EXPECT().GetById(gomock.Any(), int64(2)).Return(domain.ArticleRevision{
+					Id: 2, ArticleId: 1, Author: domain.Author{Id: 456},
+				}, nil)
+				return repo, repomocks.NewMockArticleRepository(ctrl)
+			},
+			wantErr: ErrArticleNotAuthor,
+		},
+		{
+			name: "版本属于另一个帖子",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRevisionRepository, repository.ArticleRepository) {
+				repo := repomocks.NewMockArticleRevisionRepository(ctrl)
+				repo.EXPECT().GetById(gomock.Any(), int64(2)).Return(domain.ArticleRevision{
+					Id: 2, ArticleId: 3, Author: domain.Author{Id: 123},
+				}, nil)
+				return repo, repomocks.NewMockArticleRepository(ctrl)
+			},
+			wantErr: ErrRevisionMismatch,
+		},
+		{
+			name: "帖子作者变了",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRevisionRepository, repository.ArticleRepository) {
+				repo := repomocks.NewMockArticleRevisionRepository(ctrl)
+				repo.EXPECT().GetById(gomock.Any(), int64(2)).Return(domain.ArticleRevision{
+					Id: 2, ArticleId: 1, Author: domain.Author{Id: 123},
+				}, nil)
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().Update(gomock.Any(), gomock.Any()).
+					Return(repository.ErrPossibleIncorrectAuthor)
+				return repo, artRepo
+			},
+			wantErr: ErrArticleNotAuthor,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewArticleRevisionService(tc.mock(ctrl))
+			err := svc.Restore(context.Background(), 123, 1, 2)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository"
+	"errors"
+
+	"github.com/ecodeclub/ekit/slice"
+	"golang.org/x/sync/errgroup"
+)
+
+var (
+	ErrCollectionNotFound = repository.ErrCollectionNotFound
+	// ErrCollectionPrivate 私密收藏夹只有自己能看
+	ErrCollectionPrivate = errors.New("收藏夹是私密的")
+)
+
+// CollectionService 收藏夹，收藏本身走 InteractiveService
+type CollectionService interface {
+	Create(ctx context.Context, c domain.Collection) (int64, error)
+	// CreateDefault 注册之后创建默认收藏夹，已经有了就什么都不做
+	CreateDefault(ctx context.Context, uid int64) error
+	Update(ctx context.Context, c domain.Collection) error
+	// Delete 删除收藏夹，里面的收藏记录一起删掉，默认收藏夹不能删
+	Delete(ctx context.Context, uid, id int64) error
+	// List 查看 owner 的收藏夹，不是自己的只能看到公开的
+	List(ctx context.Context, uid, owner int64) ([]domain.Collection, error)
+	// Items 分页查看收藏夹里面的帖子
+	Items(ctx context.Context, uid, cid int64, offset, limit int) ([]domain.CollectionItem, error)
+	// Move 把帖子从一个收藏夹挪到另一个收藏夹
+	Move(ctx context.Context, uid, artId, from, to int64) error
+}
+
+type NormalCollectionService struct {
+	repo     repository.CollectionRepository
+	artRepo  repository.ArticleRepository
+	intrRepo repository.InteractiveRepository
+	// 收藏夹里面放的都是帖子
+	biz string
+}
+
+func NewCollectionService(repo repository.CollectionRepository, artRepo repository.ArticleRepository,
+	intrRepo repository.InteractiveRepository) CollectionService {
+	return &NormalCollectionService{
+		repo:     repo,
+		artRepo:  artRepo,
+		intrRepo: intrRepo,
+		biz:      "article",
+	}
+}
+
+func (svc *NormalCollectionService) Create(ctx context.Context, c domain.Collection) (int64, error) {
+	// 默认收藏夹只能由系统创建
+	c.Default = false
+	return svc.repo.Create(ctx, c)
+}
+
+func (svc *NormalCollectionService) CreateDefault(ctx context.Context, uid int64) error {
+	_, err := svc.repo.FindOrCreateDefault(ctx, uid)
+	return err
+}
+
+func (svc *NormalCollectionService) Update(ctx context.Context, c domain.Collection) error {
+	err := svc.repo.Update(ctx, c)
+	if err == repository.ErrPossibleIncorrectOwner {
+		return ErrCollectionNotOwner
+	}
+	return err
+}
+
+func (svc *NormalCollectionService) Delete(ctx context.Context, uid, id int64) error {
+	err := svc.repo.Delete(ctx, uid, id)
+	if err == repository.ErrPossibleIncorrectOwner {
+		return ErrCollectionNotOwner
+	}
+	return err
+}
+
+func (svc *NormalCollectionService) List(ctx context.Context, uid, owner int64) ([]domain.Collection, error) {
+	cs, err := svc.repo.FindByUid(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	if uid == owner {
+		return cs, nil
+	}
+	return slice.FilterMap(cs, func(idx int, src domain.Collection) (domain.Collection, bool) {
+		return src, !src.Private
+	}), nil
+}
+
+func (svc *NormalCollectionService) Items(ctx context.Context, uid, cid int64, offset, limit int) ([]domain.CollectionItem, error) {
+	c, err := svc.repo.FindById(ctx, cid)
+	if err != nil {
+		return nil, err
+	}
+	if c.Private && c.Uid != uid {
+		return nil, ErrCollectionPrivate
+	}
+	items, err := svc.repo.ListItems(ctx, cid, offset, limit)
+	if err != nil || len(items) == 0 {
+		return items, err
+	}
+	ids := slice.Map(items, func(idx int, src domain.CollectionItem) int64 {
+		return src.Article.Id
+	})
+
+	var (
+		eg    errgroup.Group
+		arts  []domain.Article
+		intrs map[int64]domain.Interactive
+	)
+	eg.Go(func() error {
+		var er error
+		arts, er = svc.artRepo.GetPublishedByIds(ctx, ids)
+		return er
+	})
+	eg.Go(func() error {
+		var er error
+		intrs, er = svc.intrRepo.GetByIds(ctx, svc.biz, ids)
+		return er
+	})
+	if err = eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	artMap := make(map[int64]domain.Article, len(arts))
+	for _, art := range arts {
+		artMap[art.Id] = art
+	}
+	for i := range items {
+		id := items[i].Article.Id
+		// 帖子被撤回之后，只保留 id，前端展示为已失效
+		if art, ok := artMap[id]; ok && art.Status == domain.ArticleStatusPublished {
+			items[i].Article = art
+		}
+		if intr, ok := intrs[id]; ok {
+			items[i].Interactive = intr
+		}
+	}
+	return items, nil
+}
+
+func (svc *NormalCollectionService) Move(ctx context.Context, uid, artId, from, to int64) error {
+	if from == to {
+		return nil
+	}
+	c, err := svc.repo.FindById(ctx, to)
+	if err == repository.ErrCollectionNotFound {
+		return ErrCollectionNotOwner
+	}
+	if err != nil {
+		return err
+	}
+	if c.Uid != uid {
+		return ErrCollectionNotOwner
+	}
+	err = svc.repo.MoveItem(ctx, uid, svc.biz, artId, from, to)
+	if err == repository.ErrPossibleIncorrectOwner {
+		return ErrCollectionNotOwner
+	}
+	return err
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository"
+	repomocks "dream/webook/internal/repository/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNormalCollectionService_Items(t *testing.T) {
+	now := time.UnixMilli(time.Now().UnixMilli())
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.CollectionRepository,
+			repository.ArticleRepository, repository.InteractiveRepository)
+		uid int64
+
+		wantItems []domain.CollectionItem
+		wantErr   error
+	}{
+		{
+			name: "别人的私密收藏夹",
+			mock: func(ctrl *gomock.Controller) (repository.CollectionRepository,
+				repository.ArticleRepository, repository.InteractiveRepository) {
+				cr := repomocks.NewMockCollectionRepository(ctrl)
+				cr.EXPECT().FindById(gomock.Any(), int64(1)).Return(domain.Collection{
+					Id: 1, Uid: 123, Private: true,
+				}, nil)
+				return cr, repomocks.NewMockArticleRepository(ctrl), repomocks.NewMockInteractiveRepository(ctrl)
+			},
+			uid:     456,
+			wantErr: ErrCollectionPrivate,
+		},
+		{
+			name: "自己的私密收藏夹，撤回的帖子只保留id",
+			mock: func(ctrl *gomock.Controller) (repository.CollectionRepository,
+				repository.ArticleRepository, repository.InteractiveRepository) {
+				cr := repomocks.NewMockCollectionRepository(ctrl)
+				cr.EXPECT().FindById(gomock.Any(), int64(1)).Return(domain.Collection{
+					Id: 1, Uid: 123, Private: true,
+				}, nil)
+				cr.EXPECT().ListItems(gomock.Any(), int64(1), 0, 10).Return([]domain.CollectionItem{
+					{Cid: 1, Article: domain.Article{Id: 11}, Ctime: now},
+					{Cid: 1, Article: domain.Article{Id: 12}, Ctime: now},
+				}, nil)
+				ar := repomocks.NewMockArticleRepository(ctrl)
+				ar.EXPECT().GetPublishedByIds(gomock.Any(), []int64{11, 12}).Return([]domain.Article{
+					{Id: 11, Title: "标题", Status: domain.ArticleStatusPublished},
+					{Id: 12, Title: "撤回了", Status: domain.ArticleStatusPrivate},
+				}, nil)
+				ir := repomocks.NewMockInteractiveRepository(ctrl)
+				ir.EXPECT().GetByIds(gomock.Any(), "article", []int64{11, 12}).Return(map[int64]domain.Interactive{
+					11: {Biz: "article", BizId: 11, ReadCnt: 10, CollectCnt: 1},
+				}, nil)
+				return cr, ar, ir
+			},
+			uid: 123,
+			wantItems: []domain.CollectionItem{
+				{
+					Cid:         1,
+					Article:     domain.Article{Id: 11, Title: "标题", Status: domain.ArticleStatusPublished},
+					Interactive: domain.Interactive{Biz: "article", BizId: 11, ReadCnt: 10, CollectCnt: 1},
+					Ctime:       now,
+				},
+				{Cid: 1, Article: domain.Article{Id: 12}, Ctime: now},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewCollectionService(tc.mock(ctrl))
+			items, err := svc.Items(context.Background(), tc.uid, 1, 0, 10)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantItems, items)
+		})
+	}
+}
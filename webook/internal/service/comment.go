@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository"
+	"errors"
+)
+
+var (
+	ErrCommentNotFound = repository.ErrCommentNotFound
+	// ErrCommentNotOwner 只有评论的人和帖子的作者能删除评论
+	ErrCommentNotOwner = errors.New("评论不属于该用户")
+	// ErrCommentParentInvalid 回复的评论不存在、已经删除，或者不是同一个帖子下面的
+	ErrCommentParentInvalid = errors.New("回复的评论不对")
+)
+
+type CommentService interface {
+	// Create 评论或者回复，ParentId 是 0 的时候是根评论
+	Create(ctx context.Context, c domain.Comment) (int64, error)
+	// ListRoots 游标分页查询根评论，maxId 是上一页最后一条的 id
+	ListRoots(ctx context.Context, biz string, bizId, maxId int64, limit int) ([]domain.Comment, error)
+	// ListReplies 展开一条根评论下面的回复，minId 是上一页最后一条的 id
+	ListReplies(ctx context.Context, rootId, minId int64, limit int) ([]domain.Comment, error)
+	// Delete 评论的人和帖子的作者都可以删
+	Delete(ctx context.Context, id, uid int64) error
+}
+
+type NormalCommentService struct {
+	repo    repository.CommentRepository
+	artRepo repository.ArticleRepository
+}
+
+func NewCommentService(repo repository.CommentRepository, artRepo repository.ArticleRepository) CommentService {
+	return &NormalCommentService{
+		repo:    repo,
+		artRepo: artRepo,
+	}
+}
+
+func (svc *NormalCommentService) Create(ctx context.Context, c domain.Comment) (int64, error) {
+	// 只能评论已经发表的帖子
+	if err := svc.checkBiz(ctx, c.Biz, c.BizId); err != nil {
+		return 0, err
+	}
+	if c.ParentId == 0 {
+		c.RootId = 0
+		return svc.repo.Create(ctx, c)
+	}
+	parent, err := svc.repo.FindById(ctx, c.ParentId)
+	if err == repository.ErrCommentNotFound {
+		return 0, ErrCommentParentInvalid
+	}
+	if err != nil {
+		return 0, err
+	}
+	if parent.Deleted || parent.Biz != c.Biz || parent.BizId != c.BizId {
+		return 0, ErrCommentParentInvalid
+	}
+	// 回复的回复，还是挂在同一条根评论下面
+	c.RootId = parent.RootId
+	if c.RootId == 0 {
+		c.RootId = parent.Id
+	}
+	return svc.repo.Create(ctx, c)
+}
+
+func (svc *NormalCommentService) ListRoots(ctx context.Context, biz string, bizId, maxId int64, limit int) ([]domain.Comment, error) {
+	return svc.repo.FindRoots(ctx, biz, bizId, maxId, limit)
+}
+
+func (svc *NormalCommentService) ListReplies(ctx context.Context, rootId, minId int64, limit int) ([]domain.Comment, error) {
+	return svc.repo.FindReplies(ctx, rootId, minId, limit)
+}
+
+func (svc *NormalCommentService) Delete(ctx context.Context, id, uid int64) error {
+	c, err := svc.repo.FindById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if c.Deleted {
+		return nil
+	}
+	if c.Uid != uid {
+		// 别的业务不知道资源是谁的，只能删自己的评论
+		if c.Biz != bizArticle {
+			return ErrCommentNotOwner
+		}
+		// 不是自己的评论，看看是不是自己帖子下面的评论，撤回了的帖子作者也能删
+		art, err := svc.artRepo.GetPublishedById(ctx, c.BizId)
+		if err == repository.ErrArticleNotFound {
+			return ErrCommentNotOwner
+		}
+		if err != nil {
+			return err
+		}
+		if art.Author.Id != uid {
+			return ErrCommentNotOwner
+		}
+	}
+	return svc.repo.Delete(ctx, id)
+}
+
+// checkBiz 帖子要已经发表，别的业务由调用方保证 bizId 是对的
+func (svc *NormalCommentService) checkBiz(ctx context.Context, biz string, bizId int64) error {
+	if biz != bizArticle {
+		return nil
+	}
+	art, err := svc.artRepo.GetPublishedById(ctx, bizId)
+	if err != nil {
+		return err
+	}
+	// 撤回了的帖子不能再评论
+	if art.Status != domain.ArticleStatusPublished {
+		return ErrArticleNotFound
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository"
+	repomocks "dream/webook/internal/repository/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNormalCommentService_Create(t *testing.T) {
+	testCases := []struct {
+		name    string
+		mock    func(ctrl *gomock.Controller) (repository.CommentRepository, repository.ArticleRepository)
+		comment domain.Comment
+
+		wantId  int64
+		wantErr error
+	}{
+		{
+			name: "根评论",
+			mock: func(ctrl *gomock.Controller) (repository.CommentRepository, repository.ArticleRepository) {
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().GetPublishedById(gomock.Any(), int64(1)).
+					Return(domain.Article{Id: 1, Status: domain.ArticleStatusPublished}, nil)
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				repo.EXPECT().Create(gomock.Any(), domain.Comment{
+					Uid: 123, Biz: "article", BizId: 1, Content: "沙发",
+				}).Return(int64(10), nil)
+				return repo, artRepo
+			},
+			comment: domain.Comment{Uid: 123, Biz: "article", BizId: 1, Content: "沙发"},
+			wantId:  10,
+		},
+		{
+			name: "回复的回复，挂在同一条根评论下面",
+			mock: func(ctrl *gomock.Controller) (repository.CommentRepository, repository.ArticleRepository) {
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().GetPublishedById(gomock.Any(), int64(1)).
+					Return(domain.Article{Id: 1, Status: domain.ArticleStatusPublished}, nil)
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(11)).Return(domain.Comment{
+					Id: 11, Biz: "article", BizId: 1, RootId: 10, ParentId: 10,
+				}, nil)
+				repo.EXPECT().Create(gomock.Any(), domain.Comment{
+					Uid: 123, Biz: "article", BizId: 1, RootId: 10, ParentId: 11, Content: "同意",
+				}).Return(int64(12), nil)
+				return repo, artRepo
+			},
+			comment: domain.Comment{Uid: 123, Biz: "article", BizId: 1, ParentId: 11, Content: "同意"},
+			wantId:  12,
+		},
+		{
+			name: "回复别的帖子下面的评论",
+			mock: func(ctrl *gomock.Controller) (repository.CommentRepository, repository.ArticleRepository) {
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().GetPublishedById(gomock.Any(), int64(1)).
+					Return(domain.Article{Id: 1, Status: domain.ArticleStatusPublished}, nil)
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(11)).Return(domain.Comment{
+					Id: 11, Biz: "article", BizId: 2,
+				}, nil)
+				return repo, artRepo
+			},
+			comment: domain.Comment{Uid: 123, Biz: "article", BizId: 1, ParentId: 11, Content: "同意"},
+			wantErr: ErrCommentParentInvalid,
+		},
+		{
+			name: "帖子已经撤回",
+			mock: func(ctrl *gomock.Controller) (repository.CommentRepository, repository.ArticleRepository) {
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().GetPublishedById(gomock.Any(), int64(1)).
+					Return(domain.Article{Id: 1, Status: domain.ArticleStatusPrivate}, nil)
+				return repomocks.NewMockCommentRepository(ctrl), artRepo
+			},
+			comment: domain.Comment{Uid: 123, Biz: "article", BizId: 1, Content: "沙发"},
+			wantErr: ErrArticleNotFound,
+		},
+		{
+			name: "别的业务不查帖子",
+			mock: func(ctrl *gomock.Controller) (repository.CommentRepository, repository.ArticleRepository) {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				repo.EXPECT().Create(gomock.Any(), domain.Comment{
+					Uid: 123, Biz: "video", BizId: 1, Content: "沙发",
+				}).Return(int64(10), nil)
+				return repo, repomocks.NewMockArticleRepository(ctrl)
+			},
+			comment: domain.Comment{Uid: 123, Biz: "video", BizId: 1, Content: "沙发"},
+			wantId:  10,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewCommentService(tc.mock(ctrl))
+			id, err := svc.Create(context.Background(), tc.comment)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantId, id)
+		})
+	}
+}
+
+func TestNormalCommentService_Delete(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.CommentRepository, repository.ArticleRepository)
+		uid  int64
+
+		wantErr error
+	}{
+		{
+			name: "删除自己的评论",
+			mock: func(ctrl *gomock.Controller) (repository.CommentRepository, repository.ArticleRepository) {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(10)).
+					Return(domain.Comment{Id: 10, Uid: 123, Biz: "article", BizId: 1}, nil)
+				repo.EXPECT().Delete(gomock.Any(), int64(10)).Return(nil)
+				return repo, repomocks.NewMockArticleRepository(ctrl)
+			},
+			uid: 123,
+		},
+		{
+			name: "作者删除自己帖子下面的评论",
+			mock: func(ctrl *gomock.Controller) (repository.CommentRepository, repository.ArticleRepository) {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(10)).
+					Return(domain.Comment{Id: 10, Uid: 123, Biz: "article", BizId: 1}, nil)
+				repo.EXPECT().Delete(gomock.Any(), int64(10)).Return(nil)
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().GetPublishedById(gomock.Any(), int64(1)).
+					Return(domain.Article{Id: 1, Author: domain.Author{Id: 456}}, nil)
+				return repo, artRepo
+			},
+			uid: 456,
+		},
+		{
+			name: "删除别人的评论",
+			mock: func(ctrl *gomock.Controller) (repository.CommentRepository, repository.ArticleRepository) {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(10)).
+					Return(domain.Comment{Id: 10, Uid: 123, Biz: "article", BizId: 1}, nil)
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().GetPublishedById(gomock.Any(), int64(1)).
+					Return(domain.Article{Id: 1, Author: domain.Author{Id: 456}}, nil)
+				return repo, artRepo
+			},
+			uid:     789,
+			wantErr: ErrCommentNotOwner,
+		},
+		{
+			name: "别的业务只能删自己的评论",
+			mock: func(ctrl *gomock.Controller) (repository.CommentRepository, repository.ArticleRepository) {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(10)).
+					Return(domain.Comment{Id: 10, Uid: 123, Biz: "video", BizId: 1}, nil)
+				return repo, repomocks.NewMockArticleRepository(ctrl)
+			},
+			uid:     456,
+			wantErr: ErrCommentNotOwner,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewCommentService(tc.mock(ctrl))
+			err := svc.Delete(context.Background(), 10, tc.uid)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository"
+	"dream/webook/pkg/events"
+	"errors"
+)
+
+var (
+	ErrDeadLetterNotFound = repository.ErrDeadLetterNotFound
+	ErrDeadLetterReplayed = errors.New("死信已经重放过了")
+)
+
+type DeadLetterService interface {
+	// Save 消费者重试完了还是失败，保存下来等人工处理
+	Save(ctx context.Context, dl events.DeadLetter) error
+	// List group 为空就是所有消费者组
+	List(ctx context.Context, group string, offset, limit int) ([]domain.DeadLetter, error)
+	// Replay 重新投递给原来的消费者组，每条死信只能重放一次
+	Replay(ctx context.Context, id int64) error
+}
+
+type EventDeadLetterService struct {
+	repo     repository.DeadLetterRepository
+	producer events.Producer
+}
+
+func NewDeadLetterService(repo repository.DeadLetterRepository, producer events.Producer) DeadLetterService {
+	return &EventDeadLetterService{
+		repo:     repo,
+		producer: producer,
+	}
+}
+
+func (svc *EventDeadLetterService) Save(ctx context.Context, dl events.DeadLetter) error {
+	return svc.repo.Create(ctx, domain.DeadLetter{
+		Group: dl.Group,
+		Topic: dl.Topic,
+		Key:   dl.Key,
+		Value: dl.Value,
+		Err:   dl.Err,
+	})
+}
+
+func (svc *EventDeadLetterService) List(ctx context.Context, group string, offset, limit int) ([]domain.DeadLetter, error) {
+	return svc.repo.List(ctx, group, offset, limit)
+}
+
+func (svc *EventDeadLetterService) Replay(ctx context.Context, id int64) error {
+	dl, err := svc.repo.FindById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if dl.Replayed {
+		return ErrDeadLetterReplayed
+	}
+	// 先抢占，防止两个管理员同时重放
+	err = svc.repo.MarkReplayed(ctx, id)
+	if err == repository.ErrDeadLetterStatusChanged {
+		return ErrDeadLetterReplayed
+	}
+	if err != nil {
+		return err
+	}
+	err = events.Replay(ctx, svc.producer, events.DeadLetter{
+		Group: dl.Group,
+		Topic: dl.Topic,
+		Key:   dl.Key,
+		Value: dl.Value,
+	})
+	if err != nil {
+		// 改回来，让管理员可以再试一次
+		_ = svc.repo.UnmarkReplayed(ctx, id)
+		return err
+	}
+	return nil
+}
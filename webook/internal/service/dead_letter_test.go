@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository"
+	repomocks "dream/webook/internal/repository/mocks"
+	"dream/webook/pkg/events"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestEventDeadLetterService_Replay(t *testing.T) {
+	dl := domain.DeadLetter{
+		Id:    1,
+		Group: "interactive",
+		Topic: "article_read",
+		Key:   []byte("11"),
+		Value: []byte(`{"aid":11}`),
+	}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.DeadLetterRepository
+		// ctx 取消了的话内存消息队列发送失败
+		ctx func() context.Context
+
+		wantErr    error
+		wantReplay bool
+	}{
+		{
+			name: "重放成功",
+			mock: func(ctrl *gomock.Controller) repository.DeadLetterRepository {
+				repo := repomocks.NewMockDeadLetterRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(dl, nil)
+				repo.EXPECT().MarkReplayed(gomock.Any(), int64(1)).Return(nil)
+				return repo
+			},
+			ctx:        context.Background,
+			wantReplay: true,
+		},
+		{
+			name: "已经重放过了",
+			mock: func(ctrl *gomock.Controller) repository.DeadLetterRepository {
+				repo := repomocks.NewMockDeadLetterRepository(ctrl)
+				replayed := dl
+				replayed.Replayed = true
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(replayed, nil)
+				return repo
+			},
+			ctx:     context.Background,
+			wantErr: ErrDeadLetterReplayed,
+		},
+		{
+			name: "同时重放，没抢到",
+			mock: func(ctrl *gomock.Controller) repository.DeadLetterRepository {
+				repo := repomocks.NewMockDeadLetterRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(dl, nil)
+				repo.EXPECT().MarkReplayed(gomock.Any(), int64(1)).Return(repository.ErrDeadLetterStatusChanged)
+				return repo
+			},
+			ctx:     context.Background,
+			wantErr: ErrDeadLetterReplayed,
+		},
+		{
+			name: "发送失败，改回来",
+			mock: func(ctrl *gomock.Controller) repository.DeadLetterRepository {
+				repo := repomocks.NewMockDeadLetterRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(dl, nil)
+				repo.EXPECT().MarkReplayed(gomock.Any(), int64(1)).Return(nil)
+				repo.EXPECT().UnmarkReplayed(gomock.Any(), int64(1)).Return(nil)
+				return repo
+			},
+			ctx: func() context.Context {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx
+			},
+			wantErr: context.Canceled,
+		},
+		{
+			name: "死信不存在",
+			mock: func(ctrl *gomock.Controller) repository.DeadLetterRepository {
+				repo := repomocks.NewMockDeadLetterRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(domain.DeadLetter{}, ErrDeadLetterNotFound)
+				return repo
+			},
+			ctx:     context.Background,
+			wantErr: ErrDeadLetterNotFound,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			broker := events.NewMemoryBroker(1)
+			producer, err := broker.Producer()
+			require.NoError(t, err)
+			svc := NewDeadLetterService(tc.mock(ctrl), producer)
+			err = svc.Replay(tc.ctx(), 1)
+			assert.True(t, errors.Is(err, tc.wantErr))
+			if !tc.wantReplay {
+				return
+			}
+			// 只有原来的消费者组会收到，topic 换回原来的
+			consumer, err := broker.Consumer("check")
+			require.NoError(t, err)
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			var got *events.Message
+			_ = consumer.Consume(ctx, []string{events.ReplayTopic("interactive")}, func(ctx context.Context, msg *events.Message) error {
+				got = msg
+				cancel()
+				return nil
+			})
+			require.NotNil(t, got)
+			assert.Equal(t, "article_read", got.Headers[events.HeaderOriginTopic])
+			assert.Equal(t, dl.Value, got.Value)
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+)
+
+// FeedService 关注的人发表的帖子，推拉结合
+// 粉丝少的作者发表之后直接推到每个粉丝的收件箱
+// 粉丝多的作者只写自己的发件箱，读的时候再拉，两边合并起来就是时间线
+type FeedService interface {
+	// PublishArticle 帖子发表之后写时间线
+	PublishArticle(ctx context.Context, art domain.Article) error
+	// Feed 按照发表时间倒序，before 和 beforeId 是上一页最后一条的时间和帖子 id，第一页传零值
+	// 撤回了的帖子也在里面，状态不是已发表，游标要靠它往后走
+	Feed(ctx context.Context, uid int64, before time.Time, beforeId int64, limit int) ([]domain.FeedEvent, error)
+}
+
+type HybridFeedService struct {
+	repo       repository.FeedRepository
+	followRepo repository.FollowRepository
+	artRepo    repository.ArticleRepository
+	// threshold 粉丝数达到这个数就不推了，改成拉
+	threshold int64
+	batchSize int
+	// maxFollowees 拉的时候最多看这么多个关注的人
+	maxFollowees int
+}
+
+func NewFeedService(repo repository.FeedRepository, followRepo repository.FollowRepository,
+	artRepo repository.ArticleRepository) FeedService {
+	return &HybridFeedService{
+		repo:         repo,
+		followRepo:   followRepo,
+		artRepo:      artRepo,
+		threshold:    1000,
+		batchSize:    500,
+		maxFollowees: 2000,
+	}
+}
+
+func (svc *HybridFeedService) PublishArticle(ctx context.Context, art domain.Article) error {
+	// 用发表的时间，消息在队列里面积压、重试也不会把帖子排到后面去
+	pubTime := art.Ctime
+	if pubTime.IsZero() {
+		pubTime = time.Now()
+	}
+	s, err := svc.followRepo.GetStatics(ctx, art.Author.Id)
+	if err != nil {
+		return err
+	}
+	if s.Followers >= svc.threshold {
+		return svc.repo.CreatePullEvent(ctx, art, pubTime)
+	}
+	offset := 0
+	for {
+		rs, err := svc.followRepo.GetFollowers(ctx, art.Author.Id, offset, svc.batchSize)
+		if err != nil {
+			return err
+		}
+		followers := slice.Map(rs, func(idx int, src domain.FollowRelation) int64 {
+			return src.Follower
+		})
+		if err = svc.repo.CreatePushEvents(ctx, art, followers, pubTime); err != nil {
+			return err
+		}
+		if len(rs) < svc.batchSize {
+			return nil
+		}
+		offset += len(rs)
+	}
+}
+
+func (svc *HybridFeedService) Feed(ctx context.Context, uid int64, before time.Time, beforeId int64, limit int) ([]domain.FeedEvent, error) {
+	if before.IsZero() {
+		before, beforeId = time.Now(), math.MaxInt64
+	}
+	events, err := svc.repo.FindPushEvents(ctx, uid, before, beforeId, limit)
+	if err != nil {
+		return nil, err
+	}
+	followees, err := svc.followees(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	pulled, err := svc.repo.FindPullEvents(ctx, followees, before, beforeId, limit)
+	if err != nil {
+		return nil, err
+	}
+	events = svc.merge(append(events, pulled...), limit)
+	ids := slice.Map(events, func(idx int, src domain.FeedEvent) int64 {
+		return src.Article.Id
+	})
+	arts, err := svc.artRepo.GetPublishedByIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	artMap := make(map[int64]domain.Article, len(arts))
+	for _, art := range arts {
+		artMap[art.Id] = art
+	}
+	for i := range events {
+		if art, ok := artMap[events[i].Article.Id]; ok {
+			events[i].Article = art
+		}
+	}
+	return events, nil
+}
+
+// merge 和 dao 一样按照 (ctime, 帖子 id) 倒序。
+// 作者粉丝数越过 threshold 之后重新发表，同一篇帖子两边都有，只留最新的
+func (svc *HybridFeedService) merge(events []domain.FeedEvent, limit int) []domain.FeedEvent {
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].Ctime.Equal(events[j].Ctime) {
+			return events[i].Article.Id > events[j].Article.Id
+		}
+		return events[i].Ctime.After(events[j].Ctime)
+	})
+	seen := make(map[int64]struct{}, len(events))
+	res := make([]domain.FeedEvent, 0, min(len(events), limit))
+	for _, e := range events {
+		if len(res) == limit {
+			break
+		}
+		if _, ok := seen[e.Article.Id]; ok {
+			continue
+		}
+		seen[e.Article.Id] = struct{}{}
+		res = append(res, e)
+	}
+	return res
+}
+
+func (svc *HybridFeedService) followees(ctx context.Context, uid int64) ([]int64, error) {
+	res := make([]int64, 0, svc.batchSize)
+	for len(res) < svc.maxFollowees {
+		rs, err := svc.followRepo.GetFollowees(ctx, uid, len(res), svc.batchSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rs {
+			res = append(res, r.Followee)
+		}
+		if len(rs) < svc.batchSize {
+			break
+		}
+	}
+	return res, nil
+}
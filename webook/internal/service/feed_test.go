@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository"
+	repomocks "dream/webook/internal/repository/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHybridFeedService_PublishArticle(t *testing.T) {
+	pubTime := time.UnixMilli(9000)
+	art := domain.Article{Id: 1, Author: domain.Author{Id: 123}, Ctime: pubTime}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.FeedRepository, repository.FollowRepository)
+
+		wantErr error
+	}{
+		{
+			name: "粉丝少，推到收件箱",
+			mock: func(ctrl *gomock.Controller) (repository.FeedRepository, repository.FollowRepository) {
+				followRepo := repomocks.NewMockFollowRepository(ctrl)
+				followRepo.EXPECT().GetStatics(gomock.Any(), int64(123)).
+					Return(domain.FollowStatics{Followers: 2}, nil)
+				followRepo.EXPECT().GetFollowers(gomock.Any(), int64(123), 0, 500).
+					Return([]domain.FollowRelation{{Follower: 1}, {Follower: 2}}, nil)
+				repo := repomocks.NewMockFeedRepository(ctrl)
+				repo.EXPECT().CreatePushEvents(gomock.Any(), art, []int64{1, 2}, pubTime).Return(nil)
+				return repo, followRepo
+			},
+		},
+		{
+			name: "粉丝多，写发件箱",
+			mock: func(ctrl *gomock.Controller) (repository.FeedRepository, repository.FollowRepository) {
+				followRepo := repomocks.NewMockFollowRepository(ctrl)
+				followRepo.EXPECT().GetStatics(gomock.Any(), int64(123)).
+					Return(domain.FollowStatics{Followers: 1000}, nil)
+				repo := repomocks.NewMockFeedRepository(ctrl)
+				repo.EXPECT().CreatePullEvent(gomock.Any(), art, pubTime).Return(nil)
+				return repo, followRepo
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, followRepo := tc.mock(ctrl)
+			svc := NewFeedService(repo, followRepo, repomocks.NewMockArticleRepository(ctrl))
+			err := svc.PublishArticle(context.Background(), art)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestHybridFeedService_Feed(t *testing.T) {
+	before := time.UnixMilli(10000)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomocks.NewMockFeedRepository(ctrl)
+	repo.EXPECT().FindPushEvents(gomock.Any(), int64(123), before, int64(10), 3).Return([]domain.FeedEvent{
+		{Uid: 123, Article: domain.Article{Id: 1}, Ctime: time.UnixMilli(9000)},
+		{Uid: 123, Article: domain.Article{Id: 2}, Ctime: time.UnixMilli(8000)},
+		{Uid: 123, Article: domain.Article{Id: 5}, Ctime: time.UnixMilli(7000)},
+	}, nil)
+	followRepo := repomocks.NewMockFollowRepository(ctrl)
+	followRepo.EXPECT().GetFollowees(gomock.Any(), int64(123), 0, 500).
+		Return([]domain.FollowRelation{{Follower: 123, Followee: 456}}, nil)
+	repo.EXPECT().FindPullEvents(gomock.Any(), []int64{456}, before, int64(10), 3).Return([]domain.FeedEvent{
+		// 粉丝数越过阈值之后重新发表，收件箱里已经有了
+		{Uid: 456, Article: domain.Article{Id: 1}, Ctime: time.UnixMilli(9500)},
+		// 和帖子 2 同一毫秒发表的
+		{Uid: 456, Article: domain.Article{Id: 3}, Ctime: time.UnixMilli(8000)},
+	}, nil)
+	artRepo := repomocks.NewMockArticleRepository(ctrl)
+	// 帖子 3 撤回了，查不到
+	artRepo.EXPECT().GetPublishedByIds(gomock.Any(), []int64{1, 3, 2}).Return([]domain.Article{
+		{Id: 1, Title: "标题1", Status: domain.ArticleStatusPublished},
+		{Id: 2, Title: "标题2", Status: domain.ArticleStatusPublished},
+	}, nil)
+
+	svc := NewFeedService(repo, followRepo, artRepo)
+	events, err := svc.Feed(context.Background(), 123, before, 10, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.FeedEvent{
+		{Uid: 456, Article: domain.Article{Id: 1, Title: "标题1", Status: domain.ArticleStatusPublished}, Ctime: time.UnixMilli(9500)},
+		{Uid: 456, Article: domain.Article{Id: 3}, Ctime: time.UnixMilli(8000)},
+		{Uid: 123, Article: domain.Article{Id: 2, Title: "标题2", Status: domain.ArticleStatusPublished}, Ctime: time.UnixMilli(8000)},
+	}, events)
+}
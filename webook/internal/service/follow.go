@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository"
+	"errors"
+
+	"github.com/ecodeclub/ekit/slice"
+	"golang.org/x/sync/errgroup"
+)
+
+// ErrFollowSelf 不能关注自己
+var ErrFollowSelf = errors.New("不能关注自己")
+
+type FollowService interface {
+	// Follow 关注，followee 不存在的话返回 ErrUserNotFound
+	Follow(ctx context.Context, follower, followee int64) error
+	CancelFollow(ctx context.Context, follower, followee int64) error
+	// Followers uid 的粉丝，Mutual 表示 uid 也关注了对方
+	Followers(ctx context.Context, uid int64, offset, limit int) ([]domain.FollowUser, error)
+	// Followees uid 关注的人，Mutual 表示对方也关注了 uid
+	Followees(ctx context.Context, uid int64, offset, limit int) ([]domain.FollowUser, error)
+	Statics(ctx context.Context, uid int64) (domain.FollowStatics, error)
+}
+
+type NormalFollowService struct {
+	repo     repository.FollowRepository
+	userRepo repository.UserRepository
+}
+
+func NewFollowService(repo repository.FollowRepository, userRepo repository.UserRepository) FollowService {
+	return &NormalFollowService{
+		repo:     repo,
+		userRepo: userRepo,
+	}
+}
+
+func (svc *NormalFollowService) Follow(ctx context.Context, follower, followee int64) error {
+	if follower == followee {
+		return ErrFollowSelf
+	}
+	if _, err := svc.userRepo.FindById(ctx, followee); err != nil {
+		return err
+	}
+	return svc.repo.AddFollow(ctx, follower, followee)
+}
+
+func (svc *NormalFollowService) CancelFollow(ctx context.Context, follower, followee int64) error {
+	return svc.repo.CancelFollow(ctx, follower, followee)
+}
+
+func (svc *NormalFollowService) Followers(ctx context.Context, uid int64, offset, limit int) ([]domain.FollowUser, error) {
+	rs, err := svc.repo.GetFollowers(ctx, uid, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	ids := slice.Map(rs, func(idx int, src domain.FollowRelation) int64 {
+		return src.Follower
+	})
+	// uid 有没有回关这些粉丝
+	back, err := svc.repo.GetRelations(ctx, []int64{uid}, ids)
+	if err != nil {
+		return nil, err
+	}
+	mutual := make(map[int64]bool, len(back))
+	for _, r := range back {
+		mutual[r.Followee] = true
+	}
+	return svc.toFollowUsers(ctx, rs, ids, mutual), nil
+}
+
+func (svc *NormalFollowService) Followees(ctx context.Context, uid int64, offset, limit int) ([]domain.FollowUser, error) {
+	rs, err := svc.repo.GetFollowees(ctx, uid, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	ids := slice.Map(rs, func(idx int, src domain.FollowRelation) int64 {
+		return src.Followee
+	})
+	// 这些人有没有关注 uid
+	back, err := svc.repo.GetRelations(ctx, ids, []int64{uid})
+	if err != nil {
+		return nil, err
+	}
+	mutual := make(map[int64]bool, len(back))
+	for _, r := range back {
+		mutual[r.Follower] = true
+	}
+	return svc.toFollowUsers(ctx, rs, ids, mutual), nil
+}
+
+func (svc *NormalFollowService) Statics(ctx context.Context, uid int64) (domain.FollowStatics, error) {
+	return svc.repo.GetStatics(ctx, uid)
+}
+
+// toFollowUsers ids 和 rs 一一对应，补充昵称，查不到昵称不影响列表
+func (svc *NormalFollowService) toFollowUsers(ctx context.Context, rs []domain.FollowRelation,
+	ids []int64, mutual map[int64]bool) []domain.FollowUser {
+	res := make([]domain.FollowUser, len(rs))
+	var eg errgroup.Group
+	for i := range rs {
+		res[i] = domain.FollowUser{
+			Id:     ids[i],
+			Mutual: mutual[ids[i]],
+			Ctime:  rs[i].Ctime,
+		}
+		eg.Go(func() error {
+			u, err := svc.userRepo.FindById(ctx, ids[i])
+			if err == nil {
+				res[i].Nickname = u.Nickname
+			}
+			return nil
+		})
+	}
+	_ = eg.Wait()
+	return res
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository"
+	repomocks "dream/webook/internal/repository/mocks"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNormalFollowService_Follow(t *testing.T) {
+	testCases := []struct {
+		name     string
+		mock     func(ctrl *gomock.Controller) (repository.FollowRepository, repository.UserRepository)
+		followee int64
+
+		wantErr error
+	}{
+		{
+			name: "关注成功",
+			mock: func(ctrl *gomock.Controller) (repository.FollowRepository, repository.UserRepository) {
+				userRepo := repomocks.NewMockUserRepository(ctrl)
+				userRepo.EXPECT().FindById(gomock.Any(), int64(456)).Return(domain.User{Id: 456}, nil)
+				repo := repomocks.NewMockFollowRepository(ctrl)
+				repo.EXPECT().AddFollow(gomock.Any(), int64(123), int64(456)).Return(nil)
+				return repo, userRepo
+			},
+			followee: 456,
+		},
+		{
+			name: "关注自己",
+			mock: func(ctrl *gomock.Controller) (repository.FollowRepository, repository.UserRepository) {
+				return repomocks.NewMockFollowRepository(ctrl), repomocks.NewMockUserRepository(ctrl)
+			},
+			followee: 123,
+			wantErr:  ErrFollowSelf,
+		},
+		{
+			name: "用户不存在",
+			mock: func(ctrl *gomock.Controller) (repository.FollowRepository, repository.UserRepository) {
+				userRepo := repomocks.NewMockUserRepository(ctrl)
+				userRepo.EXPECT().FindById(gomock.Any(), int64(456)).Return(domain.User{}, ErrUserNotFound)
+				return repomocks.NewMockFollowRepository(ctrl), userRepo
+			},
+			followee: 456,
+			wantErr:  ErrUserNotFound,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewFollowService(tc.mock(ctrl))
+			err := svc.Follow(context.Background(), 123, tc.followee)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestNormalFollowService_Followers(t *testing.T) {
+	now := time.UnixMilli(time.Now().UnixMilli())
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.FollowRepository, repository.UserRepository)
+
+		wantUsers []domain.FollowUser
+		wantErr   error
+	}{
+		{
+			name: "回关了的是互相关注，查不到昵称也返回",
+			mock: func(ctrl *gomock.Controller) (repository.FollowRepository, repository.UserRepository) {
+				repo := repomocks.NewMockFollowRepository(ctrl)
+				repo.EXPECT().GetFollowers(gomock.Any(), int64(123), 0, 10).Return([]domain.FollowRelation{
+					{Follower: 1, Followee: 123, Ctime: now},
+					{Follower: 2, Followee: 123, Ctime: now},
+				}, nil)
+				repo.EXPECT().GetRelations(gomock.Any(), []int64{123}, []int64{1, 2}).
+					Return([]domain.FollowRelation{{Follower: 123, Followee: 2}}, nil)
+				userRepo := repomocks.NewMockUserRepository(ctrl)
+				userRepo.EXPECT().FindById(gomock.Any(), int64(1)).Return(domain.User{Id: 1, Nickname: "Tom"}, nil)
+				userRepo.EXPECT().FindById(gomock.Any(), int64(2)).Return(domain.User{}, errors.New("mock db error"))
+				return repo, userRepo
+			},
+			wantUsers: []domain.FollowUser{
+				{Id: 1, Nickname: "Tom", Ctime: now},
+				{Id: 2, Mutual: true, Ctime: now},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewFollowService(tc.mock(ctrl))
+			us, err := svc.Followers(context.Background(), 123, 0, 10)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantUsers, us)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository"
+	"errors"
+
+	"golang.org/x/sync/errgroup"
+)
+
+var ErrCollectionNotOwner = errors.New("收藏夹不存在或者不属于该用户")
+
+type InteractiveService interface {
+	IncrReadCnt(ctx context.Context, biz string, bizId int64) error
+	// BatchIncrReadCnt 每个 bizId 阅读数 +1，同一个 bizId 可以出现多次
+	BatchIncrReadCnt(ctx context.Context, biz string, bizIds []int64) error
+	// Like 点赞，帖子不存在或者撤回了返回 ErrArticleNotFound
+	Like(ctx context.Context, biz string, bizId, uid int64) error
+	// CancelLike 取消点赞
+	CancelLike(ctx context.Context, biz string, bizId, uid int64) error
+	// Collect 收藏，cid 是收藏夹 id，为 0 的时候放进默认收藏夹
+	// 帖子不存在或者撤回了返回 ErrArticleNotFound
+	Collect(ctx context.Context, biz string, bizId, cid, uid int64) error
+	// CancelCollect 取消收藏，不管在哪个收藏夹里面
+	CancelCollect(ctx context.Context, biz string, bizId, uid int64) error
+	// Get 计数，以及 uid 有没有点赞、收藏
+	Get(ctx context.Context, biz string, bizId, uid int64) (domain.Interactive, error)
+}
+
+type NormalInteractiveService struct {
+	repo     repository.InteractiveRepository
+	collRepo repository.CollectionRepository
+	artRepo  repository.ArticleRepository
+}
+
+func NewInteractiveService(repo repository.InteractiveRepository,
+	collRepo repository.CollectionRepository, artRepo repository.ArticleRepository) InteractiveService {
+	return &NormalInteractiveService{
+		repo:     repo,
+		collRepo: collRepo,
+		artRepo:  artRepo,
+	}
+}
+
+func (svc *NormalInteractiveService) IncrReadCnt(ctx context.Context, biz string, bizId int64) error {
+	return svc.repo.IncrReadCnt(ctx, biz, bizId)
+}
+
+func (svc *NormalInteractiveService) BatchIncrReadCnt(ctx context.Context, biz string, bizIds []int64) error {
+	// 热门帖子一批里面会出现很多次，先合并起来
+	cnts := make(map[int64]int64, len(bizIds))
+	for _, bizId := range bizIds {
+		cnts[bizId]++
+	}
+	return svc.repo.BatchIncrReadCnt(ctx, biz, cnts)
+}
+
+func (svc *NormalInteractiveService) Like(ctx context.Context, biz string, bizId, uid int64) error {
+	if err := svc.checkBiz(ctx, biz, bizId); err != nil {
+		return err
+	}
+	return svc.repo.IncrLike(ctx, biz, bizId, uid)
+}
+
+func (svc *NormalInteractiveService) CancelLike(ctx context.Context, biz string, bizId, uid int64) error {
+	return svc.repo.DecrLike(ctx, biz, bizId, uid)
+}
+
+func (svc *NormalInteractiveService) Collect(ctx context.Context, biz string, bizId, cid, uid int64) error {
+	if err := svc.checkBiz(ctx, biz, bizId); err != nil {
+		return err
+	}
+	if cid == 0 {
+		c, err := svc.collRepo.FindOrCreateDefault(ctx, uid)
+		if err != nil {
+			return err
+		}
+		cid = c.Id
+	} else {
+		c, err := svc.collRepo.FindById(ctx, cid)
+		if err == repository.ErrCollectionNotFound {
+			return ErrCollectionNotOwner
+		}
+		if err != nil {
+			return err
+		}
+		// 不能往别人的收藏夹里面放东西
+		if c.Uid != uid {
+			return ErrCollectionNotOwner
+		}
+	}
+	return svc.repo.AddCollectionItem(ctx, biz, bizId, cid, uid)
+}
+
+// checkBiz 帖子要已经发表，别的业务由调用方保证 bizId 是对的
+// 取消点赞、取消收藏不用查，撤回了的帖子也要能取消
+func (svc *NormalInteractiveService) checkBiz(ctx context.Context, biz string, bizId int64) error {
+	if biz != bizArticle {
+		return nil
+	}
+	art, err := svc.artRepo.GetPublishedById(ctx, bizId)
+	if err != nil {
+		return err
+	}
+	if art.Status != domain.ArticleStatusPublished {
+		return ErrArticleNotFound
+	}
+	return nil
+}
+
+func (svc *NormalInteractiveService) CancelCollect(ctx context.Context, biz string, bizId, uid int64) error {
+	return svc.repo.DeleteCollectionItem(ctx, biz, bizId, uid)
+}
+
+func (svc *NormalInteractiveService) Get(ctx context.Context, biz string, bizId, uid int64) (domain.Interactive, error) {
+	var (
+		eg        errgroup.Group
+		intr      domain.Interactive
+		liked     bool
+		collected bool
+	)
+	// 三个查询互不依赖，并发查
+	eg.Go(func() error {
+		var err error
+		intr, err = svc.repo.Get(ctx, biz, bizId)
+		return err
+	})
+	eg.Go(func() error {
+		var err error
+		liked, err = svc.repo.Liked(ctx, biz, bizId, uid)
+		return err
+	})
+	eg.Go(func() error {
+		var err error
+		collected, err = svc.repo.Collected(ctx, biz, bizId, uid)
+		return err
+	})
+	if err := eg.Wait(); err != nil {
+		return domain.Interactive{}, err
+	}
+	intr.Liked = liked
+	intr.Collected = collected
+	return intr, nil
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository"
+	repomocks "dream/webook/internal/repository/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNormalInteractiveService_Like(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.InteractiveRepository, repository.ArticleRepository)
+		biz  string
+
+		wantErr error
+	}{
+		{
+			name: "点赞成功",
+			biz:  "article",
+			mock: func(ctrl *gomock.Controller) (repository.InteractiveRepository, repository.ArticleRepository) {
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().GetPublishedById(gomock.Any(), int64(1)).
+					Return(domain.Article{Id: 1, Status: domain.ArticleStatusPublished}, nil)
+				repo := repomocks.NewMockInteractiveRepository(ctrl)
+				repo.EXPECT().IncrLike(gomock.Any(), "article", int64(1), int64(123)).Return(nil)
+				return repo, artRepo
+			},
+		},
+		{
+			name: "帖子不存在",
+			biz:  "article",
+			mock: func(ctrl *gomock.Controller) (repository.InteractiveRepository, repository.ArticleRepository) {
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().GetPublishedById(gomock.Any(), int64(1)).
+					Return(domain.Article{}, repository.ErrArticleNotFound)
+				return repomocks.NewMockInteractiveRepository(ctrl), artRepo
+			},
+			wantErr: ErrArticleNotFound,
+		},
+		{
+			name: "帖子撤回了",
+			biz:  "article",
+			mock: func(ctrl *gomock.Controller) (repository.InteractiveRepository, repository.ArticleRepository) {
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().GetPublishedById(gomock.Any(), int64(1)).
+					Return(domain.Article{Id: 1, Status: domain.ArticleStatusPrivate}, nil)
+				return repomocks.NewMockInteractiveRepository(ctrl), artRepo
+			},
+			wantErr: ErrArticleNotFound,
+		},
+		{
+			name: "别的业务不查帖子",
+			biz:  "video",
+			mock: func(ctrl *gomock.Controller) (repository.InteractiveRepository, repository.ArticleRepository) {
+				repo := repomocks.NewMockInteractiveRepository(ctrl)
+				repo.EXPECT().IncrLike(gomock.Any(), "video", int64(1), int64(123)).Return(nil)
+				return repo, repomocks.NewMockArticleRepository(ctrl)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, artRepo := tc.mock(ctrl)
+			svc := NewInteractiveService(repo, repomocks.NewMockCollectionRepository(ctrl), artRepo)
+			err := svc.Like(context.Background(), tc.biz, 1, 123)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestNormalInteractiveService_Collect(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.InteractiveRepository,
+			repository.CollectionRepository, repository.ArticleRepository)
+		cid int64
+
+		wantErr error
+	}{
+		{
+			name: "放进默认收藏夹",
+			mock: func(ctrl *gomock.Controller) (repository.InteractiveRepository,
+				repository.CollectionRepository, repository.ArticleRepository) {
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().GetPublishedById(gomock.Any(), int64(1)).
+					Return(domain.Article{Id: 1, Status: domain.ArticleStatusPublished}, nil)
+				collRepo := repomocks.NewMockCollectionRepository(ctrl)
+				collRepo.EXPECT().FindOrCreateDefault(gomock.Any(), int64(123)).
+					Return(domain.Collection{Id: 10, Uid: 123}, nil)
+				repo := repomocks.NewMockInteractiveRepository(ctrl)
+				repo.EXPECT().AddCollectionItem(gomock.Any(), "article", int64(1), int64(10), int64(123)).Return(nil)
+				return repo, collRepo, artRepo
+			},
+		},
+		{
+			name: "放进别人的收藏夹",
+			mock: func(ctrl *gomock.Controller) (repository.InteractiveRepository,
+				repository.CollectionRepository, repository.ArticleRepository) {
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().GetPublishedById(gomock.Any(), int64(1)).
+					Return(domain.Article{Id: 1, Status: domain.ArticleStatusPublished}, nil)
+				collRepo := repomocks.NewMockCollectionRepository(ctrl)
+				collRepo.EXPECT().FindById(gomock.Any(), int64(11)).
+					Return(domain.Collection{Id: 11, Uid: 456}, nil)
+				return repomocks.NewMockInteractiveRepository(ctrl), collRepo, artRepo
+			},
+			cid:     11,
+			wantErr: ErrCollectionNotOwner,
+		},
+		{
+			name: "帖子撤回了",
+			mock: func(ctrl *gomock.Controller) (repository.InteractiveRepository,
+				repository.CollectionRepository, repository.ArticleRepository) {
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().GetPublishedById(gomock.Any(), int64(1)).
+					Return(domain.Article{Id: 1, Status: domain.ArticleStatusPrivate}, nil)
+				return repomocks.NewMockInteractiveRepository(ctrl), repomocks.NewMockCollectionRepository(ctrl), artRepo
+			},
+			wantErr: ErrArticleNotFound,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, collRepo, artRepo := tc.mock(ctrl)
+			svc := NewInteractiveService(repo, collRepo, artRepo)
+			err := svc.Collect(context.Background(), "article", 1, tc.cid, 123)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
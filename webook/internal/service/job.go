@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository"
+	"time"
+)
+
+var (
+	ErrNoMoreJob   = repository.ErrNoMoreJob
+	ErrJobNotOwned = repository.ErrJobNotOwned
+	ErrJobNotFound = repository.ErrJobNotFound
+)
+
+// JobService 数据库里的任务，多个实例抢占执行
+type JobService interface {
+	// Preempt 抢占一个到期的任务，没有的话返回 ErrNoMoreJob
+	Preempt(ctx context.Context, owner string) (domain.Job, error)
+	// Heartbeat 执行期间定时续约，返回 ErrJobNotOwned 说明任务被暂停或者被别人抢走了
+	Heartbeat(ctx context.Context, j domain.Job) error
+	// Release 执行完释放任务，按照表达式计算下一次执行时间
+	Release(ctx context.Context, j domain.Job) error
+	AddIfNotExist(ctx context.Context, j domain.Job) error
+	List(ctx context.Context, offset, limit int) ([]domain.Job, error)
+	Pause(ctx context.Context, id int64) error
+	Resume(ctx context.Context, id int64) error
+}
+
+type PreemptJobService struct {
+	repo repository.JobRepository
+	// timeout 持有任务的实例超过这么久没有续约，别的实例就可以抢占
+	timeout time.Duration
+}
+
+func NewJobService(repo repository.JobRepository) JobService {
+	return &PreemptJobService{
+		repo:    repo,
+		timeout: time.Minute,
+	}
+}
+
+func (svc *PreemptJobService) Preempt(ctx context.Context, owner string) (domain.Job, error) {
+	return svc.repo.Preempt(ctx, owner, svc.timeout)
+}
+
+func (svc *PreemptJobService) Heartbeat(ctx context.Context, j domain.Job) error {
+	return svc.repo.Heartbeat(ctx, j.Id, j.Owner, j.Version)
+}
+
+func (svc *PreemptJobService) Release(ctx context.Context, j domain.Job) error {
+	return svc.repo.Release(ctx, j.Id, j.Owner, j.Version, svc.next(j))
+}
+
+func (svc *PreemptJobService) AddIfNotExist(ctx context.Context, j domain.Job) error {
+	j.NextTime = svc.next(j)
+	return svc.repo.AddIfNotExist(ctx, j)
+}
+
+func (svc *PreemptJobService) List(ctx context.Context, offset, limit int) ([]domain.Job, error) {
+	return svc.repo.List(ctx, offset, limit)
+}
+
+func (svc *PreemptJobService) Pause(ctx context.Context, id int64) error {
+	return svc.repo.Pause(ctx, id)
+}
+
+func (svc *PreemptJobService) Resume(ctx context.Context, id int64) error {
+	j, err := svc.repo.FindById(ctx, id)
+	if err != nil {
+		return err
+	}
+	// 暂停期间错过的不补跑，从现在开始算
+	return svc.repo.Resume(ctx, id, svc.next(j))
+}
+
+func (svc *PreemptJobService) next(j domain.Job) time.Time {
+	next := j.Next(time.Now())
+	if next.IsZero() {
+		// 表达式不对，放到很久以后，等人来修
+		return time.Now().Add(time.Hour * 24 * 365)
+	}
+	return next
+}
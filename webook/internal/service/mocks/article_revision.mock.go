@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: webook/internal/service/article_revision.go
+//
+// Generated by this command:
+//
+//	mockgen -source=webook/internal/service/article_revision.go -package=svcmocks -destination=webook/internal/service/mocks/article_revision.mock.go
+//
+
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	domain "dream/webook/internal/domain"
+	diff "dream/webook/pkg/diff"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockArticleRevisionService is a mock of ArticleRevisionService interface.
+type MockArticleRevisionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockArticleRevisionServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockArticleRevisionServiceMockRecorder is the mock recorder for MockArticleRevisionService.
+type MockArticleRevisionServiceMockRecorder struct {
+	mock *MockArticleRevisionService
+}
+
+// NewMockArticleRevisionService creates a new mock instance.
+func NewMockArticleRevisionService(ctrl *gomock.Controller) *MockArticleRevisionService {
+	mock := &MockArticleRevisionService{ctrl: ctrl}
+	mock.recorder = &MockArticleRevisionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockArticleRevisionService) EXPECT() *MockArticleRevisionServiceMockRecorder {
+	return m.recorder
+}
+
+// Diff mocks base method.
+func (m *MockArticleRevisionService) Diff(ctx context.Context, uid, from, to int64) ([]diff.Line, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Diff", ctx, uid, from, to)
+	ret0, _ := ret[0].([]diff.Line)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Diff indicates an expected call of Diff.
+func (mr *MockArticleRevisionServiceMockRecorder) Diff(ctx, uid, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Diff", reflect.TypeOf((*MockArticleRevisionService)(nil).Diff), ctx, uid, from, to)
+}
+
+// Get mocks base method.
+func (m *MockArticleRevisionService) Get(ctx context.Context, uid, id int64) (domain.ArticleRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, uid, id)
+	ret0, _ := ret[0].(domain.ArticleRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockArticleRevisionServiceMockRecorder) Get(ctx, uid, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockArticleRevisionService)(nil).Get), ctx, uid, id)
+}
+
+// List mocks base method.
+func (m *MockArticleRevisionService) List(ctx context.Context, uid, artId int64, offset, limit int) ([]domain.ArticleRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, uid, artId, offset, limit)
+	ret0, _ := ret[0].([]domain.ArticleRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockArticleRevisionServiceMockRecorder) List(ctx, uid, artId, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockArticleRevisionService)(nil).List), ctx, uid, artId, offset, limit)
+}
+
+// Restore mocks base method.
+func (m *MockArticleRevisionService) Restore(ctx context.Context, uid, artId, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, uid, artId, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockArticleRevisionServiceMockRecorder) Restore(ctx, uid, artId, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockArticleRevisionService)(nil).Restore), ctx, uid, artId, id)
+}
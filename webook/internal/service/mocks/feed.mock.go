@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: webook/internal/service/feed.go
+//
+// Generated by this command:
+//
+//	mockgen -source=webook/internal/service/feed.go -package=svcmocks -destination=webook/internal/service/mocks/feed.mock.go
+//
+
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	domain "dream/webook/internal/domain"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFeedService is a mock of FeedService interface.
+type MockFeedService struct {
+	ctrl     *gomock.Controller
+	recorder *MockFeedServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockFeedServiceMockRecorder is the mock recorder for MockFeedService.
+type MockFeedServiceMockRecorder struct {
+	mock *MockFeedService
+}
+
+// NewMockFeedService creates a new mock instance.
+func NewMockFeedService(ctrl *gomock.Controller) *MockFeedService {
+	mock := &MockFeedService{ctrl: ctrl}
+	mock.recorder = &MockFeedServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFeedService) EXPECT() *MockFeedServiceMockRecorder {
+	return m.recorder
+}
+
+// Feed mocks base method.
+func (m *MockFeedService) Feed(ctx context.Context, uid int64, before time.Time, beforeId int64, limit int) ([]domain.FeedEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Feed", ctx, uid, before, beforeId, limit)
+	ret0, _ := ret[0].([]domain.FeedEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Feed indicates an expected call of Feed.
+func (mr *MockFeedServiceMockRecorder) Feed(ctx, uid, before, beforeId, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Feed", reflect.TypeOf((*MockFeedService)(nil).Feed), ctx, uid, before, beforeId, limit)
+}
+
+// PublishArticle mocks base method.
+func (m *MockFeedService) PublishArticle(ctx context.Context, art domain.Article) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishArticle", ctx, art)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishArticle indicates an expected call of PublishArticle.
+func (mr *MockFeedServiceMockRecorder) PublishArticle(ctx, art any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishArticle", reflect.TypeOf((*MockFeedService)(nil).PublishArticle), ctx, art)
+}
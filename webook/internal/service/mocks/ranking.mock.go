@@ -0,0 +1,85 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: webook/internal/service/ranking.go
+//
+// Generated by this command:
+//
+//	mockgen -source=webook/internal/service/ranking.go -package=svcmocks -destination=webook/internal/service/mocks/ranking.mock.go
+//
+
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	domain "dream/webook/internal/domain"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRankingService is a mock of RankingService interface.
+type MockRankingService struct {
+	ctrl     *gomock.Controller
+	recorder *MockRankingServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockRankingServiceMockRecorder is the mock recorder for MockRankingService.
+type MockRankingServiceMockRecorder struct {
+	mock *MockRankingService
+}
+
+// NewMockRankingService creates a new mock instance.
+func NewMockRankingService(ctrl *gomock.Controller) *MockRankingService {
+	mock := &MockRankingService{ctrl: ctrl}
+	mock.recorder = &MockRankingServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRankingService) EXPECT() *MockRankingServiceMockRecorder {
+	return m.recorder
+}
+
+// RankTopN mocks base method.
+func (m *MockRankingService) RankTopN(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RankTopN", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RankTopN indicates an expected call of RankTopN.
+func (mr *MockRankingServiceMockRecorder) RankTopN(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RankTopN", reflect.TypeOf((*MockRankingService)(nil).RankTopN), ctx)
+}
+
+// Refresh mocks base method.
+func (m *MockRankingService) Refresh(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockRankingServiceMockRecorder) Refresh(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockRankingService)(nil).Refresh), ctx, id)
+}
+
+// TopN mocks base method.
+func (m *MockRankingService) TopN(ctx context.Context) ([]domain.HotArticle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TopN", ctx)
+	ret0, _ := ret[0].([]domain.HotArticle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TopN indicates an expected call of TopN.
+func (mr *MockRankingServiceMockRecorder) TopN(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TopN", reflect.TypeOf((*MockRankingService)(nil).TopN), ctx)
+}
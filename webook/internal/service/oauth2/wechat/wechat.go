@@ -23,12 +23,12 @@ type service struct {
 	client    *http.Client
 }
 
-func NewService(appId string, appSecret string) Service {
+// NewService client 由外面注入，测试的时候可以换成本地的
+func NewService(appId string, appSecret string, client *http.Client) Service {
 	return &service{
 		appId:     appId,
 		appSecret: appSecret,
-		// 依赖注入，但是没完全注入
-		client: http.DefaultClient,
+		client:    client,
 	}
 }
 
@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/service/payment"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+var _ payment.Service = (*Service)(nil)
+
+// Service 本地开发用，不会真的支付
+type Service struct {
+	mu       sync.Mutex
+	payments map[string]domain.Payment
+}
+
+func NewService() *Service {
+	return &Service{
+		payments: make(map[string]domain.Payment),
+	}
+}
+
+func (s *Service) Prepay(ctx context.Context, pmt domain.Payment) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pmt.Status = domain.PaymentStatusInit
+	s.payments[pmt.BizTradeNo] = pmt
+	fmt.Println("模拟下单", pmt.BizTradeNo, pmt.Amt.Total)
+	return "memory://pay/" + pmt.BizTradeNo, nil
+}
+
+// GetPayment 没有人真的去扫码，查询的时候就当已经支付成功了，所以只能本地开发用
+func (s *Service) GetPayment(ctx context.Context, bizTradeNo string) (domain.Payment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pmt, ok := s.payments[bizTradeNo]
+	if !ok {
+		return domain.Payment{}, payment.ErrPaymentNotFound
+	}
+	if pmt.Status == domain.PaymentStatusInit {
+		pmt.Status = domain.PaymentStatusSuccess
+		pmt.TxnID = "memory-" + bizTradeNo
+		s.payments[bizTradeNo] = pmt
+	}
+	return pmt, nil
+}
+
+func (s *Service) ClosePayment(ctx context.Context, bizTradeNo string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pmt, ok := s.payments[bizTradeNo]
+	if !ok {
+		return payment.ErrPaymentNotFound
+	}
+	if pmt.Status == domain.PaymentStatusInit {
+		pmt.Status = domain.PaymentStatusFailed
+		s.payments[bizTradeNo] = pmt
+	}
+	return nil
+}
+
+// HandleNotify 本地用 curl 模拟回调，body 是 {"bizTradeNo": "reward-1", "status": 2}
+func (s *Service) HandleNotify(ctx context.Context, header http.Header, body []byte) (domain.Payment, error) {
+	type Notify struct {
+		BizTradeNo string `json:"bizTradeNo"`
+		Status     uint8  `json:"status"`
+	}
+	var n Notify
+	if err := json.Unmarshal(body, &n); err != nil {
+		return domain.Payment{}, payment.ErrInvalidNotify
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pmt, ok := s.payments[n.BizTradeNo]
+	if !ok {
+		return domain.Payment{}, payment.ErrPaymentNotFound
+	}
+	pmt.Status = domain.PaymentStatus(n.Status)
+	s.payments[n.BizTradeNo] = pmt
+	return pmt, nil
+}
@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: webook/internal/service/payment/types.go
+//
+// Generated by this command:
+//
+//	mockgen -source=webook/internal/service/payment/types.go -package=pmtmocks -destination=webook/internal/service/payment/mocks/payment.mock.go
+//
+
+// Package pmtmocks is a generated GoMock package.
+package pmtmocks
+
+import (
+	context "context"
+	domain "dream/webook/internal/domain"
+	http "net/http"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockService is a mock of Service interface.
+type MockService struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockServiceMockRecorder is the mock recorder for MockService.
+type MockServiceMockRecorder struct {
+	mock *MockService
+}
+
+// NewMockService creates a new mock instance.
+func NewMockService(ctrl *gomock.Controller) *MockService {
+	mock := &MockService{ctrl: ctrl}
+	mock.recorder = &MockServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockService) EXPECT() *MockServiceMockRecorder {
+	return m.recorder
+}
+
+// ClosePayment mocks base method.
+func (m *MockService) ClosePayment(ctx context.Context, bizTradeNo string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClosePayment", ctx, bizTradeNo)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClosePayment indicates an expected call of ClosePayment.
+func (mr *MockServiceMockRecorder) ClosePayment(ctx, bizTradeNo any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClosePayment", reflect.TypeOf((*MockService)(nil).ClosePayment), ctx, bizTradeNo)
+}
+
+// GetPayment mocks base method.
+func (m *MockService) GetPayment(ctx context.Context, bizTradeNo string) (domain.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPayment", ctx, bizTradeNo)
+	ret0, _ := ret[0].(domain.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPayment indicates an expected call of GetPayment.
+func (mr *MockServiceMockRecorder) GetPayment(ctx, bizTradeNo any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPayment", reflect.TypeOf((*MockService)(nil).GetPayment), ctx, bizTradeNo)
+}
+
+// HandleNotify mocks base method.
+func (m *MockService) HandleNotify(ctx context.Context, header http.Header, body []byte) (domain.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleNotify", ctx, header, body)
+	ret0, _ := ret[0].(domain.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HandleNotify indicates an expected call of HandleNotify.
+func (mr *MockServiceMockRecorder) HandleNotify(ctx, header, body any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleNotify", reflect.TypeOf((*MockService)(nil).HandleNotify), ctx, header, body)
+}
+
+// Prepay mocks base method.
+func (m *MockService) Prepay(ctx context.Context, pmt domain.Payment) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Prepay", ctx, pmt)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Prepay indicates an expected call of Prepay.
+func (mr *MockServiceMockRecorder) Prepay(ctx, pmt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prepay", reflect.TypeOf((*MockService)(nil).Prepay), ctx, pmt)
+}
@@ -0,0 +1,27 @@
+package payment
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"errors"
+	"net/http"
+)
+
+var (
+	// ErrPaymentNotFound 支付平台那边没有这笔支付
+	ErrPaymentNotFound = errors.New("支付不存在")
+	// ErrInvalidNotify 回调的签名不对，或者内容解析不了
+	ErrInvalidNotify = errors.New("非法的支付回调")
+)
+
+// Service 支付平台
+type Service interface {
+	// Prepay 下单，返回给用户扫码支付的链接
+	Prepay(ctx context.Context, pmt domain.Payment) (string, error)
+	// GetPayment 按照业务单号查询支付状态
+	GetPayment(ctx context.Context, bizTradeNo string) (domain.Payment, error)
+	// ClosePayment 关闭还没有支付的订单，关闭之后用户就不能再支付了
+	ClosePayment(ctx context.Context, bizTradeNo string) error
+	// HandleNotify 校验支付平台的异步通知，返回通知里的支付结果
+	HandleNotify(ctx context.Context, header http.Header, body []byte) (domain.Payment, error)
+}
@@ -0,0 +1,64 @@
+package wechat
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+)
+
+var errInvalidKey = errors.New("不是合法的 RSA 密钥")
+
+// LoadPrivateKey 读取商户私钥，商户平台下载的是 PKCS8 格式
+func LoadPrivateKey(path string) (*rsa.PrivateKey, error) {
+	block, err := loadPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		if rsaKey, ok := key.(*rsa.PrivateKey); ok {
+			return rsaKey, nil
+		}
+		return nil, errInvalidKey
+	}
+	return x509.ParsePKCS1PrivateKey(block.Bytes)
+}
+
+// LoadPublicKey 读取微信支付公钥，也可以是平台证书
+func LoadPublicKey(path string) (*rsa.PublicKey, error) {
+	block, err := loadPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	var key any
+	if block.Type == "CERTIFICATE" {
+		cert, er := x509.ParseCertificate(block.Bytes)
+		if er != nil {
+			return nil, er
+		}
+		key = cert.PublicKey
+	} else {
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, errInvalidKey
+	}
+	return rsaKey, nil
+}
+
+func loadPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errInvalidKey
+	}
+	return block, nil
+}
@@ -0,0 +1,260 @@
+package wechat
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/service/payment"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+var _ payment.Service = (*NativePaymentService)(nil)
+
+const defaultBaseURL = "https://api.mch.weixin.qq.com"
+
+type Config struct {
+	AppID string
+	MchID string
+	// MchSerialNo 商户证书的序列号
+	MchSerialNo string
+	// PrivateKey 商户私钥，给请求签名
+	PrivateKey *rsa.PrivateKey
+	// APIv3Key 解密回调内容
+	APIv3Key string
+	// PlatformPublicKey 微信支付的公钥，校验回调的签名
+	PlatformPublicKey *rsa.PublicKey
+	PlatformSerialNo  string
+	NotifyURL         string
+	// BaseURL 默认是微信支付的线上地址，测试的时候换成本地的
+	BaseURL string
+}
+
+// NativePaymentService 微信支付 Native 支付，也就是扫码支付
+type NativePaymentService struct {
+	cfg    Config
+	client *http.Client
+	// notifyTolerance 回调的时间戳和本地时间最多差多少，防止重放
+	notifyTolerance time.Duration
+}
+
+func NewNativePaymentService(cfg Config, client *http.Client) *NativePaymentService {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = defaultBaseURL
+	}
+	return &NativePaymentService{
+		cfg:             cfg,
+		client:          client,
+		notifyTolerance: time.Minute * 5,
+	}
+}
+
+func (s *NativePaymentService) Prepay(ctx context.Context, pmt domain.Payment) (string, error) {
+	req := prepayRequest{
+		AppID:       s.cfg.AppID,
+		MchID:       s.cfg.MchID,
+		Description: pmt.Description,
+		OutTradeNo:  pmt.BizTradeNo,
+		NotifyURL:   s.cfg.NotifyURL,
+		Amount: amount{
+			Total:    pmt.Amt.Total,
+			Currency: pmt.Amt.Currency,
+		},
+	}
+	var resp prepayResponse
+	err := s.do(ctx, http.MethodPost, "/v3/pay/transactions/native", req, &resp)
+	return resp.CodeURL, err
+}
+
+func (s *NativePaymentService) GetPayment(ctx context.Context, bizTradeNo string) (domain.Payment, error) {
+	path := fmt.Sprintf("/v3/pay/transactions/out-trade-no/%s?mchid=%s",
+		url.PathEscape(bizTradeNo), url.QueryEscape(s.cfg.MchID))
+	var txn transaction
+	err := s.do(ctx, http.MethodGet, path, nil, &txn)
+	if err != nil {
+		return domain.Payment{}, err
+	}
+	return s.toDomain(txn), nil
+}
+
+func (s *NativePaymentService) ClosePayment(ctx context.Context, bizTradeNo string) error {
+	path := fmt.Sprintf("/v3/pay/transactions/out-trade-no/%s/close", url.PathEscape(bizTradeNo))
+	return s.do(ctx, http.MethodPost, path, map[string]string{"mchid": s.cfg.MchID}, nil)
+}
+
+func (s *NativePaymentService) HandleNotify(ctx context.Context, header http.Header, body []byte) (domain.Payment, error) {
+	if err := s.verify(header, body); err != nil {
+		return domain.Payment{}, err
+	}
+	var n notify
+	if err := json.Unmarshal(body, &n); err != nil {
+		return domain.Payment{}, payment.ErrInvalidNotify
+	}
+	if n.Resource.Algorithm != "AEAD_AES_256_GCM" {
+		return domain.Payment{}, payment.ErrInvalidNotify
+	}
+	plaintext, err := s.decrypt(n.Resource)
+	if err != nil {
+		return domain.Payment{}, payment.ErrInvalidNotify
+	}
+	var txn transaction
+	if err = json.Unmarshal(plaintext, &txn); err != nil {
+		return domain.Payment{}, payment.ErrInvalidNotify
+	}
+	// 签名是对的，但是不是我们商户号的订单
+	if txn.MchID != s.cfg.MchID || txn.AppID != s.cfg.AppID {
+		return domain.Payment{}, payment.ErrInvalidNotify
+	}
+	return s.toDomain(txn), nil
+}
+
+// do 发请求，请求要签名，非 2xx 的响应当成错误
+func (s *NativePaymentService) do(ctx context.Context, method, path string, reqBody any, respBody any) error {
+	var body []byte
+	if reqBody != nil {
+		var err error
+		body, err = json.Marshal(reqBody)
+		if err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.cfg.BaseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	auth, err := s.authorization(method, path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", auth)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var e apiError
+		_ = json.Unmarshal(data, &e)
+		if e.Code == "ORDER_NOT_EXIST" {
+			return payment.ErrPaymentNotFound
+		}
+		return fmt.Errorf("微信支付返回错误 %d %s %s", resp.StatusCode, e.Code, e.Message)
+	}
+	// 关闭订单成功是 204，没有 body
+	if respBody == nil || len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, respBody)
+}
+
+// authorization 按照微信支付 APIv3 的规则签名
+func (s *NativePaymentService) authorization(method, path string, body []byte) (string, error) {
+	nonce, err := s.nonce()
+	if err != nil {
+		return "", err
+	}
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	msg := method + "\n" + path + "\n" + ts + "\n" + nonce + "\n" + string(body) + "\n"
+	hashed := sha256.Sum256([]byte(msg))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.cfg.PrivateKey, crypto.SHA256, hashed[:])
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(`WECHATPAY2-SHA256-RSA2048 mchid="%s",nonce_str="%s",signature="%s",timestamp="%s",serial_no="%s"`,
+		s.cfg.MchID, nonce, base64.StdEncoding.EncodeToString(sig), ts, s.cfg.MchSerialNo), nil
+}
+
+// verify 校验回调的签名，签名的内容是 时间戳\n随机串\nbody\n
+func (s *NativePaymentService) verify(header http.Header, body []byte) error {
+	if header.Get("Wechatpay-Serial") != s.cfg.PlatformSerialNo {
+		return payment.ErrInvalidNotify
+	}
+	tsStr := header.Get("Wechatpay-Timestamp")
+	ts, err := strconv.ParseInt(tsStr, 10, 64)
+	if err != nil {
+		return payment.ErrInvalidNotify
+	}
+	diff := time.Since(time.Unix(ts, 0))
+	if diff > s.notifyTolerance || diff < -s.notifyTolerance {
+		return payment.ErrInvalidNotify
+	}
+	sig, err := base64.StdEncoding.DecodeString(header.Get("Wechatpay-Signature"))
+	if err != nil {
+		return payment.ErrInvalidNotify
+	}
+	msg := tsStr + "\n" + header.Get("Wechatpay-Nonce") + "\n" + string(body) + "\n"
+	hashed := sha256.Sum256([]byte(msg))
+	if err = rsa.VerifyPKCS1v15(s.cfg.PlatformPublicKey, crypto.SHA256, hashed[:], sig); err != nil {
+		return payment.ErrInvalidNotify
+	}
+	return nil
+}
+
+// decrypt 回调的内容是用 APIv3 密钥做 AES-256-GCM 加密的
+func (s *NativePaymentService) decrypt(r resource) ([]byte, error) {
+	ciphertext, err := base64.StdEncoding.DecodeString(r.Ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher([]byte(s.cfg.APIv3Key))
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return gcm.Open(nil, []byte(r.Nonce), ciphertext, []byte(r.AssociatedData))
+}
+
+func (s *NativePaymentService) nonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func (s *NativePaymentService) toDomain(txn transaction) domain.Payment {
+	var status domain.PaymentStatus
+	switch txn.TradeState {
+	case "SUCCESS":
+		status = domain.PaymentStatusSuccess
+	case "NOTPAY", "USERPAYING":
+		status = domain.PaymentStatusInit
+	case "CLOSED", "REVOKED", "PAYERROR":
+		status = domain.PaymentStatusFailed
+	case "REFUND":
+		status = domain.PaymentStatusRefund
+	default:
+		status = domain.PaymentStatusUnknown
+	}
+	return domain.Payment{
+		Amt: domain.Amount{
+			Currency: txn.Amount.Currency,
+			Total:    txn.Amount.Total,
+		},
+		BizTradeNo: txn.OutTradeNo,
+		Status:     status,
+		TxnID:      txn.TransactionID,
+	}
+}
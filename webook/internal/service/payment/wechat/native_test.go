@@ -0,0 +1,266 @@
+package wechat
+
+import (
+	"context"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/service/payment"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAPIv3Key = "0123456789abcdef0123456789abcdef"
+
+func TestNativePaymentService_Prepay(t *testing.T) {
+	mchKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/v3/pay/transactions/native", r.URL.Path)
+		body, er := io.ReadAll(r.Body)
+		require.NoError(t, er)
+		// 按照同样的规则验签，确认签名是对的
+		verifyAuthorization(t, &mchKey.PublicKey, r.Method, r.URL.RequestURI(), body, r.Header.Get("Authorization"))
+
+		var req prepayRequest
+		require.NoError(t, json.Unmarshal(body, &req))
+		assert.Equal(t, prepayRequest{
+			AppID:       "wx123",
+			MchID:       "mch123",
+			Description: "打赏-标题",
+			OutTradeNo:  "reward-1",
+			NotifyURL:   "https://example.com/pay/callback",
+			Amount:      amount{Total: 1, Currency: "CNY"},
+		}, req)
+		_, _ = w.Write([]byte(`{"code_url":"weixin://wxpay/bizpayurl?pr=abc"}`))
+	}))
+	defer server.Close()
+
+	svc := NewNativePaymentService(Config{
+		AppID:       "wx123",
+		MchID:       "mch123",
+		MchSerialNo: "serial123",
+		PrivateKey:  mchKey,
+		NotifyURL:   "https://example.com/pay/callback",
+		BaseURL:     server.URL,
+	}, server.Client())
+	codeURL, err := svc.Prepay(context.Background(), domain.Payment{
+		Amt:         domain.Amount{Currency: "CNY", Total: 1},
+		BizTradeNo:  "reward-1",
+		Description: "打赏-标题",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "weixin://wxpay/bizpayurl?pr=abc", codeURL)
+}
+
+func TestNativePaymentService_GetPayment(t *testing.T) {
+	mchKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	testCases := []struct {
+		name    string
+		status  int
+		resp    string
+		wantPmt domain.Payment
+		wantErr error
+	}{
+		{
+			name:   "支付成功",
+			status: http.StatusOK,
+			resp:   `{"out_trade_no":"reward-1","transaction_id":"txn1","trade_state":"SUCCESS","amount":{"total":1,"currency":"CNY"}}`,
+			wantPmt: domain.Payment{
+				Amt:        domain.Amount{Currency: "CNY", Total: 1},
+				BizTradeNo: "reward-1",
+				Status:     domain.PaymentStatusSuccess,
+				TxnID:      "txn1",
+			},
+		},
+		{
+			name:    "订单不存在",
+			status:  http.StatusNotFound,
+			resp:    `{"code":"ORDER_NOT_EXIST","message":"订单不存在"}`,
+			wantErr: payment.ErrPaymentNotFound,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodGet, r.Method)
+				assert.Equal(t, "/v3/pay/transactions/out-trade-no/reward-1", r.URL.Path)
+				assert.Equal(t, "mch123", r.URL.Query().Get("mchid"))
+				verifyAuthorization(t, &mchKey.PublicKey, r.Method, r.URL.RequestURI(), nil, r.Header.Get("Authorization"))
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.resp))
+			}))
+			defer server.Close()
+			svc := NewNativePaymentService(Config{
+				MchID:      "mch123",
+				PrivateKey: mchKey,
+				BaseURL:    server.URL,
+			}, server.Client())
+			pmt, err := svc.GetPayment(context.Background(), "reward-1")
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantPmt, pmt)
+		})
+	}
+}
+
+func TestNativePaymentService_ClosePayment(t *testing.T) {
+	mchKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v3/pay/transactions/out-trade-no/reward-1/close", r.URL.Path)
+		body, er := io.ReadAll(r.Body)
+		require.NoError(t, er)
+		assert.JSONEq(t, `{"mchid":"mch123"}`, string(body))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	svc := NewNativePaymentService(Config{
+		MchID:      "mch123",
+		PrivateKey: mchKey,
+		BaseURL:    server.URL,
+	}, server.Client())
+	assert.NoError(t, svc.ClosePayment(context.Background(), "reward-1"))
+}
+
+func TestNativePaymentService_HandleNotify(t *testing.T) {
+	platformKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	body := notifyBody(t, `{"appid":"wx123","mchid":"mch123","out_trade_no":"reward-1","transaction_id":"txn1","trade_state":"SUCCESS","amount":{"total":1,"currency":"CNY"}}`)
+	otherMch := notifyBody(t, `{"appid":"wx123","mchid":"mch456","out_trade_no":"reward-1","transaction_id":"txn1","trade_state":"SUCCESS","amount":{"total":1,"currency":"CNY"}}`)
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+
+	testCases := []struct {
+		name   string
+		header http.Header
+		body   []byte
+
+		wantPmt domain.Payment
+		wantErr error
+	}{
+		{
+			name:   "验签通过",
+			header: signNotify(t, platformKey, "platform123", now, body),
+			body:   body,
+			wantPmt: domain.Payment{
+				Amt:        domain.Amount{Currency: "CNY", Total: 1},
+				BizTradeNo: "reward-1",
+				Status:     domain.PaymentStatusSuccess,
+				TxnID:      "txn1",
+			},
+		},
+		{
+			name:    "别人签的名",
+			header:  signNotify(t, otherKey, "platform123", now, body),
+			body:    body,
+			wantErr: payment.ErrInvalidNotify,
+		},
+		{
+			name:    "序列号不对",
+			header:  signNotify(t, platformKey, "platform456", now, body),
+			body:    body,
+			wantErr: payment.ErrInvalidNotify,
+		},
+		{
+			name: "时间戳太旧",
+			header: signNotify(t, platformKey, "platform123",
+				strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10), body),
+			body:    body,
+			wantErr: payment.ErrInvalidNotify,
+		},
+		{
+			name:    "内容被篡改",
+			header:  signNotify(t, platformKey, "platform123", now, body),
+			body:    notifyBody(t, `{"out_trade_no":"reward-2","trade_state":"SUCCESS"}`),
+			wantErr: payment.ErrInvalidNotify,
+		},
+		{
+			name:    "别的商户号的订单",
+			header:  signNotify(t, platformKey, "platform123", now, otherMch),
+			body:    otherMch,
+			wantErr: payment.ErrInvalidNotify,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := NewNativePaymentService(Config{
+				AppID:             "wx123",
+				MchID:             "mch123",
+				APIv3Key:          testAPIv3Key,
+				PlatformPublicKey: &platformKey.PublicKey,
+				PlatformSerialNo:  "platform123",
+			}, http.DefaultClient)
+			pmt, err := svc.HandleNotify(context.Background(), tc.header, tc.body)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantPmt, pmt)
+		})
+	}
+}
+
+var authPattern = regexp.MustCompile(`^WECHATPAY2-SHA256-RSA2048 mchid="(.*)",nonce_str="(.*)",signature="(.*)",timestamp="(.*)",serial_no="(.*)"$`)
+
+func verifyAuthorization(t *testing.T, pub *rsa.PublicKey, method, uri string, body []byte, auth string) {
+	matches := authPattern.FindStringSubmatch(auth)
+	require.Len(t, matches, 6)
+	nonce, sigStr, ts := matches[2], matches[3], matches[4]
+	sig, err := base64.StdEncoding.DecodeString(sigStr)
+	require.NoError(t, err)
+	msg := method + "\n" + uri + "\n" + ts + "\n" + nonce + "\n" + string(body) + "\n"
+	hashed := sha256.Sum256([]byte(msg))
+	assert.NoError(t, rsa.VerifyPKCS1v15(pub, crypto.SHA256, hashed[:], sig))
+}
+
+// notifyBody 模拟微信支付加密回调的内容
+func notifyBody(t *testing.T, plaintext string) []byte {
+	block, err := aes.NewCipher([]byte(testAPIv3Key))
+	require.NoError(t, err)
+	gcm, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+	nonce := "abcdefghijkl"
+	ciphertext := gcm.Seal(nil, []byte(nonce), []byte(plaintext), []byte("transaction"))
+	body, err := json.Marshal(notify{
+		Id:           "notify1",
+		EventType:    "TRANSACTION.SUCCESS",
+		ResourceType: "encrypt-resource",
+		Resource: resource{
+			Algorithm:      "AEAD_AES_256_GCM",
+			Ciphertext:     base64.StdEncoding.EncodeToString(ciphertext),
+			AssociatedData: "transaction",
+			Nonce:          nonce,
+			OriginalType:   "transaction",
+		},
+	})
+	require.NoError(t, err)
+	return body
+}
+
+func signNotify(t *testing.T, key *rsa.PrivateKey, serial, ts string, body []byte) http.Header {
+	nonce := "nonce123"
+	msg := ts + "\n" + nonce + "\n" + string(body) + "\n"
+	hashed := sha256.Sum256([]byte(msg))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	require.NoError(t, err)
+	header := http.Header{}
+	header.Set("Wechatpay-Serial", serial)
+	header.Set("Wechatpay-Timestamp", ts)
+	header.Set("Wechatpay-Nonce", nonce)
+	header.Set("Wechatpay-Signature", base64.StdEncoding.EncodeToString(sig))
+	return header
+}
@@ -0,0 +1,49 @@
+package wechat
+
+type amount struct {
+	Total    int64  `json:"total"`
+	Currency string `json:"currency"`
+}
+
+type prepayRequest struct {
+	AppID       string `json:"appid"`
+	MchID       string `json:"mchid"`
+	Description string `json:"description"`
+	OutTradeNo  string `json:"out_trade_no"`
+	NotifyURL   string `json:"notify_url"`
+	Amount      amount `json:"amount"`
+}
+
+type prepayResponse struct {
+	CodeURL string `json:"code_url"`
+}
+
+// transaction 查询订单的响应，也是回调解密之后的内容
+type transaction struct {
+	AppID         string `json:"appid"`
+	MchID         string `json:"mchid"`
+	OutTradeNo    string `json:"out_trade_no"`
+	TransactionID string `json:"transaction_id"`
+	TradeState    string `json:"trade_state"`
+	Amount        amount `json:"amount"`
+}
+
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type notify struct {
+	Id           string   `json:"id"`
+	EventType    string   `json:"event_type"`
+	ResourceType string   `json:"resource_type"`
+	Resource     resource `json:"resource"`
+}
+
+type resource struct {
+	Algorithm      string `json:"algorithm"`
+	Ciphertext     string `json:"ciphertext"`
+	AssociatedData string `json:"associated_data"`
+	Nonce          string `json:"nonce"`
+	OriginalType   string `json:"original_type"`
+}
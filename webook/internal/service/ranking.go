@@ -0,0 +1,146 @@
+package service
+
+import (
+	"container/heap"
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository"
+	"math"
+	"time"
+)
+
+// RankingService 热榜，按照点赞、收藏、阅读算热度，越久的帖子热度衰减得越厉害
+type RankingService interface {
+	// TopN 热度最高的帖子
+	TopN(ctx context.Context) ([]domain.HotArticle, error)
+	// RankTopN 从数据库全量重新计算热榜，由定时任务调用
+	RankTopN(ctx context.Context) error
+	// Refresh 帖子有了新的交互，重新计算这一篇帖子的热度
+	Refresh(ctx context.Context, id int64) error
+}
+
+type BatchRankingService struct {
+	artRepo  repository.ArticleRepository
+	intrRepo repository.InteractiveRepository
+	repo     repository.RankingRepository
+	biz      string
+	// 全量计算的时候每批处理多少篇帖子
+	batchSize int
+	n         int
+	// window 只有这段时间内发表的帖子才能上热榜
+	window time.Duration
+}
+
+func NewRankingService(artRepo repository.ArticleRepository, intrRepo repository.InteractiveRepository,
+	repo repository.RankingRepository) RankingService {
+	return &BatchRankingService{
+		artRepo:   artRepo,
+		intrRepo:  intrRepo,
+		repo:      repo,
+		biz:       "article",
+		batchSize: 100,
+		n:         100,
+		window:    time.Hour * 24 * 7,
+	}
+}
+
+func (svc *BatchRankingService) TopN(ctx context.Context) ([]domain.HotArticle, error) {
+	return svc.repo.GetTopN(ctx, svc.n)
+}
+
+func (svc *BatchRankingService) RankTopN(ctx context.Context) error {
+	now := time.Now()
+	start := now.Add(-svc.window)
+	// 只保留热度最高的 n 篇，内存不随帖子数量增长
+	top := make(hotHeap, 0, svc.n)
+	var afterId int64
+	for {
+		arts, err := svc.artRepo.ListPublishedSince(ctx, start, afterId, svc.batchSize)
+		if err != nil {
+			return err
+		}
+		if len(arts) == 0 {
+			break
+		}
+		ids := make([]int64, 0, len(arts))
+		for _, art := range arts {
+			ids = append(ids, art.Id)
+		}
+		intrs, err := svc.intrRepo.GetByIds(ctx, svc.biz, ids)
+		if err != nil {
+			return err
+		}
+		for _, art := range arts {
+			intr, ok := intrs[art.Id]
+			if !ok {
+				// 没有任何交互的帖子不上热榜
+				continue
+			}
+			ha := domain.HotArticle{
+				Article: art,
+				Score:   hotScore(intr, art.Ctime, now),
+			}
+			if top.Len() < svc.n {
+				heap.Push(&top, ha)
+			} else if ha.Score > top[0].Score {
+				top[0] = ha
+				heap.Fix(&top, 0)
+			}
+		}
+		if len(arts) < svc.batchSize {
+			break
+		}
+		afterId = arts[len(arts)-1].Id
+	}
+	res := make([]domain.HotArticle, top.Len())
+	// 堆顶是最小的，倒着放就是从高到低
+	for i := len(res) - 1; i >= 0; i-- {
+		res[i] = heap.Pop(&top).(domain.HotArticle)
+	}
+	return svc.repo.ReplaceTopN(ctx, res)
+}
+
+func (svc *BatchRankingService) Refresh(ctx context.Context, id int64) error {
+	art, err := svc.artRepo.GetPublishedById(ctx, id)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if art.Status != domain.ArticleStatusPublished || art.Ctime.Before(now.Add(-svc.window)) {
+		return nil
+	}
+	intr, err := svc.intrRepo.Get(ctx, svc.biz, id)
+	if err != nil {
+		return err
+	}
+	return svc.repo.SetScore(ctx, id, hotScore(intr, art.Ctime, now))
+}
+
+// hotScore 加权之后按发表时长衰减，和 Hacker News 的算法类似
+func hotScore(intr domain.Interactive, pubTime, now time.Time) float64 {
+	weighted := float64(intr.ReadCnt) + float64(intr.LikeCnt)*5 + float64(intr.CollectCnt)*10
+	hours := now.Sub(pubTime).Hours()
+	if hours < 0 {
+		hours = 0
+	}
+	return weighted / math.Pow(hours+2, 1.5)
+}
+
+// hotHeap 按热度排的小顶堆，堆顶是目前热榜里热度最低的
+type hotHeap []domain.HotArticle
+
+func (h hotHeap) Len() int           { return len(h) }
+func (h hotHeap) Less(i, j int) bool { return h[i].Score < h[j].Score }
+func (h hotHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *hotHeap) Push(x any) {
+	*h = append(*h, x.(domain.HotArticle))
+}
+
+func (h *hotHeap) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
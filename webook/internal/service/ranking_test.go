@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository"
+	repomocks "dream/webook/internal/repository/mocks"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHotScore(t *testing.T) {
+	now := time.Now()
+	testCases := []struct {
+		name    string
+		intr    domain.Interactive
+		pubTime time.Time
+
+		want float64
+	}{
+		{
+			name:    "没有交互",
+			pubTime: now,
+			want:    0,
+		},
+		{
+			name:    "刚发表",
+			intr:    domain.Interactive{ReadCnt: 100},
+			pubTime: now,
+			want:    100 / math.Pow(2, 1.5),
+		},
+		{
+			name:    "点赞算 5 次阅读，收藏算 10 次",
+			intr:    domain.Interactive{ReadCnt: 1, LikeCnt: 1, CollectCnt: 1},
+			pubTime: now,
+			want:    16 / math.Pow(2, 1.5),
+		},
+		{
+			name:    "发表了两个小时",
+			intr:    domain.Interactive{ReadCnt: 1, LikeCnt: 1, CollectCnt: 1},
+			pubTime: now.Add(-time.Hour * 2),
+			want:    2,
+		},
+		{
+			name:    "机器时钟不准，发表时间在未来",
+			intr:    domain.Interactive{ReadCnt: 100},
+			pubTime: now.Add(time.Hour),
+			want:    100 / math.Pow(2, 1.5),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.InDelta(t, tc.want, hotScore(tc.intr, tc.pubTime, now), 1e-9)
+		})
+	}
+}
+
+func TestHotScore_Decay(t *testing.T) {
+	now := time.Now()
+	intr := domain.Interactive{ReadCnt: 100, LikeCnt: 10}
+	// 交互一样，越早发表热度越低
+	prev := hotScore(intr, now, now)
+	for _, hours := range []int{1, 6, 24, 24 * 7} {
+		score := hotScore(intr, now.Add(-time.Hour*time.Duration(hours)), now)
+		assert.Less(t, score, prev)
+		prev = score
+	}
+	// 新帖子交互少一点，也能排在老帖子前面
+	assert.Greater(t, hotScore(domain.Interactive{ReadCnt: 100}, now, now),
+		hotScore(domain.Interactive{ReadCnt: 1000}, now.Add(-time.Hour*24), now))
+}
+
+func TestBatchRankingService_RankTopN(t *testing.T) {
+	pubTime := time.Now().Add(-time.Hour)
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.ArticleRepository,
+			repository.InteractiveRepository, repository.RankingRepository)
+
+		wantErr error
+	}{
+		{
+			name: "分批计算，只留热度最高的 n 篇",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRepository,
+				repository.InteractiveRepository, repository.RankingRepository) {
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				intrRepo := repomocks.NewMockInteractiveRepository(ctrl)
+				artRepo.EXPECT().ListPublishedSince(gomock.Any(), gomock.Any(), int64(0), 2).Return([]domain.Article{
+					{Id: 10, Ctime: pubTime},
+					{Id: 20, Ctime: pubTime},
+				}, nil)
+				intrRepo.EXPECT().GetByIds(gomock.Any(), "article", []int64{10, 20}).Return(map[int64]domain.Interactive{
+					10: {ReadCnt: 10},
+					20: {LikeCnt: 10},
+				}, nil)
+				// 按上一批最后一条的 id 翻页
+				artRepo.EXPECT().ListPublishedSince(gomock.Any(), gomock.Any(), int64(20), 2).Return([]domain.Article{
+					{Id: 30, Ctime: pubTime},
+					{Id: 40, Ctime: pubTime},
+				}, nil)
+				// 帖子 40 没有交互，不上热榜
+				intrRepo.EXPECT().GetByIds(gomock.Any(), "article", []int64{30, 40}).Return(map[int64]domain.Interactive{
+					30: {CollectCnt: 10},
+				}, nil)
+				artRepo.EXPECT().ListPublishedSince(gomock.Any(), gomock.Any(), int64(40), 2).Return([]domain.Article{
+					{Id: 50, Ctime: pubTime},
+				}, nil)
+				intrRepo.EXPECT().GetByIds(gomock.Any(), "article", []int64{50}).Return(map[int64]domain.Interactive{
+					50: {ReadCnt: 60},
+				}, nil)
+				repo := repomocks.NewMockRankingRepository(ctrl)
+				repo.EXPECT().ReplaceTopN(gomock.Any(), gomock.Cond(func(arts []domain.HotArticle) bool {
+					return len(arts) == 2 && arts[0].Article.Id == 30 && arts[1].Article.Id == 50 &&
+						arts[0].Score > arts[1].Score
+				})).Return(nil)
+				return artRepo, intrRepo, repo
+			},
+		},
+		{
+			name: "查询帖子失败",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRepository,
+				repository.InteractiveRepository, repository.RankingRepository) {
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().ListPublishedSince(gomock.Any(), gomock.Any(), int64(0), 2).
+					Return(nil, errors.New("mock db error"))
+				return artRepo, repomocks.NewMockInteractiveRepository(ctrl), repomocks.NewMockRankingRepository(ctrl)
+			},
+			wantErr: errors.New("mock db error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewRankingService(tc.mock(ctrl)).(*BatchRankingService)
+			svc.batchSize = 2
+			svc.n = 2
+			err := svc.RankTopN(context.Background())
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestBatchRankingService_Refresh(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.ArticleRepository,
+			repository.InteractiveRepository, repository.RankingRepository)
+
+		wantErr error
+	}{
+		{
+			name: "更新热度",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRepository,
+				repository.InteractiveRepository, repository.RankingRepository) {
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().GetPublishedById(gomock.Any(), int64(1)).Return(domain.Article{
+					Id: 1, Status: domain.ArticleStatusPublished, Ctime: time.Now().Add(-time.Hour),
+				}, nil)
+				intrRepo := repomocks.NewMockInteractiveRepository(ctrl)
+				intrRepo.EXPECT().Get(gomock.Any(), "article", int64(1)).
+					Return(domain.Interactive{ReadCnt: 10}, nil)
+				repo := repomocks.NewMockRankingRepository(ctrl)
+				repo.EXPECT().SetScore(gomock.Any(), int64(1), gomock.Cond(func(score float64) bool {
+					return score > 0
+				})).Return(nil)
+				return artRepo, intrRepo, repo
+			},
+		},
+		{
+			name: "撤回了的帖子",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRepository,
+				repository.InteractiveRepository, repository.RankingRepository) {
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().GetPublishedById(gomock.Any(), int64(1)).Return(domain.Article{
+					Id: 1, Status: domain.ArticleStatusPrivate, Ctime: time.Now().Add(-time.Hour),
+				}, nil)
+				return artRepo, repomocks.NewMockInteractiveRepository(ctrl), repomocks.NewMockRankingRepository(ctrl)
+			},
+		},
+		{
+			name: "太久之前发表的",
+			mock: func(ctrl *gomock.Controller) (repository.ArticleRepository,
+				repository.InteractiveRepository, repository.RankingRepository) {
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().GetPublishedById(gomock.Any(), int64(1)).Return(domain.Article{
+					Id: 1, Status: domain.ArticleStatusPublished, Ctime: time.Now().Add(-time.Hour * 24 * 30),
+				}, nil)
+				return artRepo, repomocks.NewMockInteractiveRepository(ctrl), repomocks.NewMockRankingRepository(ctrl)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewRankingService(tc.mock(ctrl))
+			err := svc.Refresh(context.Background(), 1)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
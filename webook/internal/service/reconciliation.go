@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository"
+	"dream/webook/internal/service/payment"
+	"log"
+	"time"
+)
+
+// ReconciliationService 对账，把一直卡在支付中的打赏订单和支付平台对齐
+type ReconciliationService interface {
+	// Reconcile 处理一轮超时还在支付中的订单，每个订单的结果都会记录下来
+	Reconcile(ctx context.Context) error
+	// Summary 按照对账结果汇总 [start, end) 之间的对账记录，给财务看
+	Summary(ctx context.Context, start, end time.Time) ([]domain.ReconciliationSummary, error)
+}
+
+type RewardReconciliationService struct {
+	repo    repository.ReconciliationRepository
+	rwdRepo repository.RewardRepository
+	// rwdSvc 更新订单状态要走打赏服务，支付成功的还要入账
+	rwdSvc RewardService
+	paySvc payment.Service
+	// threshold 下单超过这么久还没有结果才对账，要比二维码的缓存时间长
+	threshold time.Duration
+	batchSize int
+}
+
+func NewReconciliationService(repo repository.ReconciliationRepository,
+	rwdRepo repository.RewardRepository, rwdSvc RewardService, paySvc payment.Service) ReconciliationService {
+	return &RewardReconciliationService{
+		repo:      repo,
+		rwdRepo:   rwdRepo,
+		rwdSvc:    rwdSvc,
+		paySvc:    paySvc,
+		threshold: time.Minute * 30,
+		batchSize: 100,
+	}
+}
+
+func (svc *RewardReconciliationService) Reconcile(ctx context.Context) error {
+	before := time.Now().Add(-svc.threshold)
+	var lastId int64
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		rs, err := svc.rwdRepo.FindPaying(ctx, before, lastId, svc.batchSize)
+		if err != nil {
+			return err
+		}
+		for _, r := range rs {
+			rec := svc.reconcile(ctx, r)
+			// 记录失败不影响订单本身，继续处理下一个
+			if err = svc.repo.Create(ctx, rec); err != nil {
+				log.Println("记录对账结果失败", rec.BizTradeNo, err)
+			}
+		}
+		if len(rs) < svc.batchSize {
+			return nil
+		}
+		lastId = rs[len(rs)-1].Id
+	}
+}
+
+func (svc *RewardReconciliationService) Summary(ctx context.Context, start, end time.Time) ([]domain.ReconciliationSummary, error) {
+	return svc.repo.Summary(ctx, start, end)
+}
+
+func (svc *RewardReconciliationService) reconcile(ctx context.Context, r domain.Reward) domain.Reconciliation {
+	rec := domain.Reconciliation{
+		Biz:        "reward",
+		BizId:      r.Id,
+		BizTradeNo: rewardBizTradeNo(r.Id),
+		Amt:        r.Amt,
+	}
+	var status domain.PaymentStatus
+	pmt, err := svc.paySvc.GetPayment(ctx, rec.BizTradeNo)
+	switch {
+	case err == payment.ErrPaymentNotFound:
+		// 下单的时候就失败了，支付平台根本没有这笔订单
+		status, rec.Result = domain.PaymentStatusFailed, domain.ReconciliationResultFailed
+	case err != nil:
+		rec.Result, rec.Err = domain.ReconciliationResultError, err.Error()
+		return rec
+	case pmt.Status == domain.PaymentStatusSuccess:
+		// 支付成功了，但是回调丢了
+		status, rec.Result = pmt.Status, domain.ReconciliationResultPaid
+	case pmt.Status == domain.PaymentStatusFailed || pmt.Status == domain.PaymentStatusRefund:
+		status, rec.Result = pmt.Status, domain.ReconciliationResultFailed
+	case pmt.Status == domain.PaymentStatusInit:
+		// 一直没有支付，先关单，免得用户关单之后又付了钱
+		// 关单失败的话，可能用户刚好付了，下一轮再来
+		if err = svc.paySvc.ClosePayment(ctx, rec.BizTradeNo); err != nil {
+			rec.Result, rec.Err = domain.ReconciliationResultError, err.Error()
+			return rec
+		}
+		status, rec.Result = domain.PaymentStatusFailed, domain.ReconciliationResultClosed
+	default:
+		rec.Result, rec.Err = domain.ReconciliationResultError, "未知的支付状态"
+		return rec
+	}
+	// 支付成功的时候带上实际支付的金额，金额对不上会记成对账出错
+	pmt.BizTradeNo, pmt.Status = rec.BizTradeNo, status
+	if err = svc.rwdSvc.UpdateReward(ctx, pmt); err != nil {
+		rec.Result, rec.Err = domain.ReconciliationResultError, err.Error()
+	}
+	return rec
+}
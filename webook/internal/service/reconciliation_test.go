@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository"
+	repomocks "dream/webook/internal/repository/mocks"
+	svcmocks "dream/webook/internal/service/mocks"
+	"dream/webook/internal/service/payment"
+	pmtmocks "dream/webook/internal/service/payment/mocks"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRewardReconciliationService_Reconcile(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.ReconciliationRepository,
+			repository.RewardRepository, RewardService, payment.Service)
+
+		wantErr error
+	}{
+		{
+			name: "回调丢了，其实已经支付成功",
+			mock: func(ctrl *gomock.Controller) (repository.ReconciliationRepository,
+				repository.RewardRepository, RewardService, payment.Service) {
+				rwdRepo := repomocks.NewMockRewardRepository(ctrl)
+				rwdRepo.EXPECT().FindPaying(gomock.Any(), gomock.Any(), int64(0), 100).
+					Return([]domain.Reward{{Id: 1, Amt: 100}}, nil)
+				pmt := pmtmocks.NewMockService(ctrl)
+				pmt.EXPECT().GetPayment(gomock.Any(), "reward-1").Return(domain.Payment{
+					Amt: domain.Amount{Currency: "CNY", Total: 100}, BizTradeNo: "reward-1", Status: domain.PaymentStatusSuccess,
+				}, nil)
+				rwdSvc := svcmocks.NewMockRewardService(ctrl)
+				rwdSvc.EXPECT().UpdateReward(gomock.Any(), domain.Payment{
+					Amt: domain.Amount{Currency: "CNY", Total: 100}, BizTradeNo: "reward-1", Status: domain.PaymentStatusSuccess,
+				}).Return(nil)
+				repo := repomocks.NewMockReconciliationRepository(ctrl)
+				repo.EXPECT().Create(gomock.Any(), domain.Reconciliation{
+					Biz: "reward", BizId: 1, BizTradeNo: "reward-1", Amt: 100,
+					Result: domain.ReconciliationResultPaid,
+				}).Return(nil)
+				return repo, rwdRepo, rwdSvc, pmt
+			},
+		},
+		{
+			name: "一直没有支付，关单",
+			mock: func(ctrl *gomock.Controller) (repository.ReconciliationRepository,
+				repository.RewardRepository, RewardService, payment.Service) {
+				rwdRepo := repomocks.NewMockRewardRepository(ctrl)
+				rwdRepo.EXPECT().FindPaying(gomock.Any(), gomock.Any(), int64(0), 100).
+					Return([]domain.Reward{{Id: 1, Amt: 100}}, nil)
+				pmt := pmtmocks.NewMockService(ctrl)
+				pmt.EXPECT().GetPayment(gomock.Any(), "reward-1").Return(domain.Payment{
+					BizTradeNo: "reward-1", Status: domain.PaymentStatusInit,
+				}, nil)
+				pmt.EXPECT().ClosePayment(gomock.Any(), "reward-1").Return(nil)
+				rwdSvc := svcmocks.NewMockRewardService(ctrl)
+				rwdSvc.EXPECT().UpdateReward(gomock.Any(), domain.Payment{
+					BizTradeNo: "reward-1", Status: domain.PaymentStatusFailed,
+				}).Return(nil)
+				repo := repomocks.NewMockReconciliationRepository(ctrl)
+				repo.EXPECT().Create(gomock.Any(), domain.Reconciliation{
+					Biz: "reward", BizId: 1, BizTradeNo: "reward-1", Amt: 100,
+					Result: domain.ReconciliationResultClosed,
+				}).Return(nil)
+				return repo, rwdRepo, rwdSvc, pmt
+			},
+		},
+		{
+			name: "关单失败，订单状态不动",
+			mock: func(ctrl *gomock.Controller) (repository.ReconciliationRepository,
+				repository.RewardRepository, RewardService, payment.Service) {
+				rwdRepo := repomocks.NewMockRewardRepository(ctrl)
+				rwdRepo.EXPECT().FindPaying(gomock.Any(), gomock.Any(), int64(0), 100).
+					Return([]domain.Reward{{Id: 1, Amt: 100}}, nil)
+				pmt := pmtmocks.NewMockService(ctrl)
+				pmt.EXPECT().GetPayment(gomock.Any(), "reward-1").Return(domain.Payment{
+					BizTradeNo: "reward-1", Status: domain.PaymentStatusInit,
+				}, nil)
+				pmt.EXPECT().ClosePayment(gomock.Any(), "reward-1").
+					Return(errors.New("mock close error"))
+				repo := repomocks.NewMockReconciliationRepository(ctrl)
+				repo.EXPECT().Create(gomock.Any(), domain.Reconciliation{
+					Biz: "reward", BizId: 1, BizTradeNo: "reward-1", Amt: 100,
+					Result: domain.ReconciliationResultError, Err: "mock close error",
+				}).Return(nil)
+				return repo, rwdRepo, svcmocks.NewMockRewardService(ctrl), pmt
+			},
+		},
+		{
+			name: "支付平台没有这个订单",
+			mock: func(ctrl *gomock.Controller) (repository.ReconciliationRepository,
+				repository.RewardRepository, RewardService, payment.Service) {
+				rwdRepo := repomocks.NewMockRewardRepository(ctrl)
+				rwdRepo.EXPECT().FindPaying(gomock.Any(), gomock.Any(), int64(0), 100).
+					Return([]domain.Reward{{Id: 1, Amt: 100}}, nil)
+				pmt := pmtmocks.NewMockService(ctrl)
+				pmt.EXPECT().GetPayment(gomock.Any(), "reward-1").
+					Return(domain.Payment{}, payment.ErrPaymentNotFound)
+				rwdSvc := svcmocks.NewMockRewardService(ctrl)
+				rwdSvc.EXPECT().UpdateReward(gomock.Any(), domain.Payment{
+					BizTradeNo: "reward-1", Status: domain.PaymentStatusFailed,
+				}).Return(nil)
+				repo := repomocks.NewMockReconciliationRepository(ctrl)
+				repo.EXPECT().Create(gomock.Any(), domain.Reconciliation{
+					Biz: "reward", BizId: 1, BizTradeNo: "reward-1", Amt: 100,
+					Result: domain.ReconciliationResultFailed,
+				}).Return(nil)
+				return repo, rwdRepo, rwdSvc, pmt
+			},
+		},
+		{
+			name: "查询订单失败",
+			mock: func(ctrl *gomock.Controller) (repository.ReconciliationRepository,
+				repository.RewardRepository, RewardService, payment.Service) {
+				rwdRepo := repomocks.NewMockRewardRepository(ctrl)
+				rwdRepo.EXPECT().FindPaying(gomock.Any(), gomock.Any(), int64(0), 100).
+					Return(nil, errors.New("mock db error"))
+				return repomocks.NewMockReconciliationRepository(ctrl), rwdRepo,
+					svcmocks.NewMockRewardService(ctrl), pmtmocks.NewMockService(ctrl)
+			},
+			wantErr: errors.New("mock db error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewReconciliationService(tc.mock(ctrl))
+			err := svc.Reconcile(context.Background())
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository"
+	"dream/webook/internal/service/payment"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrRewardNotFound = repository.ErrRewardNotFound
+	// ErrRewardSelf 不能打赏自己
+	ErrRewardSelf = errors.New("不能打赏自己")
+	// ErrInvalidBizTradeNo 支付回调里的单号不是打赏订单
+	ErrInvalidBizTradeNo = errors.New("非法的业务单号")
+	// ErrPaymentMismatch 支付的金额和打赏订单对不上，不能入账，需要人工处理
+	ErrPaymentMismatch = errors.New("支付金额和打赏订单不一致")
+)
+
+// rewardCurrency 打赏只支持人民币
+const rewardCurrency = "CNY"
+
+// RewardService 打赏，真正的支付交给 payment.Service
+type RewardService interface {
+	// PreReward 创建打赏订单，返回支付二维码
+	PreReward(ctx context.Context, r domain.Reward) (domain.CodeURL, error)
+	// GetReward 查询打赏订单，只能查自己的
+	GetReward(ctx context.Context, rid, uid int64) (domain.Reward, error)
+	// UpdateReward 支付结果回来之后更新打赏订单，pmt.BizTradeNo 是下单时候的业务单号。
+	// 支付成功的时候金额和订单对不上，返回 ErrPaymentMismatch
+	UpdateReward(ctx context.Context, pmt domain.Payment) error
+}
+
+type NormalRewardService struct {
+	repo   repository.RewardRepository
+	paySvc payment.Service
+	accSvc AccountService
+	// commission 平台抽成的百分比，剩下的都给作者
+	commission int64
+}
+
+func NewRewardService(repo repository.RewardRepository, paySvc payment.Service, accSvc AccountService) RewardService {
+	return &NormalRewardService{
+		repo:       repo,
+		paySvc:     paySvc,
+		accSvc:     accSvc,
+		commission: 10,
+	}
+}
+
+func (svc *NormalRewardService) PreReward(ctx context.Context, r domain.Reward) (domain.CodeURL, error) {
+	if r.Uid == r.Target.Uid {
+		return domain.CodeURL{}, ErrRewardSelf
+	}
+	cu, err := svc.repo.GetCachedCodeURL(ctx, r)
+	if err == nil && svc.reusable(ctx, cu) {
+		return cu, nil
+	}
+	r.Status = domain.RewardStatusInit
+	rid, err := svc.repo.CreateReward(ctx, r)
+	if err != nil {
+		return domain.CodeURL{}, err
+	}
+	url, err := svc.paySvc.Prepay(ctx, domain.Payment{
+		Amt: domain.Amount{
+			Currency: rewardCurrency,
+			Total:    r.Amt,
+		},
+		BizTradeNo:  rewardBizTradeNo(rid),
+		Description: fmt.Sprintf("打赏-%s", r.Target.BizName),
+	})
+	if err != nil {
+		return domain.CodeURL{}, err
+	}
+	cu = domain.CodeURL{
+		Rid: rid,
+		URL: url,
+	}
+	// 缓存失败了也没关系，最多就是重复下单
+	_ = svc.repo.CachedCodeURL(ctx, cu, r)
+	return cu, nil
+}
+
+// reusable 缓存的订单还在等支付才能复用，已经支付或者关单的要重新下单
+func (svc *NormalRewardService) reusable(ctx context.Context, cu domain.CodeURL) bool {
+	r, err := svc.repo.GetReward(ctx, cu.Rid)
+	return err == nil && r.Status == domain.RewardStatusInit
+}
+
+func (svc *NormalRewardService) GetReward(ctx context.Context, rid, uid int64) (domain.Reward, error) {
+	r, err := svc.repo.GetReward(ctx, rid)
+	if err != nil {
+		return domain.Reward{}, err
+	}
+	// 不能看别人的打赏订单
+	if r.Uid != uid {
+		return domain.Reward{}, ErrRewardNotFound
+	}
+	if r.Status != domain.RewardStatusInit {
+		return r, nil
+	}
+	// 还没有收到支付结果，主动去支付平台查一下
+	pmt, err := svc.paySvc.GetPayment(ctx, rewardBizTradeNo(rid))
+	if err != nil {
+		// 查不到就按照原来的状态返回，前端会再来查
+		return r, nil
+	}
+	status := svc.toRewardStatus(pmt.Status)
+	if status == r.Status || status == domain.RewardStatusUnknown {
+		return r, nil
+	}
+	if status == domain.RewardStatusPayed {
+		if err = svc.checkPayment(r, pmt); err != nil {
+			// 对账的时候会再检查一次，记到对账结果里面
+			log.Println(err)
+			return r, nil
+		}
+	}
+	if err = svc.updateStatus(ctx, rid, status); err != nil {
+		return domain.Reward{}, err
+	}
+	r.Status = status
+	return r, nil
+}
+
+func (svc *NormalRewardService) UpdateReward(ctx context.Context, pmt domain.Payment) error {
+	rid, err := svc.toRid(pmt.BizTradeNo)
+	if err != nil {
+		return err
+	}
+	rs := svc.toRewardStatus(pmt.Status)
+	// 还没有支付的通知不需要处理
+	if rs == domain.RewardStatusUnknown || rs == domain.RewardStatusInit {
+		return nil
+	}
+	if rs == domain.RewardStatusPayed {
+		r, err := svc.repo.GetReward(ctx, rid)
+		if err != nil {
+			return err
+		}
+		if err = svc.checkPayment(r, pmt); err != nil {
+			return err
+		}
+	}
+	return svc.updateStatus(ctx, rid, rs)
+}
+
+// checkPayment 入账用的是订单上的金额，所以实际支付的金额必须和订单一致
+func (svc *NormalRewardService) checkPayment(r domain.Reward, pmt domain.Payment) error {
+	if pmt.Amt.Total != r.Amt || pmt.Amt.Currency != rewardCurrency {
+		return fmt.Errorf("%w，打赏 %d 金额 %d，实际支付 %d %s",
+			ErrPaymentMismatch, r.Id, r.Amt, pmt.Amt.Total, pmt.Amt.Currency)
+	}
+	return nil
+}
+
+// updateStatus 支付成功之后入账，入账失败返回 error，支付平台会重新通知
+func (svc *NormalRewardService) updateStatus(ctx context.Context, rid int64, status domain.RewardStatus) error {
+	if err := svc.repo.UpdateStatus(ctx, rid, status); err != nil {
+		return err
+	}
+	if status != domain.RewardStatusPayed {
+		return nil
+	}
+	// 重新查一次，已经关单的订单不能入账
+	// 之前更新成功但是入账失败的，重试的时候也会走到这里，入账本身是幂等的
+	r, err := svc.repo.GetReward(ctx, rid)
+	if err != nil {
+		return err
+	}
+	if r.Status != domain.RewardStatusPayed {
+		return nil
+	}
+	platform := r.Amt * svc.commission / 100
+	items := []domain.CreditItem{
+		{
+			Uid:      r.Target.Uid,
+			Type:     domain.AccountTypePersonal,
+			Amt:      r.Amt - platform,
+			Currency: rewardCurrency,
+		},
+	}
+	if platform > 0 {
+		items = append(items, domain.CreditItem{
+			Type:     domain.AccountTypePlatform,
+			Amt:      platform,
+			Currency: rewardCurrency,
+		})
+	}
+	return svc.accSvc.Credit(ctx, domain.Credit{
+		Biz:   "reward",
+		BizId: rid,
+		Items: items,
+	})
+}
+
+// rewardBizTradeNo 打赏订单在支付平台那边的业务单号
+func rewardBizTradeNo(rid int64) string {
+	return fmt.Sprintf("reward-%d", rid)
+}
+
+func (svc *NormalRewardService) toRid(bizTradeNo string) (int64, error) {
+	ridStr, ok := strings.CutPrefix(bizTradeNo, "reward-")
+	if !ok {
+		return 0, ErrInvalidBizTradeNo
+	}
+	rid, err := strconv.ParseInt(ridStr, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidBizTradeNo
+	}
+	return rid, nil
+}
+
+func (svc *NormalRewardService) toRewardStatus(status domain.PaymentStatus) domain.RewardStatus {
+	switch status {
+	case domain.PaymentStatusSuccess:
+		return domain.RewardStatusPayed
+	case domain.PaymentStatusFailed, domain.PaymentStatusRefund:
+		return domain.RewardStatusFailed
+	case domain.PaymentStatusInit:
+		return domain.RewardStatusInit
+	default:
+		return domain.RewardStatusUnknown
+	}
+}
@@ -0,0 +1,291 @@
+package service
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository"
+	repomocks "dream/webook/internal/repository/mocks"
+	svcmocks "dream/webook/internal/service/mocks"
+	"dream/webook/internal/service/payment"
+	pmtmocks "dream/webook/internal/service/payment/mocks"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNormalRewardService_GetReward(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.RewardRepository, payment.Service, AccountService)
+		uid  int64
+
+		wantStatus domain.RewardStatus
+		wantErr    error
+	}{
+		{
+			name: "已经支付过了，不用再查",
+			mock: func(ctrl *gomock.Controller) (repository.RewardRepository, payment.Service, AccountService) {
+				repo := repomocks.NewMockRewardRepository(ctrl)
+				repo.EXPECT().GetReward(gomock.Any(), int64(1)).Return(domain.Reward{
+					Id: 1, Uid: 123, Status: domain.RewardStatusPayed,
+				}, nil)
+				return repo, pmtmocks.NewMockService(ctrl), svcmocks.NewMockAccountService(ctrl)
+			},
+			uid:        123,
+			wantStatus: domain.RewardStatusPayed,
+		},
+		{
+			name: "查询支付平台，支付成功",
+			mock: func(ctrl *gomock.Controller) (repository.RewardRepository, payment.Service, AccountService) {
+				repo := repomocks.NewMockRewardRepository(ctrl)
+				repo.EXPECT().GetReward(gomock.Any(), int64(1)).Return(domain.Reward{
+					Id: 1, Uid: 123, Amt: 1000, Status: domain.RewardStatusInit,
+				}, nil)
+				pmt := pmtmocks.NewMockService(ctrl)
+				pmt.EXPECT().GetPayment(gomock.Any(), "reward-1").Return(domain.Payment{
+					Amt: domain.Amount{Currency: "CNY", Total: 1000}, BizTradeNo: "reward-1", Status: domain.PaymentStatusSuccess,
+				}, nil)
+				repo.EXPECT().UpdateStatus(gomock.Any(), int64(1), domain.RewardStatusPayed).Return(nil)
+				repo.EXPECT().GetReward(gomock.Any(), int64(1)).Return(domain.Reward{
+					Id: 1, Uid: 123, Target: domain.Target{Uid: 456}, Amt: 1000, Status: domain.RewardStatusPayed,
+				}, nil)
+				accSvc := svcmocks.NewMockAccountService(ctrl)
+				accSvc.EXPECT().Credit(gomock.Any(), domain.Credit{
+					Biz:   "reward",
+					BizId: 1,
+					Items: []domain.CreditItem{
+						{Uid: 456, Type: domain.AccountTypePersonal, Amt: 900, Currency: "CNY"},
+						{Type: domain.AccountTypePlatform, Amt: 100, Currency: "CNY"},
+					},
+				}).Return(nil)
+				return repo, pmt, accSvc
+			},
+			uid:        123,
+			wantStatus: domain.RewardStatusPayed,
+		},
+		{
+			name: "支付金额不对，不入账",
+			mock: func(ctrl *gomock.Controller) (repository.RewardRepository, payment.Service, AccountService) {
+				repo := repomocks.NewMockRewardRepository(ctrl)
+				repo.EXPECT().GetReward(gomock.Any(), int64(1)).Return(domain.Reward{
+					Id: 1, Uid: 123, Amt: 1000, Status: domain.RewardStatusInit,
+				}, nil)
+				pmt := pmtmocks.NewMockService(ctrl)
+				pmt.EXPECT().GetPayment(gomock.Any(), "reward-1").Return(domain.Payment{
+					Amt: domain.Amount{Currency: "CNY", Total: 1}, BizTradeNo: "reward-1", Status: domain.PaymentStatusSuccess,
+				}, nil)
+				return repo, pmt, svcmocks.NewMockAccountService(ctrl)
+			},
+			uid:        123,
+			wantStatus: domain.RewardStatusInit,
+		},
+		{
+			name: "支付平台查询失败，返回原来的状态",
+			mock: func(ctrl *gomock.Controller) (repository.RewardRepository, payment.Service, AccountService) {
+				repo := repomocks.NewMockRewardRepository(ctrl)
+				repo.EXPECT().GetReward(gomock.Any(), int64(1)).Return(domain.Reward{
+					Id: 1, Uid: 123, Status: domain.RewardStatusInit,
+				}, nil)
+				pmt := pmtmocks.NewMockService(ctrl)
+				pmt.EXPECT().GetPayment(gomock.Any(), "reward-1").
+					Return(domain.Payment{}, errors.New("mock payment error"))
+				return repo, pmt, svcmocks.NewMockAccountService(ctrl)
+			},
+			uid:        123,
+			wantStatus: domain.RewardStatusInit,
+		},
+		{
+			name: "查别人的打赏",
+			mock: func(ctrl *gomock.Controller) (repository.RewardRepository, payment.Service, AccountService) {
+				repo := repomocks.NewMockRewardRepository(ctrl)
+				repo.EXPECT().GetReward(gomock.Any(), int64(1)).Return(domain.Reward{
+					Id: 1, Uid: 123, Status: domain.RewardStatusPayed,
+				}, nil)
+				return repo, pmtmocks.NewMockService(ctrl), svcmocks.NewMockAccountService(ctrl)
+			},
+			uid:     456,
+			wantErr: ErrRewardNotFound,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewRewardService(tc.mock(ctrl))
+			r, err := svc.GetReward(context.Background(), 1, tc.uid)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantStatus, r.Status)
+		})
+	}
+}
+
+func TestNormalRewardService_UpdateReward(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.RewardRepository, payment.Service, AccountService)
+		pmt  domain.Payment
+
+		wantErr error
+	}{
+		{
+			name: "支付成功，入账",
+			mock: func(ctrl *gomock.Controller) (repository.RewardRepository, payment.Service, AccountService) {
+				repo := repomocks.NewMockRewardRepository(ctrl)
+				repo.EXPECT().GetReward(gomock.Any(), int64(1)).Return(domain.Reward{
+					Id: 1, Uid: 123, Amt: 1000, Status: domain.RewardStatusInit,
+				}, nil)
+				repo.EXPECT().UpdateStatus(gomock.Any(), int64(1), domain.RewardStatusPayed).Return(nil)
+				repo.EXPECT().GetReward(gomock.Any(), int64(1)).Return(domain.Reward{
+					Id: 1, Uid: 123, Target: domain.Target{Uid: 456}, Amt: 1000, Status: domain.RewardStatusPayed,
+				}, nil)
+				accSvc := svcmocks.NewMockAccountService(ctrl)
+				accSvc.EXPECT().Credit(gomock.Any(), gomock.Any()).Return(nil)
+				return repo, pmtmocks.NewMockService(ctrl), accSvc
+			},
+			pmt: domain.Payment{
+				Amt:        domain.Amount{Currency: "CNY", Total: 1000},
+				BizTradeNo: "reward-1",
+				Status:     domain.PaymentStatusSuccess,
+			},
+		},
+		{
+			name: "支付金额和订单不一致",
+			mock: func(ctrl *gomock.Controller) (repository.RewardRepository, payment.Service, AccountService) {
+				repo := repomocks.NewMockRewardRepository(ctrl)
+				repo.EXPECT().GetReward(gomock.Any(), int64(1)).Return(domain.Reward{
+					Id: 1, Uid: 123, Amt: 1000, Status: domain.RewardStatusInit,
+				}, nil)
+				return repo, pmtmocks.NewMockService(ctrl), svcmocks.NewMockAccountService(ctrl)
+			},
+			pmt: domain.Payment{
+				Amt:        domain.Amount{Currency: "CNY", Total: 1},
+				BizTradeNo: "reward-1",
+				Status:     domain.PaymentStatusSuccess,
+			},
+			wantErr: ErrPaymentMismatch,
+		},
+		{
+			name: "币种不对",
+			mock: func(ctrl *gomock.Controller) (repository.RewardRepository, payment.Service, AccountService) {
+				repo := repomocks.NewMockRewardRepository(ctrl)
+				repo.EXPECT().GetReward(gomock.Any(), int64(1)).Return(domain.Reward{
+					Id: 1, Uid: 123, Amt: 1000, Status: domain.RewardStatusInit,
+				}, nil)
+				return repo, pmtmocks.NewMockService(ctrl), svcmocks.NewMockAccountService(ctrl)
+			},
+			pmt: domain.Payment{
+				Amt:        domain.Amount{Currency: "USD", Total: 1000},
+				BizTradeNo: "reward-1",
+				Status:     domain.PaymentStatusSuccess,
+			},
+			wantErr: ErrPaymentMismatch,
+		},
+		{
+			name: "支付失败不用检查金额",
+			mock: func(ctrl *gomock.Controller) (repository.RewardRepository, payment.Service, AccountService) {
+				repo := repomocks.NewMockRewardRepository(ctrl)
+				repo.EXPECT().UpdateStatus(gomock.Any(), int64(1), domain.RewardStatusFailed).Return(nil)
+				return repo, pmtmocks.NewMockService(ctrl), svcmocks.NewMockAccountService(ctrl)
+			},
+			pmt: domain.Payment{
+				BizTradeNo: "reward-1",
+				Status:     domain.PaymentStatusFailed,
+			},
+		},
+		{
+			name: "不是打赏的单号",
+			mock: func(ctrl *gomock.Controller) (repository.RewardRepository, payment.Service, AccountService) {
+				return repomocks.NewMockRewardRepository(ctrl), pmtmocks.NewMockService(ctrl),
+					svcmocks.NewMockAccountService(ctrl)
+			},
+			pmt: domain.Payment{
+				BizTradeNo: "order-1",
+				Status:     domain.PaymentStatusSuccess,
+			},
+			wantErr: ErrInvalidBizTradeNo,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewRewardService(tc.mock(ctrl))
+			err := svc.UpdateReward(context.Background(), tc.pmt)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestNormalRewardService_PreReward(t *testing.T) {
+	rwd := domain.Reward{
+		Uid:    123,
+		Target: domain.Target{Biz: "article", BizId: 1, BizName: "标题", Uid: 456},
+		Amt:    1000,
+	}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.RewardRepository, payment.Service, AccountService)
+		req  domain.Reward
+
+		wantCU  domain.CodeURL
+		wantErr error
+	}{
+		{
+			name: "缓存的订单还在等支付，直接复用",
+			mock: func(ctrl *gomock.Controller) (repository.RewardRepository, payment.Service, AccountService) {
+				repo := repomocks.NewMockRewardRepository(ctrl)
+				repo.EXPECT().GetCachedCodeURL(gomock.Any(), rwd).
+					Return(domain.CodeURL{Rid: 1, URL: "weixin://1"}, nil)
+				repo.EXPECT().GetReward(gomock.Any(), int64(1)).
+					Return(domain.Reward{Id: 1, Status: domain.RewardStatusInit}, nil)
+				return repo, pmtmocks.NewMockService(ctrl), svcmocks.NewMockAccountService(ctrl)
+			},
+			req:    rwd,
+			wantCU: domain.CodeURL{Rid: 1, URL: "weixin://1"},
+		},
+		{
+			name: "缓存的订单已经支付了，重新下单",
+			mock: func(ctrl *gomock.Controller) (repository.RewardRepository, payment.Service, AccountService) {
+				repo := repomocks.NewMockRewardRepository(ctrl)
+				repo.EXPECT().GetCachedCodeURL(gomock.Any(), rwd).
+					Return(domain.CodeURL{Rid: 1, URL: "weixin://1"}, nil)
+				repo.EXPECT().GetReward(gomock.Any(), int64(1)).
+					Return(domain.Reward{Id: 1, Status: domain.RewardStatusPayed}, nil)
+				created := rwd
+				created.Status = domain.RewardStatusInit
+				repo.EXPECT().CreateReward(gomock.Any(), created).Return(int64(2), nil)
+				pmt := pmtmocks.NewMockService(ctrl)
+				pmt.EXPECT().Prepay(gomock.Any(), domain.Payment{
+					Amt:         domain.Amount{Currency: "CNY", Total: 1000},
+					BizTradeNo:  "reward-2",
+					Description: "打赏-标题",
+				}).Return("weixin://2", nil)
+				repo.EXPECT().CachedCodeURL(gomock.Any(), domain.CodeURL{Rid: 2, URL: "weixin://2"}, created).Return(nil)
+				return repo, pmt, svcmocks.NewMockAccountService(ctrl)
+			},
+			req:    rwd,
+			wantCU: domain.CodeURL{Rid: 2, URL: "weixin://2"},
+		},
+		{
+			name: "不能打赏自己",
+			mock: func(ctrl *gomock.Controller) (repository.RewardRepository, payment.Service, AccountService) {
+				return repomocks.NewMockRewardRepository(ctrl), pmtmocks.NewMockService(ctrl),
+					svcmocks.NewMockAccountService(ctrl)
+			},
+			req:     domain.Reward{Uid: 123, Target: domain.Target{Uid: 123}, Amt: 1000},
+			wantErr: ErrRewardSelf,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewRewardService(tc.mock(ctrl))
+			cu, err := svc.PreReward(context.Background(), tc.req)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantCU, cu)
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package aliyun
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"dream/webook/internal/service/sms"
+	"dream/webook/internal/service/sms/ratelimit"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultEndpoint 阿里云短信的接口地址，测试的时候换成本地的
+const DefaultEndpoint = "https://dysmsapi.aliyuncs.com"
+
+var (
+	ErrInvalidNumber = errors.New("手机号码不对")
+	// ErrInvalidTemplate 模板、签名或者模板参数不对，要改配置
+	ErrInvalidTemplate   = errors.New("短信模板或者签名不对")
+	ErrBalanceNotEnough  = errors.New("阿里云账户余额不足")
+	ErrAuthFailed        = errors.New("阿里云 AccessKey 不对")
+	errMissingParamNames = errors.New("模板没有配置参数名")
+)
+
+type Config struct {
+	AccessKeyId     string
+	AccessKeySecret string
+	SignName        string
+	// Endpoint 为空的话用 DefaultEndpoint
+	Endpoint string
+	// ParamNames 阿里云的模板参数是有名字的，key 是模板 code，
+	// value 按照顺序对应 Send 的 args。只用 SendNamed 的话不用配置
+	ParamNames map[string][]string
+}
+
+var _ sms.Service = (*Service)(nil)
+
+type Service struct {
+	cfg    Config
+	client *http.Client
+}
+
+// NewService client 由外面注入，可以设置超时，或者包一层熔断
+func NewService(client *http.Client, cfg Config) *Service {
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = DefaultEndpoint
+	}
+	return &Service{
+		cfg:    cfg,
+		client: client,
+	}
+}
+
+func (s *Service) Send(ctx context.Context, tpl string, args []string, numbers ...string) error {
+	names, ok := s.cfg.ParamNames[tpl]
+	if !ok || len(names) != len(args) {
+		return fmt.Errorf("%w %s", errMissingParamNames, tpl)
+	}
+	named := make([]sms.NamedArg, 0, len(args))
+	for i, arg := range args {
+		named = append(named, sms.NamedArg{Name: names[i], Val: arg})
+	}
+	return s.SendNamed(ctx, tpl, named, numbers...)
+}
+
+func (s *Service) SendNamed(ctx context.Context, tpl string, args []sms.NamedArg, numbers ...string) error {
+	params := make(map[string]string, len(args))
+	for _, arg := range args {
+		params[arg.Name] = arg.Val
+	}
+	tplParam, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	nonce, err := s.nonce()
+	if err != nil {
+		return err
+	}
+	query := map[string]string{
+		"AccessKeyId":      s.cfg.AccessKeyId,
+		"Action":           "SendSms",
+		"Format":           "JSON",
+		"PhoneNumbers":     strings.Join(numbers, ","),
+		"RegionId":         "cn-hangzhou",
+		"SignName":         s.cfg.SignName,
+		"SignatureMethod":  "HMAC-SHA1",
+		"SignatureNonce":   nonce,
+		"SignatureVersion": "1.0",
+		"TemplateCode":     tpl,
+		"TemplateParam":    string(tplParam),
+		"Timestamp":        time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+		"Version":          "2017-05-25",
+	}
+	canonical := canonicalize(query)
+	target := fmt.Sprintf("%s/?Signature=%s&%s", s.cfg.Endpoint,
+		percentEncode(sign(http.MethodGet, canonical, s.cfg.AccessKeySecret)), canonical)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// 出错的时候 HTTP 状态码也可能不是 200，但是响应体里面一样有 Code
+	var res Result
+	if err = json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return fmt.Errorf("解析阿里云短信响应失败，状态码 %d，%w", resp.StatusCode, err)
+	}
+	return toError(res)
+}
+
+func (s *Service) nonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// toError 把阿里云的错误码转成我们的错误，调用方关心的几种单独列出来
+func toError(res Result) error {
+	switch res.Code {
+	case "OK":
+		return nil
+	case "isv.BUSINESS_LIMIT_CONTROL", "Throttling.User":
+		// 阿里云那边限流了，和我们自己限流一样处理
+		return fmt.Errorf("%w: %s", ratelimit.ErrLimited, res.Message)
+	case "isv.MOBILE_NUMBER_ILLEGAL", "isv.MOBILE_COUNT_OVER_LIMIT":
+		return fmt.Errorf("%w: %s", ErrInvalidNumber, res.Message)
+	case "isv.SMS_TEMPLATE_ILLEGAL", "isv.SMS_SIGNATURE_ILLEGAL",
+		"isv.TEMPLATE_MISSING_PARAMETERS", "isv.INVALID_PARAMETERS":
+		return fmt.Errorf("%w: %s", ErrInvalidTemplate, res.Message)
+	case "isv.AMOUNT_NOT_ENOUGH", "isv.OUT_OF_SERVICE":
+		return fmt.Errorf("%w: %s", ErrBalanceNotEnough, res.Message)
+	case "SignatureDoesNotMatch", "InvalidAccessKeyId.NotFound", "isv.ACCOUNT_NOT_EXISTS":
+		return fmt.Errorf("%w: %s", ErrAuthFailed, res.Message)
+	default:
+		return fmt.Errorf("短信发送失败 %s, %s", res.Code, res.Message)
+	}
+}
+
+// canonicalize 按照参数名排序之后拼起来，签名和发送用的是同一个字符串
+func canonicalize(query map[string]string) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, percentEncode(k)+"="+percentEncode(query[k]))
+	}
+	return strings.Join(pairs, "&")
+}
+
+// sign 阿里云 RPC 风格接口的签名，HMAC-SHA1，密钥后面要加一个 &
+func sign(method, canonical, secret string) string {
+	stringToSign := method + "&" + percentEncode("/") + "&" + percentEncode(canonical)
+	mac := hmac.New(sha1.New, []byte(secret+"&"))
+	mac.Write([]byte(stringToSign))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// percentEncode 阿里云要求 RFC 3986 的编码，和 url.QueryEscape 有几个字符不一样
+func percentEncode(s string) string {
+	s = url.QueryEscape(s)
+	s = strings.ReplaceAll(s, "+", "%20")
+	s = strings.ReplaceAll(s, "*", "%2A")
+	return strings.ReplaceAll(s, "%7E", "~")
+}
+
+type Result struct {
+	Code      string `json:"Code"`
+	Message   string `json:"Message"`
+	BizId     string `json:"BizId"`
+	RequestId string `json:"RequestId"`
+}
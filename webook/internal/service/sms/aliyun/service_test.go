@@ -0,0 +1,142 @@
+package aliyun
+
+import (
+	"context"
+	"dream/webook/internal/service/sms"
+	"dream/webook/internal/service/sms/ratelimit"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 阿里云文档里面的签名示例
+func TestSign(t *testing.T) {
+	canonical := canonicalize(map[string]string{
+		"AccessKeyId":      "testId",
+		"Action":           "SendSms",
+		"Format":           "XML",
+		"OutId":            "123",
+		"PhoneNumbers":     "15300000001",
+		"RegionId":         "cn-hangzhou",
+		"SignName":         "阿里云短信测试专用",
+		"SignatureMethod":  "HMAC-SHA1",
+		"SignatureNonce":   "45e25e9b-0a6f-4070-8c85-2956eda1b466",
+		"SignatureVersion": "1.0",
+		"TemplateCode":     "SMS_71390007",
+		"TemplateParam":    `{"customer":"test"}`,
+		"Timestamp":        "2017-07-12T02:42:19Z",
+		"Version":          "2017-05-25",
+	})
+	assert.Equal(t, "zJDF+Lrzhj/ThnlvIToysFRq6t4=", sign(http.MethodGet, canonical, "testSecret"))
+}
+
+func TestService_Send(t *testing.T) {
+	testCases := []struct {
+		name    string
+		tpl     string
+		args    []string
+		named   []sms.NamedArg
+		code    string
+		status  int
+		wantErr error
+	}{
+		{
+			name:   "发送成功",
+			tpl:    "SMS_1",
+			args:   []string{"123456"},
+			code:   "OK",
+			status: http.StatusOK,
+		},
+		{
+			name:   "按照名字传参数",
+			tpl:    "SMS_2",
+			named:  []sms.NamedArg{{Name: "code", Val: "123456"}},
+			code:   "OK",
+			status: http.StatusOK,
+		},
+		{
+			name:    "阿里云限流",
+			tpl:     "SMS_1",
+			args:    []string{"123456"},
+			code:    "isv.BUSINESS_LIMIT_CONTROL",
+			status:  http.StatusOK,
+			wantErr: ratelimit.ErrLimited,
+		},
+		{
+			name:    "手机号码不对",
+			tpl:     "SMS_1",
+			args:    []string{"123456"},
+			code:    "isv.MOBILE_NUMBER_ILLEGAL",
+			status:  http.StatusOK,
+			wantErr: ErrInvalidNumber,
+		},
+		{
+			name:    "签名不对",
+			tpl:     "SMS_1",
+			args:    []string{"123456"},
+			code:    "SignatureDoesNotMatch",
+			status:  http.StatusBadRequest,
+			wantErr: ErrAuthFailed,
+		},
+		{
+			name:    "模板参数个数不对",
+			tpl:     "SMS_1",
+			args:    []string{"123456", "5"},
+			wantErr: errMissingParamNames,
+		},
+		{
+			name:    "模板没有配置",
+			tpl:     "SMS_2",
+			args:    []string{"123456"},
+			wantErr: errMissingParamNames,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				q := r.URL.Query()
+				params := make(map[string]string, len(q))
+				for k := range q {
+					if k != "Signature" {
+						params[k] = q.Get(k)
+					}
+				}
+				// 和阿里云一样，用收到的参数重新算一遍签名
+				assert.Equal(t, sign(http.MethodGet, canonicalize(params), "secret"), q.Get("Signature"))
+				assert.Equal(t, "key", params["AccessKeyId"])
+				assert.Equal(t, "SendSms", params["Action"])
+				assert.Equal(t, "签名", params["SignName"])
+				assert.Equal(t, "15212345678,15312345678", params["PhoneNumbers"])
+				assert.Equal(t, tc.tpl, params["TemplateCode"])
+				assert.JSONEq(t, `{"code":"123456"}`, params["TemplateParam"])
+				w.WriteHeader(tc.status)
+				_ = json.NewEncoder(w).Encode(Result{Code: tc.code, Message: "mock message"})
+			}))
+			defer server.Close()
+			svc := NewService(server.Client(), Config{
+				AccessKeyId:     "key",
+				AccessKeySecret: "secret",
+				SignName:        "签名",
+				Endpoint:        server.URL,
+				ParamNames: map[string][]string{
+					"SMS_1": {"code"},
+				},
+			})
+			var err error
+			if tc.named != nil {
+				err = svc.SendNamed(context.Background(), tc.tpl, tc.named, "15212345678", "15312345678")
+			} else {
+				err = svc.Send(context.Background(), tc.tpl, tc.args, "15212345678", "15312345678")
+			}
+			if tc.wantErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package async
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository"
+	"dream/webook/internal/service/sms"
+	"dream/webook/internal/service/sms/ratelimit"
+	"dream/webook/pkg/circuitbreaker"
+	"errors"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+)
+
+// Service 服务商被限流或者连续失败的时候，先把短信存到数据库里面，直接告诉调用方成功了，
+// 后台再慢慢发。异步期间每隔 probeInterval 放一个请求同步发送，
+// 这个请求或者后台发送成功了，说明服务商恢复了，又切回同步发送
+type Service struct {
+	svc  sms.Service
+	repo repository.AsyncSMSRepository
+
+	// cnt 连续失败的次数，超过 threshold 就切换成异步
+	cnt       int32
+	threshold int32
+	// lastProbe 异步期间上一次同步发送的时间，毫秒时间戳
+	lastProbe     int64
+	probeInterval time.Duration
+	retryMax      int
+	// expiration 超过这个时间还没有发出去就放弃，和验证码的有效期一样
+	expiration time.Duration
+	// lease 后台抢到一条短信之后，这么久还没有结果别的实例可以再抢
+	lease time.Duration
+	// interval 没有需要发送的短信的时候，隔多久再看一次
+	interval time.Duration
+}
+
+func NewService(svc sms.Service, repo repository.AsyncSMSRepository) *Service {
+	return &Service{
+		svc:           svc,
+		repo:          repo,
+		threshold:     3,
+		probeInterval: time.Second * 10,
+		retryMax:      5,
+		expiration:    time.Minute * 10,
+		lease:         time.Minute,
+		interval:      time.Second,
+	}
+}
+
+func (s *Service) Send(ctx context.Context, tpl string, args []string, numbers ...string) error {
+	return s.send(ctx, domain.AsyncSMS{
+		Tpl:     tpl,
+		Args:    args,
+		Numbers: numbers,
+	})
+}
+
+func (s *Service) SendNamed(ctx context.Context, tpl string, args []sms.NamedArg, numbers ...string) error {
+	return s.send(ctx, domain.AsyncSMS{
+		Tpl: tpl,
+		NamedArgs: slice.Map(args, func(idx int, src sms.NamedArg) domain.SMSNamedArg {
+			return domain.SMSNamedArg{Name: src.Name, Val: src.Val}
+		}),
+		Numbers: numbers,
+	})
+}
+
+func (s *Service) send(ctx context.Context, as domain.AsyncSMS) error {
+	if s.needAsync() {
+		return s.add(ctx, as)
+	}
+	err := s.sendSync(ctx, as)
+	if err == nil {
+		atomic.StoreInt32(&s.cnt, 0)
+		return nil
+	}
+	cnt := atomic.AddInt32(&s.cnt, 1)
+	// 限流和熔断都说明服务商这会儿发不了，不用等连续失败
+	if errors.Is(err, ratelimit.ErrLimited) || errors.Is(err, circuitbreaker.ErrOpen) ||
+		cnt >= s.threshold {
+		log.Println("短信发送失败，转异步发送", err)
+		return s.add(ctx, as)
+	}
+	// 偶发的错误还是让调用方自己决定
+	return err
+}
+
+func (s *Service) needAsync() bool {
+	if atomic.LoadInt32(&s.cnt) < s.threshold {
+		return false
+	}
+	// 多个请求同时到了，只有一个能去试
+	now := time.Now().UnixMilli()
+	last := atomic.LoadInt64(&s.lastProbe)
+	if now-last < s.probeInterval.Milliseconds() {
+		return true
+	}
+	return !atomic.CompareAndSwapInt64(&s.lastProbe, last, now)
+}
+
+// sendSync 调用方用的是哪个方法，就用哪个方法发
+func (s *Service) sendSync(ctx context.Context, as domain.AsyncSMS) error {
+	if len(as.NamedArgs) > 0 {
+		return s.svc.SendNamed(ctx, as.Tpl, slice.Map(as.NamedArgs, func(idx int, src domain.SMSNamedArg) sms.NamedArg {
+			return sms.NamedArg{Name: src.Name, Val: src.Val}
+		}), as.Numbers...)
+	}
+	return s.svc.Send(ctx, as.Tpl, as.Args, as.Numbers...)
+}
+
+func (s *Service) add(ctx context.Context, as domain.AsyncSMS) error {
+	as.RetryMax = s.retryMax
+	as.Deadline = time.Now().Add(s.expiration)
+	return s.repo.Add(ctx, as)
+}
+
+// Start 启动后台发送
+func (s *Service) Start(ctx context.Context) error {
+	go func() {
+		for {
+			err := s.sendOne(ctx)
+			if err == nil {
+				continue
+			}
+			if err != repository.ErrNoMoreAsyncSMS {
+				log.Println("异步发送短信失败", err)
+			}
+			select {
+			case <-ctx.Done():
+				log.Println("异步发送短信退出")
+				return
+			case <-time.After(s.interval):
+			}
+		}
+	}()
+	return nil
+}
+
+// sendOne 抢占一条短信并发送，没有短信的时候返回 ErrNoMoreAsyncSMS
+func (s *Service) sendOne(ctx context.Context) error {
+	dbCtx, cancel := context.WithTimeout(ctx, time.Second)
+	as, err := s.repo.Preempt(dbCtx, s.lease)
+	cancel()
+	if err != nil {
+		return err
+	}
+	if time.Now().After(as.Deadline) {
+		// 验证码都过期了，发出去也没用
+		log.Println("异步短信过期了，不再发送", as.Id)
+		return s.repo.ReportFailed(ctx, as.Id)
+	}
+	ctx, cancel = context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+	err = s.sendSync(ctx, as)
+	if err == nil {
+		// 服务商恢复了
+		atomic.StoreInt32(&s.cnt, 0)
+		return s.repo.ReportSuccess(ctx, as.Id)
+	}
+	log.Println("异步发送短信失败", as.Id, as.RetryCnt, err)
+	if as.RetryCnt+1 >= as.RetryMax {
+		return s.repo.ReportFailed(ctx, as.Id)
+	}
+	return s.repo.ReportRetry(ctx, as.Id, time.Now().Add(backoff(as.RetryCnt)))
+}
+
+// backoff 指数退避，从 10 秒开始，最多 10 分钟
+func backoff(retryCnt int) time.Duration {
+	d := time.Second * 10
+	for i := 0; i < retryCnt; i++ {
+		d *= 2
+		if d >= time.Minute*10 {
+			return time.Minute * 10
+		}
+	}
+	return d
+}
@@ -0,0 +1,309 @@
+package async
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/repository"
+	repomocks "dream/webook/internal/repository/mocks"
+	"dream/webook/internal/service/sms"
+	smsmocks "dream/webook/internal/service/sms/mocks"
+	"dream/webook/internal/service/sms/ratelimit"
+	"dream/webook/pkg/circuitbreaker"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestService_Send(t *testing.T) {
+	asyncSMS := domain.AsyncSMS{
+		Tpl:      "tpl",
+		Args:     []string{"123456"},
+		Numbers:  []string{"15212345678"},
+		RetryMax: 5,
+	}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (sms.Service, repository.AsyncSMSRepository)
+		cnt  int32
+
+		wantErr error
+		wantCnt int32
+	}{
+		{
+			name: "同步发送成功",
+			mock: func(ctrl *gomock.Controller) (sms.Service, repository.AsyncSMSRepository) {
+				svc := smsmocks.NewMockService(ctrl)
+				svc.EXPECT().Send(gomock.Any(), "tpl", []string{"123456"}, "15212345678").Return(nil)
+				return svc, repomocks.NewMockAsyncSMSRepository(ctrl)
+			},
+			cnt:     2,
+			wantCnt: 0,
+		},
+		{
+			name: "被限流，转异步",
+			mock: func(ctrl *gomock.Controller) (sms.Service, repository.AsyncSMSRepository) {
+				svc := smsmocks.NewMockService(ctrl)
+				svc.EXPECT().Send(gomock.Any(), "tpl", []string{"123456"}, "15212345678").
+					Return(ratelimit.ErrLimited)
+				repo := repomocks.NewMockAsyncSMSRepository(ctrl)
+				repo.EXPECT().Add(gomock.Any(), matchAsyncSMS(asyncSMS)).Return(nil)
+				return svc, repo
+			},
+			wantCnt: 1,
+		},
+		{
+			name: "熔断了，转异步",
+			mock: func(ctrl *gomock.Controller) (sms.Service, repository.AsyncSMSRepository) {
+				svc := smsmocks.NewMockService(ctrl)
+				svc.EXPECT().Send(gomock.Any(), "tpl", []string{"123456"}, "15212345678").
+					Return(circuitbreaker.ErrOpen)
+				repo := repomocks.NewMockAsyncSMSRepository(ctrl)
+				repo.EXPECT().Add(gomock.Any(), matchAsyncSMS(asyncSMS)).Return(nil)
+				return svc, repo
+			},
+			wantCnt: 1,
+		},
+		{
+			name: "偶发的错误，直接返回",
+			mock: func(ctrl *gomock.Controller) (sms.Service, repository.AsyncSMSRepository) {
+				svc := smsmocks.NewMockService(ctrl)
+				svc.EXPECT().Send(gomock.Any(), "tpl", []string{"123456"}, "15212345678").
+					Return(errors.New("mock sms error"))
+				return svc, repomocks.NewMockAsyncSMSRepository(ctrl)
+			},
+			cnt:     1,
+			wantErr: errors.New("mock sms error"),
+			wantCnt: 2,
+		},
+		{
+			name: "连续失败达到阈值，转异步",
+			mock: func(ctrl *gomock.Controller) (sms.Service, repository.AsyncSMSRepository) {
+				svc := smsmocks.NewMockService(ctrl)
+				svc.EXPECT().Send(gomock.Any(), "tpl", []string{"123456"}, "15212345678").
+					Return(errors.New("mock sms error"))
+				repo := repomocks.NewMockAsyncSMSRepository(ctrl)
+				repo.EXPECT().Add(gomock.Any(), matchAsyncSMS(asyncSMS)).Return(nil)
+				return svc, repo
+			},
+			cnt:     2,
+			wantCnt: 3,
+		},
+		{
+			name: "已经是异步了，不再调用服务商",
+			mock: func(ctrl *gomock.Controller) (sms.Service, repository.AsyncSMSRepository) {
+				repo := repomocks.NewMockAsyncSMSRepository(ctrl)
+				repo.EXPECT().Add(gomock.Any(), matchAsyncSMS(asyncSMS)).Return(nil)
+				return smsmocks.NewMockService(ctrl), repo
+			},
+			cnt:     3,
+			wantCnt: 3,
+		},
+		{
+			name: "存数据库失败",
+			mock: func(ctrl *gomock.Controller) (sms.Service, repository.AsyncSMSRepository) {
+				repo := repomocks.NewMockAsyncSMSRepository(ctrl)
+				repo.EXPECT().Add(gomock.Any(), matchAsyncSMS(asyncSMS)).Return(errors.New("mock db error"))
+				return smsmocks.NewMockService(ctrl), repo
+			},
+			cnt:     3,
+			wantErr: errors.New("mock db error"),
+			wantCnt: 3,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			s := NewService(tc.mock(ctrl))
+			s.cnt = tc.cnt
+			// 刚探测过，这次不会同步发送
+			s.lastProbe = time.Now().UnixMilli()
+			err := s.Send(context.Background(), "tpl", []string{"123456"}, "15212345678")
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantCnt, s.cnt)
+		})
+	}
+}
+
+// TestService_Probe 异步期间隔一段时间同步发一次，成功了就切回同步
+func TestService_Probe(t *testing.T) {
+	asyncSMS := domain.AsyncSMS{
+		Tpl:      "tpl",
+		Args:     []string{"123456"},
+		Numbers:  []string{"15212345678"},
+		RetryMax: 5,
+	}
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	svc := smsmocks.NewMockService(ctrl)
+	repo := repomocks.NewMockAsyncSMSRepository(ctrl)
+	s := NewService(svc, repo)
+	s.cnt = 3
+	send := func() error {
+		return s.Send(context.Background(), "tpl", []string{"123456"}, "15212345678")
+	}
+
+	// 同步发送还是失败，这条转异步，下一条也不会再试
+	svc.EXPECT().Send(gomock.Any(), "tpl", []string{"123456"}, "15212345678").
+		Return(errors.New("mock sms error"))
+	repo.EXPECT().Add(gomock.Any(), matchAsyncSMS(asyncSMS)).Times(2).Return(nil)
+	assert.NoError(t, send())
+	assert.NoError(t, send())
+	assert.Equal(t, int32(4), s.cnt)
+
+	// 过了 probeInterval 再试一次，成功了切回同步
+	s.lastProbe = time.Now().Add(-s.probeInterval).UnixMilli()
+	svc.EXPECT().Send(gomock.Any(), "tpl", []string{"123456"}, "15212345678").Times(2).Return(nil)
+	assert.NoError(t, send())
+	assert.Equal(t, int32(0), s.cnt)
+	assert.NoError(t, send())
+}
+
+// matchAsyncSMS Deadline 是按照当前时间算的，只检查大概对不对
+func matchAsyncSMS(want domain.AsyncSMS) gomock.Matcher {
+	return gomock.Cond(func(as domain.AsyncSMS) bool {
+		deadline := as.Deadline
+		as.Deadline = time.Time{}
+		return reflect.DeepEqual(want, as) && time.Until(deadline) > time.Minute*9
+	})
+}
+
+func TestService_SendNamed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	svc := smsmocks.NewMockService(ctrl)
+	repo := repomocks.NewMockAsyncSMSRepository(ctrl)
+	args := []sms.NamedArg{{Name: "code", Val: "123456"}}
+	svc.EXPECT().SendNamed(gomock.Any(), "tpl", args, "15212345678").Return(ratelimit.ErrLimited)
+	// 转异步的时候名字也要存下来
+	repo.EXPECT().Add(gomock.Any(), matchAsyncSMS(domain.AsyncSMS{
+		Tpl:       "tpl",
+		NamedArgs: []domain.SMSNamedArg{{Name: "code", Val: "123456"}},
+		Numbers:   []string{"15212345678"},
+		RetryMax:  5,
+	})).Return(nil)
+	s := NewService(svc, repo)
+	assert.NoError(t, s.SendNamed(context.Background(), "tpl", args, "15212345678"))
+}
+
+func TestService_sendOne(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (sms.Service, repository.AsyncSMSRepository)
+		cnt  int32
+
+		wantErr error
+		wantCnt int32
+	}{
+		{
+			name: "发送成功，切回同步",
+			mock: func(ctrl *gomock.Controller) (sms.Service, repository.AsyncSMSRepository) {
+				repo := repomocks.NewMockAsyncSMSRepository(ctrl)
+				repo.EXPECT().Preempt(gomock.Any(), time.Minute).Return(domain.AsyncSMS{
+					Id: 1, Deadline: time.Now().Add(time.Minute), Tpl: "tpl", Args: []string{"123456"}, Numbers: []string{"15212345678"}, RetryMax: 5,
+				}, nil)
+				repo.EXPECT().ReportSuccess(gomock.Any(), int64(1)).Return(nil)
+				svc := smsmocks.NewMockService(ctrl)
+				svc.EXPECT().Send(gomock.Any(), "tpl", []string{"123456"}, "15212345678").Return(nil)
+				return svc, repo
+			},
+			cnt:     3,
+			wantCnt: 0,
+		},
+		{
+			name: "按照名字传的参数",
+			mock: func(ctrl *gomock.Controller) (sms.Service, repository.AsyncSMSRepository) {
+				repo := repomocks.NewMockAsyncSMSRepository(ctrl)
+				repo.EXPECT().Preempt(gomock.Any(), time.Minute).Return(domain.AsyncSMS{
+					Id: 1, Deadline: time.Now().Add(time.Minute), Tpl: "tpl", NamedArgs: []domain.SMSNamedArg{{Name: "code", Val: "123456"}},
+					Numbers: []string{"15212345678"}, RetryMax: 5,
+				}, nil)
+				repo.EXPECT().ReportSuccess(gomock.Any(), int64(1)).Return(nil)
+				svc := smsmocks.NewMockService(ctrl)
+				svc.EXPECT().SendNamed(gomock.Any(), "tpl",
+					[]sms.NamedArg{{Name: "code", Val: "123456"}}, "15212345678").Return(nil)
+				return svc, repo
+			},
+			cnt:     3,
+			wantCnt: 0,
+		},
+		{
+			name: "发送失败，稍后重试",
+			mock: func(ctrl *gomock.Controller) (sms.Service, repository.AsyncSMSRepository) {
+				repo := repomocks.NewMockAsyncSMSRepository(ctrl)
+				repo.EXPECT().Preempt(gomock.Any(), time.Minute).Return(domain.AsyncSMS{
+					Id: 1, Deadline: time.Now().Add(time.Minute), Tpl: "tpl", Args: []string{"123456"}, Numbers: []string{"15212345678"},
+					RetryCnt: 1, RetryMax: 5,
+				}, nil)
+				now := time.Now()
+				repo.EXPECT().ReportRetry(gomock.Any(), int64(1), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, id int64, next time.Time) error {
+						assert.WithinDuration(t, now.Add(time.Second*20), next, time.Second)
+						return nil
+					})
+				svc := smsmocks.NewMockService(ctrl)
+				svc.EXPECT().Send(gomock.Any(), "tpl", []string{"123456"}, "15212345678").
+					Return(errors.New("mock sms error"))
+				return svc, repo
+			},
+			cnt:     3,
+			wantCnt: 3,
+		},
+		{
+			name: "重试次数用完了",
+			mock: func(ctrl *gomock.Controller) (sms.Service, repository.AsyncSMSRepository) {
+				repo := repomocks.NewMockAsyncSMSRepository(ctrl)
+				repo.EXPECT().Preempt(gomock.Any(), time.Minute).Return(domain.AsyncSMS{
+					Id: 1, Deadline: time.Now().Add(time.Minute), Tpl: "tpl", Args: []string{"123456"}, Numbers: []string{"15212345678"},
+					RetryCnt: 4, RetryMax: 5,
+				}, nil)
+				repo.EXPECT().ReportFailed(gomock.Any(), int64(1)).Return(nil)
+				svc := smsmocks.NewMockService(ctrl)
+				svc.EXPECT().Send(gomock.Any(), "tpl", []string{"123456"}, "15212345678").
+					Return(errors.New("mock sms error"))
+				return svc, repo
+			},
+			cnt:     3,
+			wantCnt: 3,
+		},
+		{
+			name: "已经过期了，不再发送",
+			mock: func(ctrl *gomock.Controller) (sms.Service, repository.AsyncSMSRepository) {
+				repo := repomocks.NewMockAsyncSMSRepository(ctrl)
+				repo.EXPECT().Preempt(gomock.Any(), time.Minute).Return(domain.AsyncSMS{
+					Id: 1, Deadline: time.Now().Add(-time.Second), Tpl: "tpl",
+					Args: []string{"123456"}, Numbers: []string{"15212345678"}, RetryMax: 5,
+				}, nil)
+				repo.EXPECT().ReportFailed(gomock.Any(), int64(1)).Return(nil)
+				return smsmocks.NewMockService(ctrl), repo
+			},
+			cnt:     3,
+			wantCnt: 3,
+		},
+		{
+			name: "没有需要发送的短信",
+			mock: func(ctrl *gomock.Controller) (sms.Service, repository.AsyncSMSRepository) {
+				repo := repomocks.NewMockAsyncSMSRepository(ctrl)
+				repo.EXPECT().Preempt(gomock.Any(), time.Minute).
+					Return(domain.AsyncSMS{}, repository.ErrNoMoreAsyncSMS)
+				return smsmocks.NewMockService(ctrl), repo
+			},
+			wantErr: repository.ErrNoMoreAsyncSMS,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			s := NewService(tc.mock(ctrl))
+			s.cnt = tc.cnt
+			err := s.sendOne(context.Background())
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantCnt, s.cnt)
+		})
+	}
+}
@@ -15,7 +15,20 @@ type SMSService struct {
 
 // Send 发送，biz必须是线下申请的代表业务方的token
 func (s *SMSService) Send(ctx context.Context, biz string, args []string, numbers ...string) error {
+	if err := s.verify(biz); err != nil {
+		return err
+	}
+	return s.svc.Send(ctx, biz, args, numbers...)
+}
 
+func (s *SMSService) SendNamed(ctx context.Context, biz string, args []sms.NamedArg, numbers ...string) error {
+	if err := s.verify(biz); err != nil {
+		return err
+	}
+	return s.svc.SendNamed(ctx, biz, args, numbers...)
+}
+
+func (s *SMSService) verify(biz string) error {
 	var tc Claims
 
 	// 解析成功说明是对应的业务方
@@ -28,7 +41,7 @@ func (s *SMSService) Send(ctx context.Context, biz string, args []string, number
 	if !token.Valid {
 		return errors.New("token不合法")
 	}
-	return s.svc.Send(ctx, biz, args, numbers...)
+	return nil
 }
 
 type Claims struct {
@@ -0,0 +1,45 @@
+package circuitbreaker
+
+import (
+	"context"
+	"dream/webook/internal/service/sms"
+	"dream/webook/pkg/circuitbreaker"
+	"errors"
+)
+
+// CircuitBreakerSMSService 服务商一直失败的时候熔断一段时间，
+// 直接返回 circuitbreaker.ErrOpen，不用每次都等服务商超时
+type CircuitBreakerSMSService struct {
+	svc     sms.Service
+	breaker *circuitbreaker.Breaker
+}
+
+func NewCircuitBreakerSMSService(svc sms.Service, breaker *circuitbreaker.Breaker) sms.Service {
+	return &CircuitBreakerSMSService{
+		svc:     svc,
+		breaker: breaker,
+	}
+}
+
+func (c *CircuitBreakerSMSService) Send(ctx context.Context, tpl string, args []string, numbers ...string) error {
+	return c.do(ctx, func(svc sms.Service) error {
+		return svc.Send(ctx, tpl, args, numbers...)
+	})
+}
+
+func (c *CircuitBreakerSMSService) SendNamed(ctx context.Context, tpl string, args []sms.NamedArg, numbers ...string) error {
+	return c.do(ctx, func(svc sms.Service) error {
+		return svc.SendNamed(ctx, tpl, args, numbers...)
+	})
+}
+
+func (c *CircuitBreakerSMSService) do(ctx context.Context, send func(svc sms.Service) error) error {
+	done, err := c.breaker.Allow()
+	if err != nil {
+		return err
+	}
+	err = send(c.svc)
+	// 调用方自己取消的不算服务商的问题
+	done(err == nil || errors.Is(err, context.Canceled))
+	return err
+}
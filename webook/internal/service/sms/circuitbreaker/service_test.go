@@ -0,0 +1,42 @@
+package circuitbreaker
+
+import (
+	"context"
+	smsmocks "dream/webook/internal/service/sms/mocks"
+	"dream/webook/pkg/circuitbreaker"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCircuitBreakerSMSService_Send(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	svc := smsmocks.NewMockService(ctrl)
+	cb := NewCircuitBreakerSMSService(svc, circuitbreaker.NewBreaker(circuitbreaker.Config{
+		Window:           time.Minute,
+		MinRequests:      2,
+		FailureRatio:     0.5,
+		CoolDown:         time.Minute,
+		HalfOpenRequests: 1,
+	}))
+	send := func() error {
+		return cb.Send(context.Background(), "tpl", []string{"123"}, "15212345678")
+	}
+
+	// 调用方取消的不算失败
+	svc.EXPECT().Send(gomock.Any(), "tpl", []string{"123"}, "15212345678").Return(context.Canceled)
+	assert.Equal(t, context.Canceled, send())
+	svc.EXPECT().Send(gomock.Any(), "tpl", []string{"123"}, "15212345678").Return(nil)
+	assert.NoError(t, send())
+
+	svc.EXPECT().Send(gomock.Any(), "tpl", []string{"123"}, "15212345678").
+		Times(2).Return(errors.New("mock sms error"))
+	assert.Error(t, send())
+	assert.Error(t, send())
+	// 熔断之后不会再调用服务商
+	assert.Equal(t, circuitbreaker.ErrOpen, send())
+}
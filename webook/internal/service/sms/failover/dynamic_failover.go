@@ -0,0 +1,232 @@
+package failover
+
+import (
+	"context"
+	"dream/webook/internal/service/sms"
+	"errors"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DynamicConfig 判断服务商有没有出问题的参数
+type DynamicConfig struct {
+	// WindowSize 只看最近这么多次请求
+	WindowSize int
+	// Window 只看这段时间内的请求，太久之前的不算
+	Window time.Duration
+	// MinSamples 请求太少的时候不判断，偶发一次失败不能说明问题
+	MinSamples int
+	// MaxErrRate 错误率超过这个值就认为服务商出问题了
+	MaxErrRate float64
+	// Percentile 和 MaxLatency 一起用，比如 99 线超过 1 秒就认为服务商变慢了
+	Percentile float64
+	MaxLatency time.Duration
+	// Cooldown 出问题之后过多久开始探测
+	Cooldown time.Duration
+	// ProbeSuccesses 连续探测成功这么多次才恢复流量
+	ProbeSuccesses int
+}
+
+func DefaultDynamicConfig() DynamicConfig {
+	return DynamicConfig{
+		WindowSize:     100,
+		Window:         time.Minute,
+		MinSamples:     10,
+		MaxErrRate:     0.3,
+		Percentile:     0.99,
+		MaxLatency:     time.Second * 3,
+		Cooldown:       time.Second * 30,
+		ProbeSuccesses: 3,
+	}
+}
+
+// DynamicFailoverSMSService 按照顺序优先用前面的服务商，
+// 统计每个服务商最近的响应时间和错误率，变慢或者错误率太高了就切到后面的服务商；
+// 过了 Cooldown 之后拿一个真实请求去探测，探测失败了这个请求还会交给后面的服务商，
+// 连续探测成功才把流量切回来
+type DynamicFailoverSMSService struct {
+	providers []*provider
+	cfg       DynamicConfig
+}
+
+func NewDynamicFailoverSMSService(svcs []sms.Service, cfg DynamicConfig) sms.Service {
+	if cfg.WindowSize <= 0 {
+		cfg.WindowSize = DefaultDynamicConfig().WindowSize
+	}
+	providers := make([]*provider, 0, len(svcs))
+	for _, svc := range svcs {
+		providers = append(providers, &provider{
+			svc:     svc,
+			samples: make([]sample, cfg.WindowSize),
+		})
+	}
+	return &DynamicFailoverSMSService{
+		providers: providers,
+		cfg:       cfg,
+	}
+}
+
+func (d *DynamicFailoverSMSService) Send(ctx context.Context, tpl string, args []string, numbers ...string) error {
+	return d.sendAll(ctx, func(svc sms.Service) error {
+		return svc.Send(ctx, tpl, args, numbers...)
+	})
+}
+
+func (d *DynamicFailoverSMSService) SendNamed(ctx context.Context, tpl string, args []sms.NamedArg, numbers ...string) error {
+	return d.sendAll(ctx, func(svc sms.Service) error {
+		return svc.SendNamed(ctx, tpl, args, numbers...)
+	})
+}
+
+func (d *DynamicFailoverSMSService) sendAll(ctx context.Context, send func(svc sms.Service) error) error {
+	tried := false
+	for _, p := range d.providers {
+		probe, ok := p.available(time.Now(), d.cfg)
+		if !ok {
+			continue
+		}
+		tried = true
+		err := d.send(ctx, p, probe, send)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+		log.Println("短信服务商发送失败", err)
+	}
+	if !tried {
+		// 全都出问题了，死马当活马医，按照顺序都试一遍
+		for _, p := range d.providers {
+			err := d.send(ctx, p, false, send)
+			if err == nil {
+				return nil
+			}
+			if ctx.Err() != nil {
+				return err
+			}
+		}
+	}
+	return errors.New("全部服务商都失败了")
+}
+
+func (d *DynamicFailoverSMSService) send(ctx context.Context, p *provider, probe bool,
+	send func(svc sms.Service) error) error {
+	start := time.Now()
+	err := send(p.svc)
+	// 调用方自己取消的不算服务商的问题
+	if errors.Is(err, context.Canceled) {
+		p.cancelProbe(probe)
+		return err
+	}
+	p.record(time.Now(), time.Since(start), err != nil, probe, d.cfg)
+	return err
+}
+
+type sample struct {
+	at      time.Time
+	latency time.Duration
+	failed  bool
+}
+
+type provider struct {
+	svc sms.Service
+
+	mu sync.Mutex
+	// samples 环形数组，next 是下一个要写的位置
+	samples []sample
+	next    int
+	cnt     int
+
+	degraded   bool
+	degradedAt time.Time
+	// probing 同一时刻只放一个探测请求过去
+	probing bool
+	probeOK int
+}
+
+// available 返回这次是不是探测，以及能不能用
+func (p *provider) available(now time.Time, cfg DynamicConfig) (probe bool, ok bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.degraded {
+		return false, true
+	}
+	if p.probing || now.Sub(p.degradedAt) < cfg.Cooldown {
+		return false, false
+	}
+	p.probing = true
+	return true, true
+}
+
+func (p *provider) cancelProbe(probe bool) {
+	if !probe {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.probing = false
+}
+
+func (p *provider) record(now time.Time, latency time.Duration, failed bool, probe bool, cfg DynamicConfig) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if probe {
+		p.probing = false
+		if failed || latency > cfg.MaxLatency {
+			p.probeOK = 0
+			p.degradedAt = now
+			return
+		}
+		p.probeOK++
+		if p.probeOK >= cfg.ProbeSuccesses {
+			// 恢复了，之前的统计数据作废
+			p.degraded = false
+			p.next, p.cnt = 0, 0
+		}
+		return
+	}
+	if p.degraded {
+		// 切走之前发出去的请求，不影响判断
+		return
+	}
+	p.samples[p.next] = sample{at: now, latency: latency, failed: failed}
+	p.next = (p.next + 1) % len(p.samples)
+	if p.cnt < len(p.samples) {
+		p.cnt++
+	}
+	if p.unhealthy(now, cfg) {
+		log.Println("短信服务商出问题了，切换到下一个")
+		p.degraded = true
+		p.degradedAt = now
+		p.probeOK = 0
+	}
+}
+
+func (p *provider) unhealthy(now time.Time, cfg DynamicConfig) bool {
+	latencies := make([]time.Duration, 0, p.cnt)
+	failed := 0
+	for i := 0; i < p.cnt; i++ {
+		s := p.samples[i]
+		if now.Sub(s.at) > cfg.Window {
+			continue
+		}
+		latencies = append(latencies, s.latency)
+		if s.failed {
+			failed++
+		}
+	}
+	if len(latencies) < cfg.MinSamples {
+		return false
+	}
+	if float64(failed)/float64(len(latencies)) > cfg.MaxErrRate {
+		return true
+	}
+	sort.Slice(latencies, func(i, j int) bool {
+		return latencies[i] < latencies[j]
+	})
+	idx := int(float64(len(latencies)-1) * cfg.Percentile)
+	return latencies[idx] > cfg.MaxLatency
+}
@@ -0,0 +1,111 @@
+package failover
+
+import (
+	"context"
+	"dream/webook/internal/service/sms"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubService 可以随时改成失败或者变慢的服务商
+type stubService struct {
+	failed  atomic.Bool
+	latency atomic.Int64
+	cnt     atomic.Int32
+}
+
+func (s *stubService) Send(ctx context.Context, tpl string, args []string, numbers ...string) error {
+	s.cnt.Add(1)
+	time.Sleep(time.Duration(s.latency.Load()))
+	if s.failed.Load() {
+		return errors.New("mock sms error")
+	}
+	return nil
+}
+
+func (s *stubService) SendNamed(ctx context.Context, tpl string, args []sms.NamedArg, numbers ...string) error {
+	return s.Send(ctx, tpl, sms.Vals(args), numbers...)
+}
+
+func testDynamicConfig() DynamicConfig {
+	return DynamicConfig{
+		WindowSize:     10,
+		Window:         time.Minute,
+		MinSamples:     4,
+		MaxErrRate:     0.5,
+		Percentile:     0.9,
+		MaxLatency:     time.Millisecond * 20,
+		Cooldown:       time.Millisecond * 50,
+		ProbeSuccesses: 2,
+	}
+}
+
+func send(t *testing.T, svc sms.Service, cnt int) {
+	for i := 0; i < cnt; i++ {
+		require.NoError(t, svc.Send(context.Background(), "tpl", []string{"123"}, "15212345678"))
+	}
+}
+
+func TestDynamicFailoverSMSService_ErrRate(t *testing.T) {
+	primary, backup := &stubService{}, &stubService{}
+	svc := NewDynamicFailoverSMSService([]sms.Service{primary, backup}, testDynamicConfig())
+
+	send(t, svc, 4)
+	assert.Equal(t, int32(4), primary.cnt.Load())
+	assert.Equal(t, int32(0), backup.cnt.Load())
+
+	// 主服务商开始失败，每次失败都会交给备用的，错误率超过一半之后不再调用主服务商
+	primary.failed.Store(true)
+	send(t, svc, 10)
+	assert.Equal(t, int32(9), primary.cnt.Load())
+	assert.Equal(t, int32(10), backup.cnt.Load())
+
+	// 恢复了，过了 Cooldown 之后探测，连续成功两次才切回来
+	primary.failed.Store(false)
+	time.Sleep(time.Millisecond * 60)
+	send(t, svc, 2)
+	assert.Equal(t, int32(11), primary.cnt.Load())
+	assert.Equal(t, int32(10), backup.cnt.Load())
+	send(t, svc, 5)
+	assert.Equal(t, int32(16), primary.cnt.Load())
+	assert.Equal(t, int32(10), backup.cnt.Load())
+}
+
+func TestDynamicFailoverSMSService_Latency(t *testing.T) {
+	primary, backup := &stubService{}, &stubService{}
+	svc := NewDynamicFailoverSMSService([]sms.Service{primary, backup}, testDynamicConfig())
+
+	// 变慢了，但是都成功了
+	primary.latency.Store(int64(time.Millisecond * 30))
+	send(t, svc, 4)
+	send(t, svc, 3)
+	assert.Equal(t, int32(4), primary.cnt.Load())
+	assert.Equal(t, int32(3), backup.cnt.Load())
+
+	// 探测的请求虽然慢，但是发出去了；探测不算成功，继续用备用的
+	time.Sleep(time.Millisecond * 60)
+	send(t, svc, 3)
+	assert.Equal(t, int32(5), primary.cnt.Load())
+	assert.Equal(t, int32(5), backup.cnt.Load())
+}
+
+func TestDynamicFailoverSMSService_AllFailed(t *testing.T) {
+	primary, backup := &stubService{}, &stubService{}
+	primary.failed.Store(true)
+	backup.failed.Store(true)
+	svc := NewDynamicFailoverSMSService([]sms.Service{primary, backup}, testDynamicConfig())
+	for i := 0; i < 5; i++ {
+		err := svc.Send(context.Background(), "tpl", []string{"123"}, "15212345678")
+		assert.Error(t, err)
+	}
+	// 全都出问题了也要试一下
+	backup.failed.Store(false)
+	cnt := backup.cnt.Load()
+	send(t, svc, 1)
+	assert.Equal(t, cnt+1, backup.cnt.Load())
+}
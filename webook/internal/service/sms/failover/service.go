@@ -21,11 +21,23 @@ func NewFailoverSMSService(svcs []sms.Service) sms.Service {
 }
 
 func (f *FailoverSMSService) Send(ctx context.Context, tpl string, args []string, numbers ...string) error {
+	return f.send(func(svc sms.Service) error {
+		return svc.Send(ctx, tpl, args, numbers...)
+	})
+}
+
+func (f *FailoverSMSService) SendNamed(ctx context.Context, tpl string, args []sms.NamedArg, numbers ...string) error {
+	return f.send(func(svc sms.Service) error {
+		return svc.SendNamed(ctx, tpl, args, numbers...)
+	})
+}
+
+func (f *FailoverSMSService) send(send func(svc sms.Service) error) error {
 	// 可能超时，连发两条
 	// svc很多个，轮询都很慢
 	// 绝大多数请求在svcs[0]成功，负载不均衡
 	for _, svc := range f.svcs {
-		err := svc.Send(ctx, tpl, args, numbers...)
+		err := send(svc)
 		// 发送成功
 		if err == nil {
 			return nil
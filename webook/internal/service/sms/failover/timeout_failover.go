@@ -20,6 +20,19 @@ type TimeoutFailoverSMSService struct {
 
 func (t *TimeoutFailoverSMSService) Send(ctx context.Context,
 	tpl string, args []string, numbers ...string) error {
+	return t.send(func(svc sms.Service) error {
+		return svc.Send(ctx, tpl, args, numbers...)
+	})
+}
+
+func (t *TimeoutFailoverSMSService) SendNamed(ctx context.Context,
+	tpl string, args []sms.NamedArg, numbers ...string) error {
+	return t.send(func(svc sms.Service) error {
+		return svc.SendNamed(ctx, tpl, args, numbers...)
+	})
+}
+
+func (t *TimeoutFailoverSMSService) send(send func(svc sms.Service) error) error {
 	idx := atomic.LoadInt32(&t.idx)
 	cnt := atomic.LoadInt32(&t.cnt)
 	if cnt > t.threshold {
@@ -35,8 +48,7 @@ func (t *TimeoutFailoverSMSService) Send(ctx context.Context,
 		idx = atomic.LoadInt32(&t.idx)
 	}
 
-	svc := t.svcs[idx]
-	err := svc.Send(ctx, tpl, args, numbers...)
+	err := send(t.svcs[idx])
 	switch err {
 	case context.DeadlineExceeded:
 		atomic.AddInt32(&t.cnt, 1)
@@ -55,6 +67,10 @@ func (t *TimeoutFailoverSMSService) Send(ctx context.Context,
 	}
 }
 
-func NewTimeoutFailoverSMSService() sms.Service {
-	return &TimeoutFailoverSMSService{}
+// NewTimeoutFailoverSMSService 连续超时超过 threshold 次就切换到下一个服务商
+func NewTimeoutFailoverSMSService(svcs []sms.Service, threshold int32) sms.Service {
+	return &TimeoutFailoverSMSService{
+		svcs:      svcs,
+		threshold: threshold,
+	}
 }
@@ -2,6 +2,7 @@ package memory
 
 import (
 	"context"
+	"dream/webook/internal/service/sms"
 	"fmt"
 )
 
@@ -15,3 +16,8 @@ func (s *Service) Send(ctx context.Context, tplId string, args []string, numbers
 	fmt.Println(args)
 	return nil
 }
+
+func (s *Service) SendNamed(ctx context.Context, tplId string, args []sms.NamedArg, numbers ...string) error {
+	fmt.Println(args)
+	return nil
+}
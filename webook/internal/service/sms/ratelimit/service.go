@@ -7,7 +7,8 @@ import (
 	"fmt"
 )
 
-var errLimited = fmt.Errorf("触发了限流")
+// ErrLimited 触发了限流，调用方可以稍后再试
+var ErrLimited = fmt.Errorf("触发了限流")
 
 type RatelimitSMSService struct {
 	svc     sms.Service
@@ -15,6 +16,23 @@ type RatelimitSMSService struct {
 }
 
 func (r RatelimitSMSService) Send(ctx context.Context, tpl string, args []string, numbers ...string) error {
+	if err := r.limit(ctx); err != nil {
+		return err
+	}
+	// 你这里加一些代码，新特性
+	err := r.svc.Send(ctx, tpl, args, numbers...)
+	// 你在这里也可以加一些代码，新特性
+	return err
+}
+
+func (r RatelimitSMSService) SendNamed(ctx context.Context, tpl string, args []sms.NamedArg, numbers ...string) error {
+	if err := r.limit(ctx); err != nil {
+		return err
+	}
+	return r.svc.SendNamed(ctx, tpl, args, numbers...)
+}
+
+func (r RatelimitSMSService) limit(ctx context.Context) error {
 	limited, err := r.limiter.Limit(ctx, "send:tencent")
 	if err != nil {
 		// 系统错误
@@ -24,12 +42,9 @@ func (r RatelimitSMSService) Send(ctx context.Context, tpl string, args []string
 		return fmt.Errorf("短信服务判断是否限流出现问题，%w", err)
 	}
 	if limited {
-		return errLimited
+		return ErrLimited
 	}
-	// 你这里加一些代码，新特性
-	err = r.svc.Send(ctx, tpl, args, numbers...)
-	// 你在这里也可以加一些代码，新特性
-	return err
+	return nil
 }
 
 func NewRateLimitSMSServcie(svc sms.Service, limiter ratelimit.Limiter) sms.Service {
@@ -2,6 +2,7 @@ package tencent
 
 import (
 	"context"
+	webooksms "dream/webook/internal/service/sms"
 	"fmt"
 
 	"github.com/ecodeclub/ekit"
@@ -57,6 +58,11 @@ func (s *Service) Send(ctx context.Context, biz string, args []string, number ..
 	return nil
 }
 
+// SendNamed 腾讯云只支持按照位置传参数，名字直接丢掉
+func (s *Service) SendNamed(ctx context.Context, biz string, args []webooksms.NamedArg, number ...string) error {
+	return s.Send(ctx, biz, webooksms.Vals(args), number...)
+}
+
 func (s *Service) toStringPtrSlice(src []string) []*string {
 	return slice.Map(src, func(idx int, src string) *string {
 		return &src
@@ -3,8 +3,11 @@ package sms
 import "context"
 
 type Service interface {
-	Send(ctx context.Context, biz string, args []string, numbers ...string) error
-	//SendV1(ctx context.Context, tpl string, args []NamedArg, numbers ...string) error
+	// Send 按照位置传参数，比如腾讯云
+	Send(ctx context.Context, tpl string, args []string, numbers ...string) error
+	// SendNamed 按照名字传参数，比如阿里云。
+	// 只支持按照位置传参数的服务商，会按照 args 的顺序传
+	SendNamed(ctx context.Context, tpl string, args []NamedArg, numbers ...string) error
 	// 调用者需要知道实现者需要什么类型的参数，是 []string，还是 map[string]string
 	//SendV2(ctx context.Context, tpl string, args any, numbers ...string) error
 	//SendVV3(ctx context.Context, tpl string, args T, numbers ...string) error
@@ -14,3 +17,12 @@ type NamedArg struct {
 	Val  string
 	Name string
 }
+
+// Vals 去掉名字，按照顺序转成位置参数
+func Vals(args []NamedArg) []string {
+	res := make([]string, 0, len(args))
+	for _, arg := range args {
+		res = append(res, arg.Val)
+	}
+	return res
+}
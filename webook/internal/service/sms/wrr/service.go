@@ -0,0 +1,114 @@
+package wrr
+
+import (
+	"context"
+	"dream/webook/internal/service/sms"
+	"errors"
+	"log"
+	"sync"
+)
+
+// Node 一个服务商，Weight 越大分到的流量越多，一般便宜的服务商权重大
+type Node struct {
+	Svc    sms.Service
+	Weight int
+}
+
+// WeightedSMSService 平滑加权轮询，和 nginx 的算法一样：
+// 每次所有节点的 current 加上自己的有效权重，选 current 最大的，再减去有效权重的总和。
+// 发送失败有效权重减半，成功慢慢恢复到配置的权重，最少是 1，不会完全没有流量
+type WeightedSMSService struct {
+	mu    sync.Mutex
+	nodes []*node
+}
+
+type node struct {
+	svc    sms.Service
+	weight int
+	// effective 有效权重，根据发送结果调整
+	effective int
+	current   int
+}
+
+func NewWeightedSMSService(nodes []Node) sms.Service {
+	res := make([]*node, 0, len(nodes))
+	for _, n := range nodes {
+		res = append(res, &node{
+			svc:       n.Svc,
+			weight:    n.Weight,
+			effective: n.Weight,
+		})
+	}
+	return &WeightedSMSService{
+		nodes: res,
+	}
+}
+
+func (w *WeightedSMSService) Send(ctx context.Context, tpl string, args []string, numbers ...string) error {
+	return w.send(func(svc sms.Service) error {
+		return svc.Send(ctx, tpl, args, numbers...)
+	})
+}
+
+func (w *WeightedSMSService) SendNamed(ctx context.Context, tpl string, args []sms.NamedArg, numbers ...string) error {
+	return w.send(func(svc sms.Service) error {
+		return svc.SendNamed(ctx, tpl, args, numbers...)
+	})
+}
+
+func (w *WeightedSMSService) send(send func(svc sms.Service) error) error {
+	tried := make(map[*node]struct{}, len(w.nodes))
+	for len(tried) < len(w.nodes) {
+		n := w.pick(tried)
+		err := send(n.svc)
+		if errors.Is(err, context.Canceled) {
+			// 调用方自己取消的不算服务商的问题
+			return err
+		}
+		w.report(n, err)
+		if err == nil {
+			return nil
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			return err
+		}
+		log.Println("短信服务商发送失败", err)
+		tried[n] = struct{}{}
+	}
+	return errors.New("全部服务商都失败了")
+}
+
+// pick 在还没有试过的节点里面选一个
+func (w *WeightedSMSService) pick(tried map[*node]struct{}) *node {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var (
+		total int
+		best  *node
+	)
+	for _, n := range w.nodes {
+		if _, ok := tried[n]; ok {
+			continue
+		}
+		n.current += n.effective
+		total += n.effective
+		if best == nil || n.current > best.current {
+			best = n
+		}
+	}
+	best.current -= total
+	return best
+}
+
+func (w *WeightedSMSService) report(n *node, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err != nil {
+		n.effective = max(n.effective/2, 1)
+		return
+	}
+	if n.effective < n.weight {
+		// 每次恢复十分之一
+		n.effective = min(n.effective+max(n.weight/10, 1), n.weight)
+	}
+}
@@ -0,0 +1,100 @@
+package wrr
+
+import (
+	"context"
+	"dream/webook/internal/service/sms"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubService struct {
+	name   string
+	failed bool
+	got    *[]string
+}
+
+func (s *stubService) Send(ctx context.Context, tpl string, args []string, numbers ...string) error {
+	*s.got = append(*s.got, s.name)
+	if s.failed {
+		return errors.New("mock sms error")
+	}
+	return nil
+}
+
+func (s *stubService) SendNamed(ctx context.Context, tpl string, args []sms.NamedArg, numbers ...string) error {
+	return s.Send(ctx, tpl, sms.Vals(args), numbers...)
+}
+
+func TestWeightedSMSService_Smooth(t *testing.T) {
+	var got []string
+	svc := NewWeightedSMSService([]Node{
+		{Svc: &stubService{name: "a", got: &got}, Weight: 5},
+		{Svc: &stubService{name: "b", got: &got}, Weight: 1},
+		{Svc: &stubService{name: "c", got: &got}, Weight: 1},
+	})
+	for i := 0; i < 7; i++ {
+		require.NoError(t, svc.Send(context.Background(), "tpl", []string{"123"}, "15212345678"))
+	}
+	// 权重大的不会连续拿到所有流量
+	assert.Equal(t, []string{"a", "a", "b", "a", "c", "a", "a"}, got)
+}
+
+func TestWeightedSMSService_Failure(t *testing.T) {
+	var got []string
+	a := &stubService{name: "a", got: &got, failed: true}
+	b := &stubService{name: "b", got: &got}
+	svc := NewWeightedSMSService([]Node{
+		{Svc: a, Weight: 10},
+		{Svc: b, Weight: 1},
+	}).(*WeightedSMSService)
+
+	// 失败了交给下一个，并且有效权重减半
+	require.NoError(t, svc.Send(context.Background(), "tpl", []string{"123"}, "15212345678"))
+	assert.Equal(t, []string{"a", "b"}, got)
+	assert.Equal(t, 5, svc.nodes[0].effective)
+	for i := 0; i < 5; i++ {
+		require.NoError(t, svc.Send(context.Background(), "tpl", []string{"123"}, "15212345678"))
+	}
+	// 一直失败也至少留 1，不会饿死
+	assert.Equal(t, 1, svc.nodes[0].effective)
+
+	// 恢复之后每次成功加回十分之一
+	a.failed = false
+	for i := 0; i < 20; i++ {
+		require.NoError(t, svc.Send(context.Background(), "tpl", []string{"123"}, "15212345678"))
+	}
+	assert.Equal(t, 10, svc.nodes[0].effective)
+
+	// 全都失败
+	a.failed, b.failed = true, true
+	err := svc.Send(context.Background(), "tpl", []string{"123"}, "15212345678")
+	assert.Error(t, err)
+}
+
+func TestWeightedSMSService_Canceled(t *testing.T) {
+	var got []string
+	svc := NewWeightedSMSService([]Node{
+		{Svc: &cancelService{}, Weight: 1},
+		{Svc: &stubService{name: "b", got: &got}, Weight: 1},
+	}).(*WeightedSMSService)
+	err := svc.Send(context.Background(), "tpl", []string{"123"}, "15212345678")
+	assert.ErrorIs(t, err, context.Canceled)
+	// 不算服务商的问题，也不会再发给别人
+	assert.Equal(t, 1, svc.nodes[0].effective)
+	assert.Empty(t, got)
+}
+
+// cancelService 服务商的客户端一般会把 ctx 的错误包一层
+type cancelService struct{}
+
+func (c *cancelService) Send(ctx context.Context, tpl string, args []string, numbers ...string) error {
+	return fmt.Errorf("发送短信失败 %w", context.Canceled)
+}
+
+func (c *cancelService) SendNamed(ctx context.Context, tpl string, args []sms.NamedArg, numbers ...string) error {
+	return c.Send(ctx, tpl, sms.Vals(args), numbers...)
+}
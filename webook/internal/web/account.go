@@ -0,0 +1,53 @@
+package web
+
+import (
+	"dream/webook/internal/domain"
+	"dream/webook/internal/service"
+	ijwt "dream/webook/internal/web/jwt"
+	"net/http"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+)
+
+var _ handler = (*AccountHandler)(nil)
+
+// AccountHandler 作者的收入
+type AccountHandler struct {
+	svc service.AccountService
+}
+
+func NewAccountHandler(svc service.AccountService) *AccountHandler {
+	return &AccountHandler{
+		svc: svc,
+	}
+}
+
+func (h *AccountHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.POST("/balance", h.Balance)
+}
+
+// Balance 查询自己的余额，还没有收到过打赏的返回空列表
+func (h *AccountHandler) Balance(ctx *gin.Context) {
+	claims, ok := ctx.MustGet("claims").(*ijwt.UserClaims)
+	if !ok {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	accs, err := h.svc.Balance(ctx, claims.Uid)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: slice.Map(accs, func(idx int, src domain.Account) AccountVO {
+			return AccountVO{
+				Currency: src.Currency,
+				Balance:  src.Balance,
+			}
+		}),
+	})
+}
@@ -0,0 +1,536 @@
+package web
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/events/article"
+	"dream/webook/internal/service"
+	ijwt "dream/webook/internal/web/jwt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+)
+
+var _ handler = (*ArticleHandler)(nil)
+
+// ArticleHandler 帖子相关的路由，作者视角
+type ArticleHandler struct {
+	svc     service.ArticleService
+	revSvc  service.ArticleRevisionService
+	intrSvc service.InteractiveService
+	rankSvc service.RankingService
+	rwdSvc  service.RewardService
+	// producer 阅读事件
+	producer article.Producer
+	// 交互数据里面帖子的业务标识
+	biz string
+}
+
+func NewArticleHandler(svc service.ArticleService, revSvc service.ArticleRevisionService,
+	intrSvc service.InteractiveService, rankSvc service.RankingService,
+	rwdSvc service.RewardService, producer article.Producer) *ArticleHandler {
+	return &ArticleHandler{
+		svc:      svc,
+		revSvc:   revSvc,
+		intrSvc:  intrSvc,
+		rankSvc:  rankSvc,
+		rwdSvc:   rwdSvc,
+		producer: producer,
+		biz:      "article",
+	}
+}
+
+func (h *ArticleHandler) RegisterRoutes(ag *gin.RouterGroup) {
+	ag.POST("/edit", h.Edit)
+	ag.POST("/publish", h.Publish)
+	ag.POST("/list", h.List)
+	ag.GET("/detail/:id", h.Detail)
+	ag.POST("/withdraw", h.Withdraw)
+
+	// 历史版本
+	rg := ag.Group("/revisions")
+	rg.POST("/list", h.RevisionList)
+	rg.GET("/detail/:id", h.RevisionDetail)
+	rg.POST("/diff", h.RevisionDiff)
+	rg.POST("/restore", h.RevisionRestore)
+
+	// 读者视角
+	pub := ag.Group("/pub")
+	pub.GET("/hot", h.Hot)
+	pub.GET("/:id", h.PubDetail)
+	pub.POST("/like", h.Like)
+	pub.POST("/collect", h.Collect)
+	pub.POST("/uncollect", h.Uncollect)
+	pub.POST("/reward", h.Reward)
+}
+
+// Edit 保存草稿，新建或者更新
+func (h *ArticleHandler) Edit(ctx *gin.Context) {
+	var req ArticleReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	claims, ok := ctx.MustGet("claims").(*ijwt.UserClaims)
+	if !ok {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	id, err := h.svc.Save(ctx, req.toDomain(claims.Uid))
+	if err == service.ErrArticleNotAuthor {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "无权修改该帖子",
+		})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: id,
+	})
+}
+
+func (h *ArticleHandler) Publish(ctx *gin.Context) {
+	var req ArticleReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	claims, ok := ctx.MustGet("claims").(*ijwt.UserClaims)
+	if !ok {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	id, err := h.svc.Publish(ctx, req.toDomain(claims.Uid))
+	if err == service.ErrArticleNotAuthor {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "无权发表该帖子",
+		})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: id,
+	})
+}
+
+// List 作者的帖子列表
+func (h *ArticleHandler) List(ctx *gin.Context) {
+	type Req struct {
+		Offset int `json:"offset"`
+		Limit  int `json:"limit"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	// 防止一次性查太多
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 100
+	}
+	claims, ok := ctx.MustGet("claims").(*ijwt.UserClaims)
+	if !ok {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	arts, err := h.svc.List(ctx, claims.Uid, req.Offset, req.Limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		// 列表页不需要返回内容，只返回摘要
+		Data: slice.Map(arts, func(idx int, src domain.Article) ArticleVO {
+			return ArticleVO{
+				Id:       src.Id,
+				Title:    src.Title,
+				Abstract: src.Abstract(),
+				Status:   src.Status.ToUint8(),
+				Ctime:    src.Ctime.Format(time.DateTime),
+				Utime:    src.Utime.Format(time.DateTime),
+			}
+		}),
+	})
+}
+
+// Detail 作者查看自己的帖子，用于编辑
+func (h *ArticleHandler) Detail(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "参数错误",
+		})
+		return
+	}
+	claims, ok := ctx.MustGet("claims").(*ijwt.UserClaims)
+	if !ok {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	art, err := h.svc.GetById(ctx, claims.Uid, id)
+	switch err {
+	case nil:
+	case service.ErrArticleNotFound:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "帖子不存在",
+		})
+		return
+	case service.ErrArticleNotAuthor:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "无权查看该帖子",
+		})
+		return
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: ArticleVO{
+			Id:      art.Id,
+			Title:   art.Title,
+			Content: art.Content,
+			Status:  art.Status.ToUint8(),
+			Ctime:   art.Ctime.Format(time.DateTime),
+			Utime:   art.Utime.Format(time.DateTime),
+		},
+	})
+}
+
+// Withdraw 作者撤回已经发表的帖子
+func (h *ArticleHandler) Withdraw(ctx *gin.Context) {
+	type Req struct {
+		Id int64 `json:"id"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	claims, ok := ctx.MustGet("claims").(*ijwt.UserClaims)
+	if !ok {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	err := h.svc.Withdraw(ctx, claims.Uid, req.Id)
+	if err == service.ErrArticleNotAuthor {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "无权撤回该帖子",
+		})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "OK",
+	})
+}
+
+// PubDetail 读者查看已经发表的帖子
+func (h *ArticleHandler) PubDetail(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "参数错误",
+		})
+		return
+	}
+	claims, ok := ctx.MustGet("claims").(*ijwt.UserClaims)
+	if !ok {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	art, err := h.svc.GetPublishedById(ctx, id)
+	if err == service.ErrArticleNotFound {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "帖子不存在",
+		})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		return
+	}
+
+	// 阅读数不影响读者看帖子，发个事件异步增加
+	er := h.producer.ProduceReadEvent(ctx, article.ReadEvent{
+		Aid: art.Id,
+		Uid: claims.Uid,
+	})
+	if er != nil {
+		log.Println("发送阅读事件失败", art.Id, er)
+	}
+
+	// 交互数据查不到，帖子还是要给读者看的
+	intr, err := h.intrSvc.Get(ctx, h.biz, art.Id, claims.Uid)
+	if err != nil {
+		log.Println("查询交互数据失败", art.Id, err)
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: ArticleVO{
+			Id:         art.Id,
+			Title:      art.Title,
+			Content:    art.Content,
+			Status:     art.Status.ToUint8(),
+			AuthorId:   art.Author.Id,
+			AuthorName: art.Author.Name,
+			ReadCnt:    intr.ReadCnt,
+			LikeCnt:    intr.LikeCnt,
+			CollectCnt: intr.CollectCnt,
+			Liked:      intr.Liked,
+			Collected:  intr.Collected,
+			Ctime:      art.Ctime.Format(time.DateTime),
+			Utime:      art.Utime.Format(time.DateTime),
+		},
+	})
+}
+
+// Like 点赞或者取消点赞
+func (h *ArticleHandler) Like(ctx *gin.Context) {
+	type Req struct {
+		Id int64 `json:"id"`
+		// true 是点赞，false 是取消点赞
+		Like bool `json:"like"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	claims, ok := ctx.MustGet("claims").(*ijwt.UserClaims)
+	if !ok {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	var err error
+	if req.Like {
+		err = h.intrSvc.Like(ctx, h.biz, req.Id, claims.Uid)
+	} else {
+		err = h.intrSvc.CancelLike(ctx, h.biz, req.Id, claims.Uid)
+	}
+	if err == service.ErrArticleNotFound {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "帖子不存在",
+		})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		return
+	}
+	go h.refreshRanking(context.Background(), req.Id)
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "OK",
+	})
+}
+
+// Collect 收藏
+func (h *ArticleHandler) Collect(ctx *gin.Context) {
+	type Req struct {
+		Id int64 `json:"id"`
+		// 收藏夹 id
+		Cid int64 `json:"cid"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	claims, ok := ctx.MustGet("claims").(*ijwt.UserClaims)
+	if !ok {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	err := h.intrSvc.Collect(ctx, h.biz, req.Id, req.Cid, claims.Uid)
+	if err == service.ErrArticleNotFound {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "帖子不存在",
+		})
+		return
+	}
+	if err == service.ErrCollectionNotOwner {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "收藏夹不存在或者无权操作",
+		})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		return
+	}
+	go h.refreshRanking(context.Background(), req.Id)
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "OK",
+	})
+}
+
+// Uncollect 取消收藏
+func (h *ArticleHandler) Uncollect(ctx *gin.Context) {
+	type Req struct {
+		Id int64 `json:"id"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	claims, ok := ctx.MustGet("claims").(*ijwt.UserClaims)
+	if !ok {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	err := h.intrSvc.CancelCollect(ctx, h.biz, req.Id, claims.Uid)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		return
+	}
+	go h.refreshRanking(context.Background(), req.Id)
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "OK",
+	})
+}
+
+// Hot 热榜
+func (h *ArticleHandler) Hot(ctx *gin.Context) {
+	arts, err := h.rankSvc.TopN(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: slice.Map(arts, func(idx int, src domain.HotArticle) HotArticleVO {
+			return HotArticleVO{
+				Id:       src.Article.Id,
+				Title:    src.Article.Title,
+				Abstract: src.Article.Abstract(),
+				AuthorId: src.Article.Author.Id,
+				Score:    src.Score,
+				Ctime:    src.Article.Ctime.Format(time.DateTime),
+			}
+		}),
+	})
+}
+
+// Reward 打赏帖子的作者，返回支付二维码
+func (h *ArticleHandler) Reward(ctx *gin.Context) {
+	type Req struct {
+		Id int64 `json:"id"`
+		// 金额，单位是分
+		Amt int64 `json:"amt"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	if req.Amt <= 0 {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "打赏金额不对",
+		})
+		return
+	}
+	claims, ok := ctx.MustGet("claims").(*ijwt.UserClaims)
+	if !ok {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	art, err := h.svc.GetPublishedById(ctx, req.Id)
+	if err == service.ErrArticleNotFound {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "帖子不存在",
+		})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		return
+	}
+	cu, err := h.rwdSvc.PreReward(ctx, domain.Reward{
+		Uid: claims.Uid,
+		Target: domain.Target{
+			Biz:     h.biz,
+			BizId:   art.Id,
+			BizName: art.Title,
+			Uid:     art.Author.Id,
+		},
+		Amt: req.Amt,
+	})
+	if err == service.ErrRewardSelf {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "不能打赏自己",
+		})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: CodeURLVO{
+			Rid:     cu.Rid,
+			CodeURL: cu.URL,
+		},
+	})
+}
+
+// refreshRanking 交互数据变了，更新热榜，失败了等定时任务全量重算
+func (h *ArticleHandler) refreshRanking(ctx context.Context, id int64) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	if err := h.rankSvc.Refresh(ctx, id); err != nil {
+		log.Println("更新热榜失败", id, err)
+	}
+}
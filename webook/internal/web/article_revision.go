@@ -0,0 +1,166 @@
+package web
+
+import (
+	"dream/webook/internal/domain"
+	"dream/webook/internal/service"
+	ijwt "dream/webook/internal/web/jwt"
+	"dream/webook/pkg/diff"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+)
+
+// RevisionList 帖子的历史版本列表，不返回内容
+func (h *ArticleHandler) RevisionList(ctx *gin.Context) {
+	type Req struct {
+		Id     int64 `json:"id"`
+		Offset int   `json:"offset"`
+		Limit  int   `json:"limit"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 100
+	}
+	claims, ok := ctx.MustGet("claims").(*ijwt.UserClaims)
+	if !ok {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	revs, err := h.revSvc.List(ctx, claims.Uid, req.Id, req.Offset, req.Limit)
+	if !h.handleRevisionErr(ctx, err) {
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: slice.Map(revs, func(idx int, src domain.ArticleRevision) RevisionVO {
+			return newRevisionVO(src, false)
+		}),
+	})
+}
+
+func (h *ArticleHandler) RevisionDetail(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "参数错误",
+		})
+		return
+	}
+	claims, ok := ctx.MustGet("claims").(*ijwt.UserClaims)
+	if !ok {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	rev, err := h.revSvc.Get(ctx, claims.Uid, id)
+	if !h.handleRevisionErr(ctx, err) {
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: newRevisionVO(rev, true),
+	})
+}
+
+// RevisionDiff 比较两个版本，from 是旧版本，to 是新版本
+func (h *ArticleHandler) RevisionDiff(ctx *gin.Context) {
+	type Req struct {
+		From int64 `json:"from"`
+		To   int64 `json:"to"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	claims, ok := ctx.MustGet("claims").(*ijwt.UserClaims)
+	if !ok {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	lines, err := h.revSvc.Diff(ctx, claims.Uid, req.From, req.To)
+	if !h.handleRevisionErr(ctx, err) {
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: slice.Map(lines, func(idx int, src diff.Line) DiffLineVO {
+			return DiffLineVO{
+				Op:   src.Op.String(),
+				Text: src.Text,
+			}
+		}),
+	})
+}
+
+// RevisionRestore 用历史版本覆盖当前的草稿
+func (h *ArticleHandler) RevisionRestore(ctx *gin.Context) {
+	type Req struct {
+		// 帖子 id
+		Id int64 `json:"id"`
+		// 版本 id
+		Rid int64 `json:"rid"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	claims, ok := ctx.MustGet("claims").(*ijwt.UserClaims)
+	if !ok {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	err := h.revSvc.Restore(ctx, claims.Uid, req.Id, req.Rid)
+	if !h.handleRevisionErr(ctx, err) {
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: req.Id,
+	})
+}
+
+// handleRevisionErr 处理历史版本相关的错误，返回 true 表示没有错误，可以继续
+func (h *ArticleHandler) handleRevisionErr(ctx *gin.Context, err error) bool {
+	switch err {
+	case nil:
+		return true
+	case service.ErrArticleNotFound, service.ErrRevisionNotFound:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "版本不存在",
+		})
+	case service.ErrArticleNotAuthor:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "无权操作该帖子",
+		})
+	case service.ErrRevisionMismatch:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "版本不属于该帖子",
+		})
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+	}
+	return false
+}
+
+func newRevisionVO(rev domain.ArticleRevision, withContent bool) RevisionVO {
+	vo := RevisionVO{
+		Id:        rev.Id,
+		ArticleId: rev.ArticleId,
+		AuthorId:  rev.Author.Id,
+		Title:     rev.Title,
+		Hash:      rev.Hash,
+		Ctime:     rev.Ctime.Format(time.DateTime),
+	}
+	if withContent {
+		vo.Content = rev.Content
+	}
+	return vo
+}
@@ -0,0 +1,225 @@
+package web
+
+import (
+	"bytes"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/service"
+	svcmocks "dream/webook/internal/service/mocks"
+	ijwt "dream/webook/internal/web/jwt"
+	"dream/webook/pkg/diff"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestArticleHandler_RevisionList(t *testing.T) {
+	ctime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local)
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) service.ArticleRevisionService
+
+		reqBody string
+
+		wantRes Result
+	}{
+		{
+			name: "查看成功，不返回内容",
+			mock: func(ctrl *gomock.Controller) service.ArticleRevisionService {
+				svc := svcmocks.NewMockArticleRevisionService(ctrl)
+				svc.EXPECT().List(gomock.Any(), int64(123), int64(1), 0, 100).Return([]domain.ArticleRevision{
+					{Id: 2, ArticleId: 1, Author: domain.Author{Id: 123}, Title: "标题",
+						Content: "内容", Hash: "abc", Ctime: ctime},
+				}, nil)
+				return svc
+			},
+			reqBody: `{"id":1}`,
+			wantRes: Result{
+				Data: []any{
+					map[string]any{
+						"id":        float64(2),
+						"articleId": float64(1),
+						"authorId":  float64(123),
+						"title":     "标题",
+						"hash":      "abc",
+						"ctime":     "2024-01-02 03:04:05",
+					},
+				},
+			},
+		},
+		{
+			name: "别人的帖子",
+			mock: func(ctrl *gomock.Controller) service.ArticleRevisionService {
+				svc := svcmocks.NewMockArticleRevisionService(ctrl)
+				svc.EXPECT().List(gomock.Any(), int64(123), int64(1), 0, 100).
+					Return(nil, service.ErrArticleNotAuthor)
+				return svc
+			},
+			reqBody: `{"id":1}`,
+			wantRes: Result{Code: 4, Msg: "无权操作该帖子"},
+		},
+		{
+			name: "查询失败",
+			mock: func(ctrl *gomock.Controller) service.ArticleRevisionService {
+				svc := svcmocks.NewMockArticleRevisionService(ctrl)
+				svc.EXPECT().List(gomock.Any(), int64(123), int64(1), 10, 20).
+					Return(nil, errors.New("mock error"))
+				return svc
+			},
+			reqBody: `{"id":1,"offset":10,"limit":20}`,
+			wantRes: Result{Code: 5, Msg: "系统错误"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res := doRevisionRequest(t, tc.mock, "/articles/revisions/list", tc.reqBody)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
+
+func TestArticleHandler_RevisionDiff(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) service.ArticleRevisionService
+
+		wantRes Result
+	}{
+		{
+			name: "比较成功",
+			mock: func(ctrl *gomock.Controller) service.ArticleRevisionService {
+				svc := svcmocks.NewMockArticleRevisionService(ctrl)
+				svc.EXPECT().Diff(gomock.Any(), int64(123), int64(1), int64(2)).Return([]diff.Line{
+					{Op: diff.OpEqual, Text: "a"},
+					{Op: diff.OpDelete, Text: "b"},
+					{Op: diff.OpInsert, Text: "c"},
+				}, nil)
+				return svc
+			},
+			wantRes: Result{
+				Data: []any{
+					map[string]any{"op": " ", "text": "a"},
+					map[string]any{"op": "-", "text": "b"},
+					map[string]any{"op": "+", "text": "c"},
+				},
+			},
+		},
+		{
+			name: "别人的版本",
+			mock: func(ctrl *gomock.Controller) service.ArticleRevisionService {
+				svc := svcmocks.NewMockArticleRevisionService(ctrl)
+				svc.EXPECT().Diff(gomock.Any(), int64(123), int64(1), int64(2)).
+					Return(nil, service.ErrArticleNotAuthor)
+				return svc
+			},
+			wantRes: Result{Code: 4, Msg: "无权操作该帖子"},
+		},
+		{
+			name: "两个版本不是同一个帖子的",
+			mock: func(ctrl *gomock.Controller) service.ArticleRevisionService {
+				svc := svcmocks.NewMockArticleRevisionService(ctrl)
+				svc.EXPECT().Diff(gomock.Any(), int64(123), int64(1), int64(2)).
+					Return(nil, service.ErrRevisionMismatch)
+				return svc
+			},
+			wantRes: Result{Code: 4, Msg: "版本不属于该帖子"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res := doRevisionRequest(t, tc.mock, "/articles/revisions/diff", `{"from":1,"to":2}`)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
+
+func TestArticleHandler_RevisionRestore(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) service.ArticleRevisionService
+
+		wantRes Result
+	}{
+		{
+			name: "恢复成功",
+			mock: func(ctrl *gomock.Controller) service.ArticleRevisionService {
+				svc := svcmocks.NewMockArticleRevisionService(ctrl)
+				svc.EXPECT().Restore(gomock.Any(), int64(123), int64(1), int64(2)).Return(nil)
+				return svc
+			},
+			wantRes: Result{Data: float64(1)},
+		},
+		{
+			name: "别人的帖子",
+			mock: func(ctrl *gomock.Controller) service.ArticleRevisionService {
+				svc := svcmocks.NewMockArticleRevisionService(ctrl)
+				svc.EXPECT().Restore(gomock.Any(), int64(123), int64(1), int64(2)).
+					Return(service.ErrArticleNotAuthor)
+				return svc
+			},
+			wantRes: Result{Code: 4, Msg: "无权操作该帖子"},
+		},
+		{
+			name: "版本属于另一个帖子",
+			mock: func(ctrl *gomock.Controller) service.ArticleRevisionService {
+				svc := svcmocks.NewMockArticleRevisionService(ctrl)
+				svc.EXPECT().Restore(gomock.Any(), int64(123), int64(1), int64(2)).
+					Return(service.ErrRevisionMismatch)
+				return svc
+			},
+			wantRes: Result{Code: 4, Msg: "版本不属于该帖子"},
+		},
+		{
+			name: "版本不存在",
+			mock: func(ctrl *gomock.Controller) service.ArticleRevisionService {
+				svc := svcmocks.NewMockArticleRevisionService(ctrl)
+				svc.EXPECT().Restore(gomock.Any(), int64(123), int64(1), int64(2)).
+					Return(service.ErrRevisionNotFound)
+				return svc
+			},
+			wantRes: Result{Code: 4, Msg: "版本不存在"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res := doRevisionRequest(t, tc.mock, "/articles/revisions/restore", `{"id":1,"rid":2}`)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
+
+// doRevisionRequest 以用户 123 的身份调用历史版本的接口
+func doRevisionRequest(t *testing.T, mock func(ctrl *gomock.Controller) service.ArticleRevisionService,
+	path, reqBody string) Result {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := gin.Default()
+	server.Use(func(ctx *gin.Context) {
+		ctx.Set("claims", &ijwt.UserClaims{
+			Uid: 123,
+		})
+	})
+	h := NewArticleHandler(nil, mock(ctrl), nil, nil, nil, nil)
+	h.RegisterRoutes(server.Group("/articles"))
+
+	req, err := http.NewRequest(http.MethodPost, path, bytes.NewBuffer([]byte(reqBody)))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+
+	server.ServeHTTP(resp, req)
+
+	require.Equal(t, http.StatusOK, resp.Code)
+	var res Result
+	err = json.NewDecoder(resp.Body).Decode(&res)
+	require.NoError(t, err)
+	return res
+}
@@ -0,0 +1,274 @@
+package web
+
+import (
+	"bytes"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/service"
+	svcmocks "dream/webook/internal/service/mocks"
+	ijwt "dream/webook/internal/web/jwt"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestArticleHandler_Publish(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		mock func(ctrl *gomock.Controller) service.ArticleService
+
+		reqBody string
+
+		wantCode int
+		wantRes  Result
+	}{
+		{
+			name: "新建并发表成功",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				svc := svcmocks.NewMockArticleService(ctrl)
+				svc.EXPECT().Publish(gomock.Any(), domain.Article{
+					Title:   "我的标题",
+					Content: "我的内容",
+					Author: domain.Author{
+						Id: 123,
+					},
+				}).Return(int64(1), nil)
+				return svc
+			},
+			reqBody:  `{"title":"我的标题","content":"我的内容"}`,
+			wantCode: http.StatusOK,
+			wantRes: Result{
+				// json 反序列化数字默认是 float64
+				Data: float64(1),
+			},
+		},
+		{
+			name: "修改别人的帖子",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				svc := svcmocks.NewMockArticleService(ctrl)
+				svc.EXPECT().Publish(gomock.Any(), domain.Article{
+					Id:      2,
+					Title:   "我的标题",
+					Content: "我的内容",
+					Author: domain.Author{
+						Id: 123,
+					},
+				}).Return(int64(0), service.ErrArticleNotAuthor)
+				return svc
+			},
+			reqBody:  `{"id":2,"title":"我的标题","content":"我的内容"}`,
+			wantCode: http.StatusOK,
+			wantRes: Result{
+				Code: 4,
+				Msg:  "无权发表该帖子",
+			},
+		},
+		{
+			name: "发表失败",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				svc := svcmocks.NewMockArticleService(ctrl)
+				svc.EXPECT().Publish(gomock.Any(), gomock.Any()).
+					Return(int64(0), errors.New("mock error"))
+				return svc
+			},
+			reqBody:  `{"title":"我的标题","content":"我的内容"}`,
+			wantCode: http.StatusOK,
+			wantRes: Result{
+				Code: 5,
+				Msg:  "系统错误",
+			},
+		},
+		{
+			name: "bind失败",
+			mock: func(ctrl *gomock.Controller) service.ArticleService {
+				return svcmocks.NewMockArticleService(ctrl)
+			},
+			reqBody:  `{"title":"我的标题","content":"我的内容",}`,
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			server := gin.Default()
+			// 模拟登录态
+			server.Use(func(ctx *gin.Context) {
+				ctx.Set("claims", &ijwt.UserClaims{
+					Uid: 123,
+				})
+			})
+			h := NewArticleHandler(tc.mock(ctrl), nil, nil, nil, nil, nil)
+			h.RegisterRoutes(server.Group("/articles"))
+
+			req, err := http.NewRequest(http.MethodPost, "/articles/publish", bytes.NewBuffer([]byte(tc.reqBody)))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+
+			server.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.wantCode, resp.Code)
+			if resp.Code != http.StatusOK {
+				return
+			}
+			var res Result
+			err = json.NewDecoder(resp.Body).Decode(&res)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
+
+func TestArticleHandler_Like(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		mock func(ctrl *gomock.Controller) service.InteractiveService
+
+		reqBody string
+
+		wantRes Result
+	}{
+		{
+			name: "点赞成功",
+			mock: func(ctrl *gomock.Controller) service.InteractiveService {
+				svc := svcmocks.NewMockInteractiveService(ctrl)
+				svc.EXPECT().Like(gomock.Any(), "article", int64(1), int64(123)).Return(nil)
+				return svc
+			},
+			reqBody: `{"id":1,"like":true}`,
+			wantRes: Result{Msg: "OK"},
+		},
+		{
+			name: "取消点赞",
+			mock: func(ctrl *gomock.Controller) service.InteractiveService {
+				svc := svcmocks.NewMockInteractiveService(ctrl)
+				svc.EXPECT().CancelLike(gomock.Any(), "article", int64(1), int64(123)).Return(nil)
+				return svc
+			},
+			reqBody: `{"id":1,"like":false}`,
+			wantRes: Result{Msg: "OK"},
+		},
+		{
+			name: "帖子不存在",
+			mock: func(ctrl *gomock.Controller) service.InteractiveService {
+				svc := svcmocks.NewMockInteractiveService(ctrl)
+				svc.EXPECT().Like(gomock.Any(), "article", int64(1), int64(123)).
+					Return(service.ErrArticleNotFound)
+				return svc
+			},
+			reqBody: `{"id":1,"like":true}`,
+			wantRes: Result{Code: 4, Msg: "帖子不存在"},
+		},
+		{
+			name: "点赞失败",
+			mock: func(ctrl *gomock.Controller) service.InteractiveService {
+				svc := svcmocks.NewMockInteractiveService(ctrl)
+				svc.EXPECT().Like(gomock.Any(), "article", int64(1), int64(123)).
+					Return(errors.New("mock db error"))
+				return svc
+			},
+			reqBody: `{"id":1,"like":true}`,
+			wantRes: Result{Code: 5, Msg: "系统错误"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			res := doArticleRequest(t, ctrl, tc.mock(ctrl), "/articles/pub/like", tc.reqBody)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
+
+func TestArticleHandler_Collect(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		mock func(ctrl *gomock.Controller) service.InteractiveService
+
+		reqBody string
+
+		wantRes Result
+	}{
+		{
+			name: "收藏成功",
+			mock: func(ctrl *gomock.Controller) service.InteractiveService {
+				svc := svcmocks.NewMockInteractiveService(ctrl)
+				svc.EXPECT().Collect(gomock.Any(), "article", int64(1), int64(10), int64(123)).Return(nil)
+				return svc
+			},
+			reqBody: `{"id":1,"cid":10}`,
+			wantRes: Result{Msg: "OK"},
+		},
+		{
+			name: "帖子不存在",
+			mock: func(ctrl *gomock.Controller) service.InteractiveService {
+				svc := svcmocks.NewMockInteractiveService(ctrl)
+				svc.EXPECT().Collect(gomock.Any(), "article", int64(1), int64(10), int64(123)).
+					Return(service.ErrArticleNotFound)
+				return svc
+			},
+			reqBody: `{"id":1,"cid":10}`,
+			wantRes: Result{Code: 4, Msg: "帖子不存在"},
+		},
+		{
+			name: "别人的收藏夹",
+			mock: func(ctrl *gomock.Controller) service.InteractiveService {
+				svc := svcmocks.NewMockInteractiveService(ctrl)
+				svc.EXPECT().Collect(gomock.Any(), "article", int64(1), int64(10), int64(123)).
+					Return(service.ErrCollectionNotOwner)
+				return svc
+			},
+			reqBody: `{"id":1,"cid":10}`,
+			wantRes: Result{Code: 4, Msg: "收藏夹不存在或者无权操作"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			res := doArticleRequest(t, ctrl, tc.mock(ctrl), "/articles/pub/collect", tc.reqBody)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
+
+// doArticleRequest 以用户 123 的身份请求交互相关的接口
+func doArticleRequest(t *testing.T, ctrl *gomock.Controller, intrSvc service.InteractiveService,
+	path, body string) Result {
+	// 成功之后会异步刷新热榜
+	rankSvc := svcmocks.NewMockRankingService(ctrl)
+	rankSvc.EXPECT().Refresh(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	server := gin.Default()
+	server.Use(func(ctx *gin.Context) {
+		ctx.Set("claims", &ijwt.UserClaims{
+			Uid: 123,
+		})
+	})
+	h := NewArticleHandler(nil, nil, intrSvc, rankSvc, nil, nil)
+	h.RegisterRoutes(server.Group("/articles"))
+
+	req, err := http.NewRequest(http.MethodPost, path, bytes.NewBuffer([]byte(body)))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	server.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	var res Result
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&res))
+	return res
+}
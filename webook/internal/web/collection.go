@@ -0,0 +1,263 @@
+package web
+
+import (
+	"dream/webook/internal/domain"
+	"dream/webook/internal/service"
+	ijwt "dream/webook/internal/web/jwt"
+	"net/http"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+)
+
+var _ handler = (*CollectionHandler)(nil)
+
+// CollectionHandler 收藏夹相关的路由
+type CollectionHandler struct {
+	svc service.CollectionService
+}
+
+func NewCollectionHandler(svc service.CollectionService) *CollectionHandler {
+	return &CollectionHandler{
+		svc: svc,
+	}
+}
+
+func (h *CollectionHandler) RegisterRoutes(cg *gin.RouterGroup) {
+	cg.POST("/create", h.Create)
+	cg.POST("/edit", h.Edit)
+	cg.POST("/delete", h.Delete)
+	cg.POST("/list", h.List)
+	cg.POST("/items", h.Items)
+	cg.POST("/move", h.Move)
+}
+
+func (h *CollectionHandler) Create(ctx *gin.Context) {
+	type Req struct {
+		Name    string `json:"name"`
+		Private bool   `json:"private"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	if req.Name == "" {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "收藏夹名字不能为空",
+		})
+		return
+	}
+	claims, ok := ctx.MustGet("claims").(*ijwt.UserClaims)
+	if !ok {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	id, err := h.svc.Create(ctx, domain.Collection{
+		Uid:     claims.Uid,
+		Name:    req.Name,
+		Private: req.Private,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: id,
+	})
+}
+
+// Edit 修改名字和是否私密
+func (h *CollectionHandler) Edit(ctx *gin.Context) {
+	type Req struct {
+		Id      int64  `json:"id"`
+		Name    string `json:"name"`
+		Private bool   `json:"private"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	if req.Name == "" {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "收藏夹名字不能为空",
+		})
+		return
+	}
+	claims, ok := ctx.MustGet("claims").(*ijwt.UserClaims)
+	if !ok {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	err := h.svc.Update(ctx, domain.Collection{
+		Id:      req.Id,
+		Uid:     claims.Uid,
+		Name:    req.Name,
+		Private: req.Private,
+	})
+	h.handleErr(ctx, err)
+}
+
+func (h *CollectionHandler) Delete(ctx *gin.Context) {
+	type Req struct {
+		Id int64 `json:"id"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	claims, ok := ctx.MustGet("claims").(*ijwt.UserClaims)
+	if !ok {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	err := h.svc.Delete(ctx, claims.Uid, req.Id)
+	h.handleErr(ctx, err)
+}
+
+// List 用户的收藏夹列表，uid 为 0 表示查看自己的
+func (h *CollectionHandler) List(ctx *gin.Context) {
+	type Req struct {
+		Uid int64 `json:"uid"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	claims, ok := ctx.MustGet("claims").(*ijwt.UserClaims)
+	if !ok {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	if req.Uid == 0 {
+		req.Uid = claims.Uid
+	}
+	cs, err := h.svc.List(ctx, claims.Uid, req.Uid)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: slice.Map(cs, func(idx int, src domain.Collection) CollectionVO {
+			return CollectionVO{
+				Id:      src.Id,
+				Uid:     src.Uid,
+				Name:    src.Name,
+				Private: src.Private,
+				Default: src.Default,
+				Ctime:   src.Ctime.Format(time.DateTime),
+				Utime:   src.Utime.Format(time.DateTime),
+			}
+		}),
+	})
+}
+
+// Items 收藏夹里面的帖子，带上交互数据
+func (h *CollectionHandler) Items(ctx *gin.Context) {
+	type Req struct {
+		Id     int64 `json:"id"`
+		Offset int   `json:"offset"`
+		Limit  int   `json:"limit"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 100
+	}
+	claims, ok := ctx.MustGet("claims").(*ijwt.UserClaims)
+	if !ok {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	items, err := h.svc.Items(ctx, claims.Uid, req.Id, req.Offset, req.Limit)
+	switch err {
+	case nil:
+	case service.ErrCollectionNotFound:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "收藏夹不存在",
+		})
+		return
+	case service.ErrCollectionPrivate:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "收藏夹是私密的",
+		})
+		return
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: slice.Map(items, func(idx int, src domain.CollectionItem) CollectionItemVO {
+			return CollectionItemVO{
+				Cid: src.Cid,
+				Article: ArticleVO{
+					Id:         src.Article.Id,
+					Title:      src.Article.Title,
+					Abstract:   src.Article.Abstract(),
+					Status:     src.Article.Status.ToUint8(),
+					AuthorId:   src.Article.Author.Id,
+					ReadCnt:    src.Interactive.ReadCnt,
+					LikeCnt:    src.Interactive.LikeCnt,
+					CollectCnt: src.Interactive.CollectCnt,
+					Ctime:      src.Article.Ctime.Format(time.DateTime),
+					Utime:      src.Article.Utime.Format(time.DateTime),
+				},
+				Ctime: src.Ctime.Format(time.DateTime),
+			}
+		}),
+	})
+}
+
+// Move 把帖子挪到另一个收藏夹
+func (h *CollectionHandler) Move(ctx *gin.Context) {
+	type Req struct {
+		ArticleId int64 `json:"articleId"`
+		From      int64 `json:"from"`
+		To        int64 `json:"to"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	claims, ok := ctx.MustGet("claims").(*ijwt.UserClaims)
+	if !ok {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	err := h.svc.Move(ctx, claims.Uid, req.ArticleId, req.From, req.To)
+	h.handleErr(ctx, err)
+}
+
+func (h *CollectionHandler) handleErr(ctx *gin.Context, err error) {
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{
+			Msg: "OK",
+		})
+	case service.ErrCollectionNotOwner:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "收藏夹不存在或者无权操作",
+		})
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package web
+
+import (
+	"dream/webook/internal/domain"
+	"dream/webook/internal/service"
+	ijwt "dream/webook/internal/web/jwt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+)
+
+var _ handler = (*CommentHandler)(nil)
+
+// CommentHandler 帖子下面的评论
+type CommentHandler struct {
+	svc service.CommentService
+	biz string
+}
+
+func NewCommentHandler(svc service.CommentService) *CommentHandler {
+	return &CommentHandler{
+		svc: svc,
+		biz: "article",
+	}
+}
+
+func (h *CommentHandler) RegisterRoutes(cg *gin.RouterGroup) {
+	cg.POST("/create", h.Create)
+	cg.POST("/list", h.List)
+	cg.POST("/replies", h.Replies)
+	cg.POST("/delete", h.Delete)
+}
+
+// Create 评论帖子，或者回复别人的评论
+func (h *CommentHandler) Create(ctx *gin.Context) {
+	type Req struct {
+		ArticleId int64  `json:"articleId"`
+		ParentId  int64  `json:"parentId"`
+		Content   string `json:"content"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	req.Content = strings.TrimSpace(req.Content)
+	if req.Content == "" || utf8.RuneCountInString(req.Content) > 1000 {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "评论内容不能为空，也不能超过 1000 个字",
+		})
+		return
+	}
+	claims, ok := ctx.MustGet("claims").(*ijwt.UserClaims)
+	if !ok {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	id, err := h.svc.Create(ctx, domain.Comment{
+		Uid:      claims.Uid,
+		Biz:      h.biz,
+		BizId:    req.ArticleId,
+		ParentId: req.ParentId,
+		Content:  req.Content,
+	})
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{
+			Data: id,
+		})
+	case service.ErrArticleNotFound:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "帖子不存在",
+		})
+	case service.ErrCommentParentInvalid:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "回复的评论不存在",
+		})
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+	}
+}
+
+// List 帖子的根评论，最新的在前面，回复要单独展开
+func (h *CommentHandler) List(ctx *gin.Context) {
+	type Req struct {
+		ArticleId int64 `json:"articleId"`
+		// MaxId 上一页最后一条评论的 id，第一页不传
+		MaxId int64 `json:"maxId"`
+		Limit int   `json:"limit"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 100
+	}
+	cs, err := h.svc.ListRoots(ctx, h.biz, req.ArticleId, req.MaxId, req.Limit)
+	h.renderComments(ctx, cs, err)
+}
+
+// Replies 展开根评论下面的回复，按照时间顺序
+func (h *CommentHandler) Replies(ctx *gin.Context) {
+	type Req struct {
+		RootId int64 `json:"rootId"`
+		// MinId 上一页最后一条回复的 id，第一页不传
+		MinId int64 `json:"minId"`
+		Limit int   `json:"limit"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 100
+	}
+	cs, err := h.svc.ListReplies(ctx, req.RootId, req.MinId, req.Limit)
+	h.renderComments(ctx, cs, err)
+}
+
+// Delete 删除自己的评论，或者自己帖子下面的评论
+func (h *CommentHandler) Delete(ctx *gin.Context) {
+	type Req struct {
+		Id int64 `json:"id"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	claims, ok := ctx.MustGet("claims").(*ijwt.UserClaims)
+	if !ok {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	err := h.svc.Delete(ctx, req.Id, claims.Uid)
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{
+			Msg: "OK",
+		})
+	case service.ErrCommentNotFound, service.ErrCommentNotOwner:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "评论不存在或者没有权限",
+		})
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+	}
+}
+
+func (h *CommentHandler) renderComments(ctx *gin.Context, cs []domain.Comment, err error) {
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: slice.Map(cs, func(idx int, src domain.Comment) CommentVO {
+			return CommentVO{
+				Id:       src.Id,
+				Uid:      src.Uid,
+				Content:  src.Content,
+				RootId:   src.RootId,
+				ParentId: src.ParentId,
+				Deleted:  src.Deleted,
+				Ctime:    src.Ctime.Format(time.DateTime),
+			}
+		}),
+	})
+}
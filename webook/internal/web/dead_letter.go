@@ -0,0 +1,99 @@
+package web
+
+import (
+	"dream/webook/internal/domain"
+	"dream/webook/internal/service"
+	"net/http"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+)
+
+var _ handler = (*DeadLetterHandler)(nil)
+
+// DeadLetterHandler 查看和重放死信，只有管理员能用
+type DeadLetterHandler struct {
+	svc service.DeadLetterService
+}
+
+func NewDeadLetterHandler(svc service.DeadLetterService) *DeadLetterHandler {
+	return &DeadLetterHandler{
+		svc: svc,
+	}
+}
+
+func (h *DeadLetterHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.POST("/list", h.List)
+	rg.POST("/replay", h.Replay)
+}
+
+func (h *DeadLetterHandler) List(ctx *gin.Context) {
+	type Req struct {
+		Group  string `json:"group"`
+		Offset int    `json:"offset"`
+		Limit  int    `json:"limit"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 100
+	}
+	dls, err := h.svc.List(ctx, req.Group, req.Offset, req.Limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: slice.Map(dls, func(idx int, src domain.DeadLetter) DeadLetterVO {
+			return DeadLetterVO{
+				Id:       src.Id,
+				Group:    src.Group,
+				Topic:    src.Topic,
+				Key:      string(src.Key),
+				Value:    string(src.Value),
+				Err:      src.Err,
+				Replayed: src.Replayed,
+				Ctime:    src.Ctime.Format(time.DateTime),
+			}
+		}),
+	})
+}
+
+// Replay 修好了消费者之后，把死信重新投递给原来的消费者组
+func (h *DeadLetterHandler) Replay(ctx *gin.Context) {
+	type Req struct {
+		Id int64 `json:"id"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	err := h.svc.Replay(ctx, req.Id)
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{
+			Msg: "OK",
+		})
+	case service.ErrDeadLetterNotFound:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "死信不存在",
+		})
+	case service.ErrDeadLetterReplayed:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "已经重放过了",
+		})
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+	}
+}
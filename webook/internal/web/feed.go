@@ -0,0 +1,82 @@
+package web
+
+import (
+	"dream/webook/internal/domain"
+	"dream/webook/internal/service"
+	ijwt "dream/webook/internal/web/jwt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var _ handler = (*FeedHandler)(nil)
+
+// FeedHandler 关注的人发表的帖子
+type FeedHandler struct {
+	svc service.FeedService
+}
+
+func NewFeedHandler(svc service.FeedService) *FeedHandler {
+	return &FeedHandler{
+		svc: svc,
+	}
+}
+
+func (h *FeedHandler) RegisterRoutes(fg *gin.RouterGroup) {
+	fg.POST("/list", h.List)
+}
+
+// List 时间线，下一页把返回的 cursor 带上
+func (h *FeedHandler) List(ctx *gin.Context) {
+	type Req struct {
+		// Cursor 和 CursorId 都用上一页返回的，第一页不传
+		Cursor   int64 `json:"cursor"`
+		CursorId int64 `json:"cursorId"`
+		Limit    int   `json:"limit"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 100
+	}
+	claims, ok := ctx.MustGet("claims").(*ijwt.UserClaims)
+	if !ok {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	var before time.Time
+	if req.Cursor > 0 {
+		before = time.UnixMilli(req.Cursor)
+	}
+	events, err := h.svc.Feed(ctx, claims.Uid, before, req.CursorId, req.Limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		return
+	}
+	res := FeedVO{
+		Articles: make([]ArticleVO, 0, len(events)),
+	}
+	for _, e := range events {
+		res.Cursor, res.CursorId = e.Ctime.UnixMilli(), e.Article.Id
+		// 撤回了的不展示，但是游标还是要往后走
+		if e.Article.Status != domain.ArticleStatusPublished {
+			continue
+		}
+		res.Articles = append(res.Articles, ArticleVO{
+			Id:       e.Article.Id,
+			Title:    e.Article.Title,
+			Abstract: e.Article.Abstract(),
+			AuthorId: e.Article.Author.Id,
+			Ctime:    e.Ctime.Format(time.DateTime),
+		})
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: res,
+	})
+}
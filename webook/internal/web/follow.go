@@ -0,0 +1,147 @@
+package web
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/service"
+	ijwt "dream/webook/internal/web/jwt"
+	"net/http"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+)
+
+var _ handler = (*FollowHandler)(nil)
+
+// FollowHandler 关注作者
+type FollowHandler struct {
+	svc service.FollowService
+}
+
+func NewFollowHandler(svc service.FollowService) *FollowHandler {
+	return &FollowHandler{
+		svc: svc,
+	}
+}
+
+func (h *FollowHandler) RegisterRoutes(fg *gin.RouterGroup) {
+	fg.POST("/add", h.Follow)
+	fg.POST("/cancel", h.CancelFollow)
+	fg.POST("/followers", h.Followers)
+	fg.POST("/followees", h.Followees)
+}
+
+func (h *FollowHandler) Follow(ctx *gin.Context) {
+	type Req struct {
+		Followee int64 `json:"followee"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	claims, ok := ctx.MustGet("claims").(*ijwt.UserClaims)
+	if !ok {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	err := h.svc.Follow(ctx, claims.Uid, req.Followee)
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{
+			Msg: "OK",
+		})
+	case service.ErrFollowSelf:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "不能关注自己",
+		})
+	case service.ErrUserNotFound:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "用户不存在",
+		})
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+	}
+}
+
+func (h *FollowHandler) CancelFollow(ctx *gin.Context) {
+	type Req struct {
+		Followee int64 `json:"followee"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	claims, ok := ctx.MustGet("claims").(*ijwt.UserClaims)
+	if !ok {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	if err := h.svc.CancelFollow(ctx, claims.Uid, req.Followee); err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Msg: "OK",
+	})
+}
+
+// Followers 粉丝列表，uid 不传就是看自己的
+func (h *FollowHandler) Followers(ctx *gin.Context) {
+	h.list(ctx, h.svc.Followers)
+}
+
+// Followees 关注列表，uid 不传就是看自己的
+func (h *FollowHandler) Followees(ctx *gin.Context) {
+	h.list(ctx, h.svc.Followees)
+}
+
+func (h *FollowHandler) list(ctx *gin.Context,
+	find func(ctx context.Context, uid int64, offset, limit int) ([]domain.FollowUser, error)) {
+	type Req struct {
+		Uid    int64 `json:"uid"`
+		Offset int   `json:"offset"`
+		Limit  int   `json:"limit"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 100
+	}
+	if req.Uid == 0 {
+		claims, ok := ctx.MustGet("claims").(*ijwt.UserClaims)
+		if !ok {
+			ctx.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		req.Uid = claims.Uid
+	}
+	us, err := find(ctx, req.Uid, req.Offset, req.Limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: slice.Map(us, func(idx int, src domain.FollowUser) FollowUserVO {
+			return FollowUserVO{
+				Id:       src.Id,
+				Nickname: src.Nickname,
+				Mutual:   src.Mutual,
+				Ctime:    src.Ctime.Format(time.DateTime),
+			}
+		}),
+	})
+}
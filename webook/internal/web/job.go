@@ -0,0 +1,109 @@
+package web
+
+import (
+	"dream/webook/internal/domain"
+	"dream/webook/internal/service"
+	"net/http"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+)
+
+var _ handler = (*JobHandler)(nil)
+
+// JobHandler 管理数据库里的任务，只有管理员能用
+type JobHandler struct {
+	svc service.JobService
+}
+
+func NewJobHandler(svc service.JobService) *JobHandler {
+	return &JobHandler{
+		svc: svc,
+	}
+}
+
+func (h *JobHandler) RegisterRoutes(jg *gin.RouterGroup) {
+	jg.POST("/list", h.List)
+	jg.POST("/pause", h.Pause)
+	jg.POST("/resume", h.Resume)
+}
+
+func (h *JobHandler) List(ctx *gin.Context) {
+	type Req struct {
+		Offset int `json:"offset"`
+		Limit  int `json:"limit"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 100
+	}
+	js, err := h.svc.List(ctx, req.Offset, req.Limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: slice.Map(js, func(idx int, src domain.Job) JobVO {
+			return JobVO{
+				Id:         src.Id,
+				Name:       src.Name,
+				Executor:   src.Executor,
+				Expression: src.Expression,
+				NextTime:   src.NextTime.Format(time.DateTime),
+				Status:     src.Status.String(),
+				Owner:      src.Owner,
+				Utime:      src.Utime.Format(time.DateTime),
+			}
+		}),
+	})
+}
+
+// Pause 暂停任务，正在执行的会被取消
+func (h *JobHandler) Pause(ctx *gin.Context) {
+	type Req struct {
+		Id int64 `json:"id"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	h.handleErr(ctx, h.svc.Pause(ctx, req.Id))
+}
+
+// Resume 恢复暂停的任务
+func (h *JobHandler) Resume(ctx *gin.Context) {
+	type Req struct {
+		Id int64 `json:"id"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	h.handleErr(ctx, h.svc.Resume(ctx, req.Id))
+}
+
+func (h *JobHandler) handleErr(ctx *gin.Context, err error) {
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, Result{
+			Msg: "OK",
+		})
+	case service.ErrJobNotFound:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "任务不存在或者状态不对",
+		})
+	default:
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+	}
+}
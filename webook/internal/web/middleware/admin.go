@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+
+	ijwt "dream/webook/internal/web/jwt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminMiddlewareBuilder 只允许管理员访问，要放在登录校验后面
+type AdminMiddlewareBuilder struct {
+	uids map[int64]struct{}
+}
+
+func NewAdminMiddlewareBuilder(uids []int64) *AdminMiddlewareBuilder {
+	m := make(map[int64]struct{}, len(uids))
+	for _, uid := range uids {
+		m[uid] = struct{}{}
+	}
+	return &AdminMiddlewareBuilder{
+		uids: m,
+	}
+}
+
+func (a *AdminMiddlewareBuilder) Build() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		claims, ok := ctx.MustGet("claims").(*ijwt.UserClaims)
+		if !ok {
+			ctx.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		if _, ok = a.uids[claims.Uid]; !ok {
+			ctx.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+	}
+}
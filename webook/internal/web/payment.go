@@ -0,0 +1,78 @@
+package web
+
+import (
+	"dream/webook/internal/service"
+	"dream/webook/internal/service/payment"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PaymentHandler 支付平台的回调，不需要登录
+type PaymentHandler struct {
+	paySvc payment.Service
+	rwdSvc service.RewardService
+}
+
+func NewPaymentHandler(paySvc payment.Service, rwdSvc service.RewardService) *PaymentHandler {
+	return &PaymentHandler{
+		paySvc: paySvc,
+		rwdSvc: rwdSvc,
+	}
+}
+
+func (h *PaymentHandler) RegisterRoutes(server *gin.Engine) {
+	server.POST("/pay/callback", h.Callback)
+}
+
+// Callback 返回 2xx 支付平台就不会再重试，失败的时候返回 5xx 让它重试
+func (h *PaymentHandler) Callback(ctx *gin.Context) {
+	body, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		h.fail(ctx, http.StatusBadRequest)
+		return
+	}
+	pmt, err := h.paySvc.HandleNotify(ctx, ctx.Request.Header, body)
+	if err == payment.ErrInvalidNotify {
+		// 可能是伪造的请求，需要监控
+		log.Println("非法的支付回调", ctx.ClientIP())
+		h.fail(ctx, http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		log.Println("处理支付回调失败", err)
+		h.fail(ctx, http.StatusInternalServerError)
+		return
+	}
+	// 目前只有打赏用到了支付，以后按照业务单号的前缀分发
+	if !strings.HasPrefix(pmt.BizTradeNo, "reward-") {
+		log.Println("未知的业务单号", pmt.BizTradeNo)
+		ctx.Status(http.StatusNoContent)
+		return
+	}
+	err = h.rwdSvc.UpdateReward(ctx, pmt)
+	if errors.Is(err, service.ErrPaymentMismatch) {
+		// 不能入账，需要告警人工处理，支付平台重试的时候还会再告警
+		log.Println("支付回调和打赏订单不一致", err)
+		h.fail(ctx, http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Println("更新打赏状态失败", pmt.BizTradeNo, err)
+		h.fail(ctx, http.StatusInternalServerError)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// fail 微信支付要求失败的时候返回这个格式
+func (h *PaymentHandler) fail(ctx *gin.Context, status int) {
+	ctx.JSON(status, gin.H{
+		"code":    "FAIL",
+		"message": "失败",
+	})
+}
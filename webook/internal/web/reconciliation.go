@@ -0,0 +1,87 @@
+package web
+
+import (
+	"dream/webook/internal/domain"
+	"dream/webook/internal/service"
+	"net/http"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+)
+
+var _ handler = (*ReconciliationHandler)(nil)
+
+// ReconciliationHandler 给财务看对账结果，只有管理员能用
+type ReconciliationHandler struct {
+	svc service.ReconciliationService
+}
+
+func NewReconciliationHandler(svc service.ReconciliationService) *ReconciliationHandler {
+	return &ReconciliationHandler{
+		svc: svc,
+	}
+}
+
+func (h *ReconciliationHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.POST("/summary", h.Summary)
+}
+
+// Summary 时间格式是 2006-01-02 15:04:05，不传的话默认最近一天
+func (h *ReconciliationHandler) Summary(ctx *gin.Context) {
+	type Req struct {
+		Start string `json:"start"`
+		End   string `json:"end"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	end := time.Now()
+	start := end.Add(-time.Hour * 24)
+	var err error
+	if req.Start != "" {
+		start, err = time.ParseInLocation(time.DateTime, req.Start, time.Local)
+		if err != nil {
+			ctx.JSON(http.StatusOK, Result{
+				Code: 4,
+				Msg:  "开始时间格式不对",
+			})
+			return
+		}
+	}
+	if req.End != "" {
+		end, err = time.ParseInLocation(time.DateTime, req.End, time.Local)
+		if err != nil {
+			ctx.JSON(http.StatusOK, Result{
+				Code: 4,
+				Msg:  "结束时间格式不对",
+			})
+			return
+		}
+	}
+	if !start.Before(end) {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "开始时间必须早于结束时间",
+		})
+		return
+	}
+	res, err := h.svc.Summary(ctx, start, end)
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: slice.Map(res, func(idx int, src domain.ReconciliationSummary) ReconciliationSummaryVO {
+			return ReconciliationSummaryVO{
+				Result: src.Result.String(),
+				Cnt:    src.Cnt,
+				Amt:    src.Amt,
+			}
+		}),
+	})
+}
@@ -0,0 +1,60 @@
+package web
+
+import (
+	"dream/webook/internal/service"
+	ijwt "dream/webook/internal/web/jwt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+var _ handler = (*RewardHandler)(nil)
+
+// RewardHandler 打赏，下单在 ArticleHandler 里面
+type RewardHandler struct {
+	svc service.RewardService
+}
+
+func NewRewardHandler(svc service.RewardService) *RewardHandler {
+	return &RewardHandler{
+		svc: svc,
+	}
+}
+
+func (h *RewardHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.POST("/detail", h.Detail)
+}
+
+// Detail 查询打赏状态，前端关掉二维码之后轮询
+func (h *RewardHandler) Detail(ctx *gin.Context) {
+	type Req struct {
+		Rid int64 `json:"rid"`
+	}
+	var req Req
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	claims, ok := ctx.MustGet("claims").(*ijwt.UserClaims)
+	if !ok {
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	r, err := h.svc.GetReward(ctx, req.Rid, claims.Uid)
+	if err == service.ErrRewardNotFound {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 4,
+			Msg:  "打赏不存在",
+		})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusOK, Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, Result{
+		Data: r.Status.String(),
+	})
+}
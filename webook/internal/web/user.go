@@ -29,12 +29,13 @@ var _ handler = (*UserHandler)(nil)
 type UserHandler struct {
 	svc         service.UserService
 	codeSvc     service.CodeService
+	followSvc   service.FollowService
 	emailExp    *regexp.Regexp
 	passwordExp *regexp.Regexp
 	ijwt.Handler
 }
 
-func NewUserHandler(svc service.UserService, codeSvc service.CodeService) *UserHandler {
+func NewUserHandler(svc service.UserService, codeSvc service.CodeService, followSvc service.FollowService) *UserHandler {
 	const (
 		emailRegexp    = "^[a-zA-Z0-9_.+-]+@[a-zA-Z0-9-]+\\.[a-zA-Z0-9-.]+$"
 		passwordRegexp = "^(?=.*\\d)(?=.*[A-z])[\\da-zA-Z]{1,72}$"
@@ -46,6 +47,7 @@ func NewUserHandler(svc service.UserService, codeSvc service.CodeService) *UserH
 	return &UserHandler{
 		svc:         svc,
 		codeSvc:     codeSvc,
+		followSvc:   followSvc,
 		emailExp:    emailExp,
 		passwordExp: passwordExp,
 		Handler: ijwt.NewRedisJWTHandler(redis.NewClient(&redis.Options{
@@ -492,19 +494,26 @@ func (u *UserHandler) ProfileJWT(ctx *gin.Context) {
 		Nickname string
 		Birthday string
 		AboutMe  string
+		// 粉丝数和关注数
+		Followers int64
+		Followees int64
 	}
 
 	if claims, ok := ctx.MustGet("claims").(*ijwt.UserClaims); ok {
-		u, err := u.svc.Profile(ctx, claims.Uid)
+		user, err := u.svc.Profile(ctx, claims.Uid)
 		if err != nil {
 			ctx.String(http.StatusOK, "用户不存在")
 			return
 		}
+		// 计数查不到不影响看资料，按照 0 返回
+		s, _ := u.followSvc.Statics(ctx, claims.Uid)
 		ctx.JSON(http.StatusOK, ProfileReq{
-			Email:    u.Email,
-			Nickname: u.Nickname,
-			Birthday: u.Birthday.Format("2006-01-02"),
-			AboutMe:  u.AboutMe,
+			Email:     user.Email,
+			Nickname:  user.Nickname,
+			Birthday:  user.Birthday.Format("2006-01-02"),
+			AboutMe:   user.AboutMe,
+			Followers: s.Followers,
+			Followees: s.Followees,
 		})
 	} else {
 		ctx.String(http.StatusOK, "系统错误")
@@ -161,7 +161,7 @@ func TestUserHandler_Signup(t *testing.T) {
 			defer ctrl.Finish()
 
 			server := gin.Default()
-			userHandler := NewUserHandler(tc.mock(ctrl), nil) // 注册这里用不到codesvc
+			userHandler := NewUserHandler(tc.mock(ctrl), nil, nil) // 注册这里用不到codesvc和followSvc
 			userHandler.RegisterRoutes(server.Group("/users"))
 
 			req, err := http.NewRequest(http.MethodPost, "/users/signup", bytes.NewBuffer([]byte(tc.reqBody)))
@@ -0,0 +1,157 @@
+package web
+
+import "dream/webook/internal/domain"
+
+// ArticleReq 编辑和发表共用的请求
+type ArticleReq struct {
+	Id      int64  `json:"id"`
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
+
+func (req ArticleReq) toDomain(uid int64) domain.Article {
+	return domain.Article{
+		Id:      req.Id,
+		Title:   req.Title,
+		Content: req.Content,
+		Author: domain.Author{
+			Id: uid,
+		},
+	}
+}
+
+// ArticleVO 返回给前端的帖子
+type ArticleVO struct {
+	Id       int64  `json:"id"`
+	Title    string `json:"title"`
+	Abstract string `json:"abstract"`
+	Content  string `json:"content"`
+	Status   uint8  `json:"status"`
+	// 读者视角才需要作者信息
+	AuthorId   int64  `json:"authorId"`
+	AuthorName string `json:"authorName"`
+	// 交互数据，也是读者视角才有
+	ReadCnt    int64  `json:"readCnt"`
+	LikeCnt    int64  `json:"likeCnt"`
+	CollectCnt int64  `json:"collectCnt"`
+	Liked      bool   `json:"liked"`
+	Collected  bool   `json:"collected"`
+	Ctime      string `json:"ctime"`
+	Utime      string `json:"utime"`
+}
+
+// RevisionVO 帖子的历史版本
+type RevisionVO struct {
+	Id        int64  `json:"id"`
+	ArticleId int64  `json:"articleId"`
+	AuthorId  int64  `json:"authorId"`
+	Title     string `json:"title"`
+	Content   string `json:"content,omitempty"`
+	Hash      string `json:"hash"`
+	Ctime     string `json:"ctime"`
+}
+
+// DiffLineVO 一行 diff，op 是 "+"、"-" 或者 " "
+type DiffLineVO struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// CollectionVO 收藏夹
+type CollectionVO struct {
+	Id      int64  `json:"id"`
+	Uid     int64  `json:"uid"`
+	Name    string `json:"name"`
+	Private bool   `json:"private"`
+	Default bool   `json:"default"`
+	Ctime   string `json:"ctime"`
+	Utime   string `json:"utime"`
+}
+
+// CollectionItemVO 收藏夹里面的一个帖子，ctime 是收藏时间
+type CollectionItemVO struct {
+	Cid     int64     `json:"cid"`
+	Article ArticleVO `json:"article"`
+	Ctime   string    `json:"ctime"`
+}
+
+// HotArticleVO 热榜上的帖子
+type HotArticleVO struct {
+	Id       int64   `json:"id"`
+	Title    string  `json:"title"`
+	Abstract string  `json:"abstract"`
+	AuthorId int64   `json:"authorId"`
+	Score    float64 `json:"score"`
+	Ctime    string  `json:"ctime"`
+}
+
+// JobVO 数据库里的任务
+type JobVO struct {
+	Id         int64  `json:"id"`
+	Name       string `json:"name"`
+	Executor   string `json:"executor"`
+	Expression string `json:"expression"`
+	NextTime   string `json:"nextTime"`
+	Status     string `json:"status"`
+	Owner      string `json:"owner"`
+	Utime      string `json:"utime"`
+}
+
+// ReconciliationSummaryVO 按照对账结果汇总，金额单位是分
+type ReconciliationSummaryVO struct {
+	Result string `json:"result"`
+	Cnt    int64  `json:"cnt"`
+	Amt    int64  `json:"amt"`
+}
+
+// CodeURLVO 打赏的支付二维码
+type CodeURLVO struct {
+	Rid     int64  `json:"rid"`
+	CodeURL string `json:"codeURL"`
+}
+
+// AccountVO 账户余额，单位是分
+type AccountVO struct {
+	Currency string `json:"currency"`
+	Balance  int64  `json:"balance"`
+}
+
+// CommentVO 评论，删除了的 content 是空的
+type CommentVO struct {
+	Id       int64  `json:"id"`
+	Uid      int64  `json:"uid"`
+	Content  string `json:"content"`
+	RootId   int64  `json:"rootId"`
+	ParentId int64  `json:"parentId"`
+	Deleted  bool   `json:"deleted"`
+	Ctime    string `json:"ctime"`
+}
+
+// FollowUserVO 关注列表和粉丝列表里面的人
+type FollowUserVO struct {
+	Id       int64  `json:"id"`
+	Nickname string `json:"nickname"`
+	// Mutual 互相关注
+	Mutual bool   `json:"mutual"`
+	Ctime  string `json:"ctime"`
+}
+
+// FeedVO 时间线的一页
+type FeedVO struct {
+	Articles []ArticleVO `json:"articles"`
+	// Cursor 毫秒时间戳，和 CursorId 一起下一页带上，是 0 说明没有更多了
+	Cursor   int64 `json:"cursor"`
+	CursorId int64 `json:"cursorId"`
+}
+
+// DeadLetterVO Key 和 Value 按照字符串展示，目前的消息都是 JSON
+type DeadLetterVO struct {
+	Id       int64  `json:"id"`
+	Group    string `json:"group"`
+	Topic    string `json:"topic"`
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Err      string `json:"err"`
+	Replayed bool   `json:"replayed"`
+	Ctime    string `json:"ctime"`
+}
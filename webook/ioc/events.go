@@ -0,0 +1,76 @@
+package ioc
+
+import (
+	"dream/webook/config"
+	ievents "dream/webook/internal/events"
+	"dream/webook/internal/events/article"
+	"dream/webook/internal/events/outbox"
+	"dream/webook/internal/events/user"
+	"dream/webook/internal/service"
+	"dream/webook/internal/service/sms/async"
+	"dream/webook/pkg/events"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// InitEventBroker 没有配置 Kafka 的时候用内存里的，本地跑起来不用装 Kafka
+func InitEventBroker() events.Broker {
+	if len(config.Config.Kafka.Addrs) == 0 {
+		return events.NewMemoryBroker(3)
+	}
+	return events.NewKafkaBroker(config.Config.Kafka.Addrs, sarama.NewConfig())
+}
+
+func InitEventProducer(broker events.Broker) events.Producer {
+	p, err := broker.Producer()
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// InitReadEventConsumer 失败的阅读事件先进重试 topic，最后进死信
+func InitReadEventConsumer(broker events.Broker, producer events.Producer, dlSvc service.DeadLetterService,
+	svc service.InteractiveService, rankSvc service.RankingService) *article.ReadEventConsumer {
+	const group = "interactive"
+	c, err := broker.Consumer(group)
+	if err != nil {
+		panic(err)
+	}
+	rc := events.NewRetryConsumer(c, producer, dlSvc, group,
+		[]time.Duration{time.Second * 10, time.Minute, time.Minute * 10})
+	return article.NewReadEventConsumer(rc, svc, rankSvc)
+}
+
+// InitPublishedEventConsumer 写时间线失败了也走重试 topic
+func InitPublishedEventConsumer(broker events.Broker, producer events.Producer, dlSvc service.DeadLetterService,
+	feedSvc service.FeedService) *article.PublishedEventConsumer {
+	const group = "feed"
+	c, err := broker.Consumer(group)
+	if err != nil {
+		panic(err)
+	}
+	rc := events.NewRetryConsumer(c, producer, dlSvc, group,
+		[]time.Duration{time.Second * 10, time.Minute, time.Minute * 10})
+	return article.NewPublishedEventConsumer(rc, feedSvc)
+}
+
+// InitSignupEventConsumer 建默认收藏夹失败了也走重试 topic
+func InitSignupEventConsumer(broker events.Broker, producer events.Producer, dlSvc service.DeadLetterService,
+	collSvc service.CollectionService) *user.SignupEventConsumer {
+	const group = "collection"
+	c, err := broker.Consumer(group)
+	if err != nil {
+		panic(err)
+	}
+	rc := events.NewRetryConsumer(c, producer, dlSvc, group,
+		[]time.Duration{time.Second * 10, time.Minute, time.Minute * 10})
+	return user.NewSignupEventConsumer(rc, collSvc)
+}
+
+// InitConsumers 所有需要启动的消费者，发件箱转发和异步短信也在这里一起启动
+func InitConsumers(read *article.ReadEventConsumer, published *article.PublishedEventConsumer,
+	signup *user.SignupEventConsumer, relay *outbox.Relay, asyncSMS *async.Service) []ievents.Consumer {
+	return []ievents.Consumer{read, published, signup, relay, asyncSMS}
+}
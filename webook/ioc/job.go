@@ -0,0 +1,47 @@
+package ioc
+
+import (
+	"context"
+	"dream/webook/internal/domain"
+	"dream/webook/internal/job"
+	"dream/webook/internal/service"
+	"dream/webook/pkg/cronjob"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+func InitScheduler(redisClient redis.Cmdable, db *gorm.DB, rankingJob *job.RankingJob) *cronjob.Scheduler {
+	err := cronjob.InitTable(db)
+	if err != nil {
+		panic(err)
+	}
+	s := cronjob.NewScheduler(cronjob.NewRedisLocker(redisClient), cronjob.NewGORMRecorder(db))
+	// 注册失败说明表达式写错了，直接不让启动
+	err = s.Register("@every 1m", rankingJob)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// InitJobScheduler 数据库里的任务，执行方法在本地注册
+func InitJobScheduler(svc service.JobService, reconJob *job.ReconciliationJob) *job.Scheduler {
+	exec := job.NewLocalFuncExecutor()
+	exec.RegisterFunc(reconJob.Name(), reconJob.Run)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	// 已经有了就不会覆盖，后台改过的表达式和状态都保留
+	err := svc.AddIfNotExist(ctx, domain.Job{
+		Name:       reconJob.Name(),
+		Executor:   exec.Name(),
+		Expression: "*/5 * * * *",
+	})
+	if err != nil {
+		panic(err)
+	}
+	s := job.NewScheduler(svc)
+	s.RegisterExecutor(exec)
+	return s
+}
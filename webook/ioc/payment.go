@@ -0,0 +1,49 @@
+package ioc
+
+import (
+	"dream/webook/config"
+	"dream/webook/internal/service/payment"
+	"dream/webook/internal/service/payment/memory"
+	"dream/webook/internal/service/payment/wechat"
+	"log"
+	"net/http"
+	"os"
+	"time"
+)
+
+func InitPaymentService() payment.Service {
+	if config.Config.Pay.Memory {
+		// 只有本地开发的配置会打开，线上没有配置微信支付就启动失败
+		log.Println("使用内存模拟支付，不能用于线上")
+		return memory.NewService()
+	}
+	privateKey, err := wechat.LoadPrivateKey(mustGetenv("WECHAT_PAY_PRIVATE_KEY_PATH"))
+	if err != nil {
+		panic(err)
+	}
+	publicKey, err := wechat.LoadPublicKey(mustGetenv("WECHAT_PAY_PUBLIC_KEY_PATH"))
+	if err != nil {
+		panic(err)
+	}
+	// 下单、查单卡住的时候不能一直占着请求
+	client := &http.Client{Timeout: time.Second * 5}
+	return wechat.NewNativePaymentService(wechat.Config{
+		AppID:             mustGetenv("WECHAT_APP_ID"),
+		MchID:             mustGetenv("WECHAT_PAY_MCH_ID"),
+		MchSerialNo:       mustGetenv("WECHAT_PAY_MCH_SERIAL_NO"),
+		PrivateKey:        privateKey,
+		APIv3Key:          mustGetenv("WECHAT_PAY_API_V3_KEY"),
+		PlatformPublicKey: publicKey,
+		PlatformSerialNo:  mustGetenv("WECHAT_PAY_PUBLIC_KEY_ID"),
+		// NotifyURL 是我们自己的 /pay/callback 的外网地址
+		NotifyURL: mustGetenv("WECHAT_PAY_NOTIFY_URL"),
+	}, client)
+}
+
+func mustGetenv(key string) string {
+	val := os.Getenv(key)
+	if val == "" {
+		panic("没有找到环境变量 " + key)
+	}
+	return val
+}
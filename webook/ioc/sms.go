@@ -1,11 +1,31 @@
 package ioc
 
 import (
+	"dream/webook/internal/repository"
 	"dream/webook/internal/service/sms"
+	"dream/webook/internal/service/sms/async"
+	smscircuitbreaker "dream/webook/internal/service/sms/circuitbreaker"
 	"dream/webook/internal/service/sms/memory"
+	smsratelimit "dream/webook/internal/service/sms/ratelimit"
+	"dream/webook/pkg/circuitbreaker"
+	"dream/webook/pkg/ratelimit"
+	"time"
+
+	"github.com/redis/go-redis/v9"
 )
 
-func InitSMSService() sms.Service {
+func InitSMSService(svc *async.Service) sms.Service {
+	return svc
+}
+
+// InitAsyncSMSService 服务商限流或者连续失败的时候，转成异步发送
+// 后台发送也要在 InitConsumers 里面启动
+func InitAsyncSMSService(redisClient redis.Cmdable, repo repository.AsyncSMSRepository) *async.Service {
 	// 换内存，还是换别的
-	return memory.NewService()
+	var svc sms.Service = memory.NewService()
+	// 服务商一直失败就熔断，熔断期间的短信直接转异步
+	svc = smscircuitbreaker.NewCircuitBreakerSMSService(svc, circuitbreaker.NewBreaker(circuitbreaker.DefaultConfig()))
+	svc = smsratelimit.NewRateLimitSMSServcie(svc,
+		ratelimit.NewRedisSlidingWindowLimiter(redisClient, time.Second, 100))
+	return async.NewService(svc, repo)
 }
@@ -1,6 +1,7 @@
 package ioc
 
 import (
+	"dream/webook/config"
 	"dream/webook/internal/web"
 	ijwt "dream/webook/internal/web/jwt"
 	"dream/webook/internal/web/middleware"
@@ -13,18 +14,34 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
-func InitGin(hdl *web.UserHandler, mdls []gin.HandlerFunc, oauth2WechatHdl *web.WeChatOAuth2Handler) *gin.Engine {
+func InitGin(hdl *web.UserHandler, mdls []gin.HandlerFunc, oauth2WechatHdl *web.WeChatOAuth2Handler,
+	artHdl *web.ArticleHandler, collHdl *web.CollectionHandler, jobHdl *web.JobHandler,
+	rwdHdl *web.RewardHandler, payHdl *web.PaymentHandler, reconHdl *web.ReconciliationHandler,
+	accHdl *web.AccountHandler, cmtHdl *web.CommentHandler, followHdl *web.FollowHandler,
+	feedHdl *web.FeedHandler, dlHdl *web.DeadLetterHandler) *gin.Engine {
 	server := gin.Default()
 	server.Use(mdls...)
 	hdl.RegisterRoutes(server.Group("/users"))
 	oauth2WechatHdl.RegisteRoutes(server)
+	artHdl.RegisterRoutes(server.Group("/articles"))
+	collHdl.RegisterRoutes(server.Group("/collections"))
+	rwdHdl.RegisterRoutes(server.Group("/reward"))
+	payHdl.RegisterRoutes(server)
+	accHdl.RegisterRoutes(server.Group("/account"))
+	cmtHdl.RegisterRoutes(server.Group("/comments"))
+	followHdl.RegisterRoutes(server.Group("/follow"))
+	feedHdl.RegisterRoutes(server.Group("/feed"))
+	admin := middleware.NewAdminMiddlewareBuilder(config.Config.Admin.Uids).Build()
+	jobHdl.RegisterRoutes(server.Group("/admin/jobs", admin))
+	reconHdl.RegisterRoutes(server.Group("/admin/reconciliations", admin))
+	dlHdl.RegisterRoutes(server.Group("/admin/dead-letters", admin))
 	return server
 }
 
 func InitMiddlewares(redisClient redis.Cmdable, jwtHdl ijwt.Handler) []gin.HandlerFunc {
 	return []gin.HandlerFunc{
 		corsHdl(),
-		middleware.NewLoginJWTMiddlewareBuilder(jwtHdl).IgnorePaths("/users/login").IgnorePaths("/users/signup").IgnorePaths("/users/login_sms/code/send").IgnorePaths("/users/login_sms").IgnorePaths("/users/refresh_token").IgnorePaths("/oauth2/wechat/authurl").IgnorePaths("/oauth2/wechat/callback").Build(),
+		middleware.NewLoginJWTMiddlewareBuilder(jwtHdl).IgnorePaths("/users/login").IgnorePaths("/users/signup").IgnorePaths("/users/login_sms/code/send").IgnorePaths("/users/login_sms").IgnorePaths("/users/refresh_token").IgnorePaths("/oauth2/wechat/authurl").IgnorePaths("/oauth2/wechat/callback").IgnorePaths("/pay/callback").Build(),
 		ratelimit.NewBuilder(redisClient, time.Second, 100).Build(),
 	}
 }
@@ -2,7 +2,10 @@ package ioc
 
 import (
 	"dream/webook/internal/service/oauth2/wechat"
+	"dream/webook/pkg/circuitbreaker"
+	"net/http"
 	"os"
+	"time"
 )
 
 func InitWechatService() wechat.Service {
@@ -16,5 +19,11 @@ func InitWechatService() wechat.Service {
 		appKey = "123"
 		// panic("没有找到环境变量 WECHAT_APP_SECRET")
 	}
-	return wechat.NewService(appId, appKey)
+	// 微信接口一直出错的时候熔断，不要让每个扫码登录都卡在超时上
+	client := &http.Client{
+		Transport: circuitbreaker.NewRoundTripper(http.DefaultTransport,
+			circuitbreaker.NewBreaker(circuitbreaker.DefaultConfig())),
+		Timeout: time.Second * 5,
+	}
+	return wechat.NewService(appId, appKey, client)
 }
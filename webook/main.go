@@ -1,14 +1,39 @@
 package main
 
 import (
+	"context"
+	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
 
-	server := initWebServer()
+	app := initApp()
+	app.scheduler.Start()
+	defer func() {
+		// 等正在执行的任务结束
+		<-app.scheduler.Stop().Done()
+	}()
+	// 收到退出信号之后取消，定时任务和消费者跟着一起退出
+	jobCtx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	go func() {
+		err := app.jobScheduler.Schedule(jobCtx)
+		log.Println("任务调度退出", err)
+	}()
+	for _, c := range app.consumers {
+		if err := c.Start(jobCtx); err != nil {
+			panic(err)
+		}
+	}
+
+	server := app.server
 	// db := initDB()
 	// server := initWebServer()
 	// redisClient := redis.NewClient(&redis.Options{
@@ -21,7 +46,24 @@ func main() {
 	server.GET("/hello", func(ctx *gin.Context) {
 		ctx.String(http.StatusOK, "hello go")
 	})
-	server.Run(":8080")
+	srv := &http.Server{
+		Addr:    ":8080",
+		Handler: server,
+	}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Println("HTTP 服务退出", err)
+			cancel()
+		}
+	}()
+	<-jobCtx.Done()
+	log.Println("开始退出")
+	// 不再接收新请求，等正在处理的请求结束
+	ctx, shutdownCancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer shutdownCancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Println("HTTP 服务关闭失败", err)
+	}
 }
 
 // func initWebServer() *gin.Engine {
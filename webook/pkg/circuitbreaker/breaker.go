@@ -0,0 +1,176 @@
+// Package circuitbreaker 熔断器：
+// 关闭状态正常放行，统计窗口内失败比例超过阈值就打开；
+// 打开状态直接拒绝，过了冷却时间进入半开；
+// 半开状态只放少量请求过去试探，全部成功就关闭，有一个失败就重新打开
+package circuitbreaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrOpen 熔断器打开了，请求没有发出去
+var ErrOpen = errors.New("熔断器打开了")
+
+type State uint8
+
+const (
+	StateClosed State = iota
+	StateOpen
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+type Config struct {
+	// Window 关闭状态下统计失败比例的窗口，过了就重新统计
+	Window time.Duration
+	// MinRequests 窗口内请求太少的时候不打开，偶发的失败不能说明问题
+	MinRequests int
+	// FailureRatio 失败比例达到这个值就打开
+	FailureRatio float64
+	// CoolDown 打开之后过多久进入半开
+	CoolDown time.Duration
+	// HalfOpenRequests 半开状态放过去的请求数，全部成功才关闭
+	HalfOpenRequests int
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Window:           time.Second * 10,
+		MinRequests:      10,
+		FailureRatio:     0.5,
+		CoolDown:         time.Second * 30,
+		HalfOpenRequests: 3,
+	}
+}
+
+type Breaker struct {
+	cfg Config
+	mu  sync.Mutex
+
+	state State
+	// generation 每次切换状态都加一，上一个状态放过去的请求结果不算
+	generation uint64
+	// expiry 关闭状态是窗口结束的时间，打开状态是冷却结束的时间
+	expiry time.Time
+
+	requests int
+	failures int
+	// successes 半开状态下成功的请求数
+	successes int
+}
+
+func NewBreaker(cfg Config) *Breaker {
+	b := &Breaker{cfg: cfg}
+	b.toState(StateClosed, time.Now())
+	return b
+}
+
+// Allow 判断能不能放行，能的话请求结束之后要调用 done 上报结果
+func (b *Breaker) Allow() (done func(success bool), err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	b.refresh(now)
+	switch b.state {
+	case StateOpen:
+		return nil, ErrOpen
+	case StateHalfOpen:
+		if b.requests >= b.cfg.HalfOpenRequests {
+			return nil, ErrOpen
+		}
+	}
+	b.requests++
+	generation := b.generation
+	return func(success bool) {
+		b.report(generation, success)
+	}, nil
+}
+
+// Do 包一个请求，fn 返回 nil 就是成功
+func (b *Breaker) Do(fn func() error) error {
+	done, err := b.Allow()
+	if err != nil {
+		return err
+	}
+	err = fn()
+	done(err == nil)
+	return err
+}
+
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refresh(time.Now())
+	return b.state
+}
+
+func (b *Breaker) report(generation uint64, success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	b.refresh(now)
+	if generation != b.generation {
+		return
+	}
+	switch b.state {
+	case StateClosed:
+		if !success {
+			b.failures++
+		}
+		if b.requests >= b.cfg.MinRequests &&
+			float64(b.failures)/float64(b.requests) >= b.cfg.FailureRatio {
+			b.toState(StateOpen, now)
+		}
+	case StateHalfOpen:
+		if !success {
+			b.toState(StateOpen, now)
+			return
+		}
+		b.successes++
+		if b.successes >= b.cfg.HalfOpenRequests {
+			b.toState(StateClosed, now)
+		}
+	}
+}
+
+// refresh 时间到了就切换状态，窗口到了就重新统计
+func (b *Breaker) refresh(now time.Time) {
+	if now.Before(b.expiry) {
+		return
+	}
+	switch b.state {
+	case StateClosed:
+		b.toState(StateClosed, now)
+	case StateOpen:
+		b.toState(StateHalfOpen, now)
+	}
+}
+
+func (b *Breaker) toState(state State, now time.Time) {
+	b.state = state
+	b.generation++
+	b.requests, b.failures, b.successes = 0, 0, 0
+	switch state {
+	case StateClosed:
+		b.expiry = now.Add(b.cfg.Window)
+	case StateOpen:
+		b.expiry = now.Add(b.cfg.CoolDown)
+	default:
+		// 半开状态没有超时，等请求的结果
+		b.expiry = time.Time{}
+	}
+}
@@ -0,0 +1,140 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testConfig() Config {
+	return Config{
+		Window:           time.Minute,
+		MinRequests:      4,
+		FailureRatio:     0.5,
+		CoolDown:         time.Millisecond * 50,
+		HalfOpenRequests: 2,
+	}
+}
+
+var errMock = errors.New("mock error")
+
+func TestBreaker(t *testing.T) {
+	b := NewBreaker(testConfig())
+	succeed := func() error { return nil }
+	fail := func() error { return errMock }
+
+	// 请求数不够，全失败也不打开
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, errMock, b.Do(fail))
+	}
+	assert.Equal(t, StateClosed, b.State())
+	// 第四个请求之后失败比例 3/4
+	assert.NoError(t, b.Do(succeed))
+	assert.Equal(t, StateOpen, b.State())
+	assert.Equal(t, ErrOpen, b.Do(succeed))
+
+	// 冷却之后半开，只放两个请求
+	time.Sleep(time.Millisecond * 60)
+	assert.Equal(t, StateHalfOpen, b.State())
+	done1, err := b.Allow()
+	require.NoError(t, err)
+	done2, err := b.Allow()
+	require.NoError(t, err)
+	_, err = b.Allow()
+	assert.Equal(t, ErrOpen, err)
+	// 有一个失败就重新打开
+	done1(true)
+	done2(false)
+	assert.Equal(t, StateOpen, b.State())
+
+	// 再次半开，全部成功就关闭
+	time.Sleep(time.Millisecond * 60)
+	assert.NoError(t, b.Do(succeed))
+	assert.NoError(t, b.Do(succeed))
+	assert.Equal(t, StateClosed, b.State())
+}
+
+func TestBreaker_Window(t *testing.T) {
+	cfg := testConfig()
+	cfg.Window = time.Millisecond * 50
+	b := NewBreaker(cfg)
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, errMock, b.Do(func() error { return errMock }))
+	}
+	// 窗口过了重新统计
+	time.Sleep(time.Millisecond * 60)
+	assert.Equal(t, errMock, b.Do(func() error { return errMock }))
+	assert.Equal(t, StateClosed, b.State())
+}
+
+func TestBreaker_StaleReport(t *testing.T) {
+	b := NewBreaker(testConfig())
+	slow, err := b.Allow()
+	require.NoError(t, err)
+	for i := 0; i < 4; i++ {
+		_ = b.Do(func() error { return errMock })
+	}
+	require.Equal(t, StateOpen, b.State())
+	time.Sleep(time.Millisecond * 60)
+	require.Equal(t, StateHalfOpen, b.State())
+	// 打开之前发出去的请求，结果不影响半开状态
+	slow(false)
+	assert.Equal(t, StateHalfOpen, b.State())
+}
+
+func TestRoundTripper(t *testing.T) {
+	status := http.StatusInternalServerError
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	client := &http.Client{
+		Transport: NewRoundTripper(nil, NewBreaker(testConfig())),
+	}
+	for i := 0; i < 4; i++ {
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	}
+	// 5xx 太多，不再请求
+	_, err := client.Get(server.URL)
+	assert.ErrorIs(t, err, ErrOpen)
+
+	// 4xx 不算失败
+	status = http.StatusBadRequest
+	time.Sleep(time.Millisecond * 60)
+	for i := 0; i < 6; i++ {
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+}
+
+func TestRoundTripper_Canceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	client := &http.Client{
+		Transport: NewRoundTripper(nil, NewBreaker(testConfig())),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i := 0; i < 4; i++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		_, err = client.Do(req)
+		assert.ErrorIs(t, err, context.Canceled)
+	}
+	// 调用方取消的不算失败，不会熔断
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+}
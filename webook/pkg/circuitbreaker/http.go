@@ -0,0 +1,39 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// RoundTripper 包在 http.Client 的 Transport 外面，
+// 网络错误和 5xx 算失败，4xx 和调用方自己取消的是调用方的问题，不算
+type RoundTripper struct {
+	next    http.RoundTripper
+	breaker *Breaker
+}
+
+// NewRoundTripper next 为空的话用 http.DefaultTransport
+func NewRoundTripper(next http.RoundTripper, breaker *Breaker) *RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &RoundTripper{
+		next:    next,
+		breaker: breaker,
+	}
+}
+
+func (r *RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	done, err := r.breaker.Allow()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		done(errors.Is(err, context.Canceled))
+		return nil, err
+	}
+	done(resp.StatusCode < http.StatusInternalServerError)
+	return resp, err
+}
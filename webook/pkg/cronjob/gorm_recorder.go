@@ -0,0 +1,50 @@
+package cronjob
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// GORMRecorder 把执行历史写到 MySQL
+type GORMRecorder struct {
+	db *gorm.DB
+}
+
+func NewGORMRecorder(db *gorm.DB) Recorder {
+	return &GORMRecorder{
+		db: db,
+	}
+}
+
+// InitTable 建表，在初始化数据库的时候调用
+func InitTable(db *gorm.DB) error {
+	return db.AutoMigrate(&CronJobExecution{})
+}
+
+func (r *GORMRecorder) Record(ctx context.Context, exec Execution) error {
+	errMsg := []rune(exec.Err)
+	if len(errMsg) > 1024 {
+		errMsg = errMsg[:1024]
+	}
+	return r.db.WithContext(ctx).Create(&CronJobExecution{
+		Name:     exec.Name,
+		Instance: exec.Instance,
+		Status:   uint8(exec.Status),
+		Err:      string(errMsg),
+		Stime:    exec.Start.UnixMilli(),
+		Etime:    exec.End.UnixMilli(),
+	}).Error
+}
+
+// CronJobExecution 任务的执行历史
+type CronJobExecution struct {
+	Id int64 `gorm:"primaryKey,autoIncrement"`
+	// 按任务查询最近的执行记录
+	Name     string `gorm:"type:varchar(128);index:name_stime"`
+	Instance string `gorm:"type:varchar(128)"`
+	Status   uint8
+	Err      string `gorm:"type:varchar(1024)"`
+	Stime    int64  `gorm:"index:name_stime"`
+	Etime    int64
+}
@@ -0,0 +1,76 @@
+package cronjob
+
+import (
+	"context"
+	_ "embed"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	uuid "github.com/satori/go.uuid"
+)
+
+var (
+	//go:embed lock_refresh.lua
+	luaRefresh string
+	//go:embed lock_unlock.lua
+	luaUnlock string
+)
+
+// RedisLocker 基于 SET NX 的分布式锁
+type RedisLocker struct {
+	client redis.Cmdable
+}
+
+func NewRedisLocker(client redis.Cmdable) Locker {
+	return &RedisLocker{
+		client: client,
+	}
+}
+
+func (l *RedisLocker) Lock(ctx context.Context, key string, expiration time.Duration) (Lock, error) {
+	// value 用来区分锁是不是自己的
+	val := uuid.NewV4().String()
+	ok, err := l.client.SetNX(ctx, key, val, expiration).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrLockNotHold
+	}
+	return &redisLock{
+		client:     l.client,
+		key:        key,
+		val:        val,
+		expiration: expiration,
+	}, nil
+}
+
+type redisLock struct {
+	client     redis.Cmdable
+	key        string
+	val        string
+	expiration time.Duration
+}
+
+func (l *redisLock) Refresh(ctx context.Context) error {
+	res, err := l.client.Eval(ctx, luaRefresh, []string{l.key},
+		l.val, l.expiration.Milliseconds()).Int64()
+	if err != nil {
+		return err
+	}
+	if res != 1 {
+		return ErrLockNotHold
+	}
+	return nil
+}
+
+func (l *redisLock) Unlock(ctx context.Context) error {
+	res, err := l.client.Eval(ctx, luaUnlock, []string{l.key}, l.val).Int64()
+	if err != nil {
+		return err
+	}
+	if res != 1 {
+		return ErrLockNotHold
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package cronjob
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Scheduler 按 cron 表达式调度任务
+// 每一轮先抢这一轮的锁，抢到的实例才执行，保证多个实例一轮只执行一次。
+// 执行之前再抢任务的锁，上一轮还没跑完的话这一轮就跳过
+type Scheduler struct {
+	cron     *cron.Cron
+	locker   Locker
+	recorder Recorder
+	// instance 当前实例的标识，写到执行历史里面
+	instance  string
+	keyPrefix string
+	// lockExpiration 锁的过期时间，持有锁期间每过三分之一就续约一次
+	lockExpiration time.Duration
+}
+
+func NewScheduler(locker Locker, recorder Recorder) *Scheduler {
+	host, _ := os.Hostname()
+	return &Scheduler{
+		cron:           cron.New(),
+		locker:         locker,
+		recorder:       recorder,
+		instance:       fmt.Sprintf("%s-%d", host, os.Getpid()),
+		keyPrefix:      "cronjob:",
+		lockExpiration: time.Second * 30,
+	}
+}
+
+// Register 注册任务，spec 是标准的 cron 表达式，也支持 @every 1m 这种写法
+func (s *Scheduler) Register(spec string, job Job) error {
+	sched, err := cron.ParseStandard(spec)
+	if err != nil {
+		return err
+	}
+	period := roundPeriod(sched)
+	s.cron.Schedule(sched, cron.FuncJob(func() {
+		s.run(job, period)
+	}))
+	return nil
+}
+
+// roundPeriod 一轮的时间粒度。@every 是从每个实例各自启动的时候开始算的，
+// 不同实例触发的时刻对不上，按间隔取整之后才是同一轮；cron 表达式最小粒度是分钟
+func roundPeriod(sched cron.Schedule) time.Duration {
+	if cd, ok := sched.(cron.ConstantDelaySchedule); ok {
+		return cd.Delay
+	}
+	return time.Minute
+}
+
+func (s *Scheduler) Start() {
+	s.cron.Start()
+}
+
+// Stop 不再调度新的任务，返回的 ctx 在正在执行的任务都结束之后被取消
+func (s *Scheduler) Stop() context.Context {
+	return s.cron.Stop()
+}
+
+func (s *Scheduler) run(job Job, period time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// 这一轮的锁不释放，过了这一轮自然过期，别的实例这一轮再触发就抢不到
+	round := time.Now().Truncate(period).Unix()
+	_, err := s.locker.Lock(ctx, fmt.Sprintf("%s%s:%d", s.keyPrefix, job.Name(), round), period)
+	if err == ErrLockNotHold {
+		return
+	}
+	if err != nil {
+		log.Println("获取任务锁失败", job.Name(), err)
+		return
+	}
+	lock, err := s.locker.Lock(ctx, s.keyPrefix+job.Name(), s.lockExpiration)
+	if err == ErrLockNotHold {
+		return
+	}
+	if err != nil {
+		log.Println("获取任务锁失败", job.Name(), err)
+		return
+	}
+	defer func() {
+		uctx, ucancel := context.WithTimeout(context.Background(), time.Second)
+		defer ucancel()
+		if er := lock.Unlock(uctx); er != nil {
+			log.Println("释放任务锁失败", job.Name(), er)
+		}
+	}()
+
+	done := make(chan struct{})
+	defer close(done)
+	go s.renew(job.Name(), lock, done, cancel)
+
+	exec := Execution{
+		Name:     job.Name(),
+		Instance: s.instance,
+		Start:    time.Now(),
+	}
+	err = job.Run(ctx)
+	exec.End = time.Now()
+	if err != nil {
+		exec.Status = ExecutionStatusFailed
+		exec.Err = err.Error()
+	} else {
+		exec.Status = ExecutionStatusSuccess
+	}
+	rctx, rcancel := context.WithTimeout(context.Background(), time.Second)
+	defer rcancel()
+	if er := s.recorder.Record(rctx, exec); er != nil {
+		log.Println("记录任务执行历史失败", job.Name(), er)
+	}
+}
+
+// renew 任务结束之前一直续约，锁被别人抢走了就取消任务
+func (s *Scheduler) renew(name string, lock Lock, done <-chan struct{}, cancel context.CancelFunc) {
+	ticker := time.NewTicker(s.lockExpiration / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			ctx, rcancel := context.WithTimeout(context.Background(), time.Second)
+			err := lock.Refresh(ctx)
+			rcancel()
+			if err == ErrLockNotHold {
+				log.Println("任务锁已经丢失，取消任务", name)
+				cancel()
+				return
+			}
+			// 偶发的网络错误，下一次再试，真过期了下一次会返回 ErrLockNotHold
+			if err != nil {
+				log.Println("任务锁续约失败", name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package cronjob
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_run(t *testing.T) {
+	testCases := []struct {
+		name   string
+		locker *fakeLocker
+		job    *fakeJob
+
+		wantRun      bool
+		wantStatus   ExecutionStatus
+		wantUnlocked bool
+	}{
+		{
+			name:         "执行成功",
+			locker:       newFakeLocker(),
+			job:          &fakeJob{},
+			wantRun:      true,
+			wantStatus:   ExecutionStatusSuccess,
+			wantUnlocked: true,
+		},
+		{
+			name:         "执行失败",
+			locker:       newFakeLocker(),
+			job:          &fakeJob{err: errors.New("mock job error")},
+			wantRun:      true,
+			wantStatus:   ExecutionStatusFailed,
+			wantUnlocked: true,
+		},
+		{
+			name:   "别的实例在跑",
+			locker: &fakeLocker{lockErr: ErrLockNotHold, locks: map[string]*fakeLock{}},
+			job:    &fakeJob{},
+		},
+		{
+			name:         "续约发现锁丢了，取消任务",
+			locker:       &fakeLocker{refreshErr: ErrLockNotHold, locks: map[string]*fakeLock{}},
+			job:          &fakeJob{waitCancel: true},
+			wantRun:      true,
+			wantStatus:   ExecutionStatusFailed,
+			wantUnlocked: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := &fakeRecorder{}
+			s := NewScheduler(tc.locker, rec)
+			s.lockExpiration = time.Millisecond * 30
+			s.run(tc.job, time.Minute)
+
+			assert.Equal(t, tc.wantRun, tc.job.ran)
+			if tc.wantRun {
+				// 任务的锁释放了，这一轮的锁留着
+				assert.Equal(t, tc.wantUnlocked, tc.locker.locks["cronjob:mock"].unlocked)
+				assert.Len(t, tc.locker.locks, 2)
+			}
+			if !tc.wantRun {
+				assert.Empty(t, rec.execs)
+				return
+			}
+			assert.Len(t, rec.execs, 1)
+			assert.Equal(t, "mock", rec.execs[0].Name)
+			assert.Equal(t, tc.wantStatus, rec.execs[0].Status)
+		})
+	}
+}
+
+func TestScheduler_runOncePerRound(t *testing.T) {
+	locker := newFakeLocker()
+	rec := &fakeRecorder{}
+	// 两个实例，触发的时刻不一样，但是在同一轮里面
+	s1 := NewScheduler(locker, rec)
+	s2 := NewScheduler(locker, rec)
+	job1, job2 := &fakeJob{}, &fakeJob{}
+	s1.run(job1, time.Hour)
+	s2.run(job2, time.Hour)
+	assert.True(t, job1.ran)
+	assert.False(t, job2.ran)
+	assert.Len(t, rec.execs, 1)
+}
+
+func TestRoundPeriod(t *testing.T) {
+	testCases := []struct {
+		spec string
+		want time.Duration
+	}{
+		{spec: "@every 1m", want: time.Minute},
+		{spec: "@every 1h30m", want: time.Hour + time.Minute*30},
+		{spec: "*/5 * * * *", want: time.Minute},
+		{spec: "@daily", want: time.Minute},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.spec, func(t *testing.T) {
+			sched, err := cron.ParseStandard(tc.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, roundPeriod(sched))
+		})
+	}
+}
+
+type fakeJob struct {
+	err error
+	// waitCancel 一直跑到 ctx 被取消
+	waitCancel bool
+	ran        bool
+}
+
+func (j *fakeJob) Name() string {
+	return "mock"
+}
+
+func (j *fakeJob) Run(ctx context.Context) error {
+	j.ran = true
+	if j.waitCancel {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return j.err
+}
+
+// fakeLocker 同一个 key 没释放之前抢不到
+type fakeLocker struct {
+	lockErr    error
+	refreshErr error
+	locks      map[string]*fakeLock
+}
+
+func newFakeLocker() *fakeLocker {
+	return &fakeLocker{locks: map[string]*fakeLock{}}
+}
+
+func (l *fakeLocker) Lock(ctx context.Context, key string, expiration time.Duration) (Lock, error) {
+	if l.lockErr != nil {
+		return nil, l.lockErr
+	}
+	if lock, ok := l.locks[key]; ok && !lock.unlocked {
+		return nil, ErrLockNotHold
+	}
+	lock := &fakeLock{refreshErr: l.refreshErr}
+	l.locks[key] = lock
+	return lock, nil
+}
+
+type fakeLock struct {
+	refreshErr error
+	unlocked   bool
+}
+
+func (l *fakeLock) Refresh(ctx context.Context) error {
+	return l.refreshErr
+}
+
+func (l *fakeLock) Unlock(ctx context.Context) error {
+	l.unlocked = true
+	return nil
+}
+
+type fakeRecorder struct {
+	mu    sync.Mutex
+	execs []Execution
+}
+
+func (r *fakeRecorder) Record(ctx context.Context, exec Execution) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.execs = append(r.execs, exec)
+	return nil
+}
@@ -0,0 +1,52 @@
+package cronjob
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrLockNotHold 锁被别人拿着，或者已经过期被别人抢走了
+var ErrLockNotHold = errors.New("没有持有锁")
+
+// Job 定时任务，ctx 在任务超时或者丢了锁的时候会被取消
+type Job interface {
+	Name() string
+	Run(ctx context.Context) error
+}
+
+// Locker 分布式锁，保证多个实例里面同一时刻只有一个在跑任务
+type Locker interface {
+	// Lock 拿不到锁返回 ErrLockNotHold，不会阻塞等待
+	Lock(ctx context.Context, key string, expiration time.Duration) (Lock, error)
+}
+
+type Lock interface {
+	// Refresh 续约，锁已经不是自己的了返回 ErrLockNotHold
+	Refresh(ctx context.Context) error
+	Unlock(ctx context.Context) error
+}
+
+// Recorder 记录任务的执行历史
+type Recorder interface {
+	Record(ctx context.Context, exec Execution) error
+}
+
+type ExecutionStatus uint8
+
+const (
+	ExecutionStatusUnknown ExecutionStatus = iota
+	ExecutionStatusSuccess
+	ExecutionStatusFailed
+)
+
+// Execution 任务的一次执行
+type Execution struct {
+	Name string
+	// Instance 在哪个实例上执行的
+	Instance string
+	Status   ExecutionStatus
+	Err      string
+	Start    time.Time
+	End      time.Time
+}
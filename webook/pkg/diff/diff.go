@@ -0,0 +1,161 @@
+// Package diff 按行比较两段文本
+package diff
+
+import "strings"
+
+type Op uint8
+
+const (
+	// OpEqual 两边都有
+	OpEqual Op = iota
+	// OpInsert 新版本多出来的行
+	OpInsert
+	// OpDelete 旧版本有，新版本删掉的行
+	OpDelete
+)
+
+func (o Op) String() string {
+	switch o {
+	case OpInsert:
+		return "+"
+	case OpDelete:
+		return "-"
+	default:
+		return " "
+	}
+}
+
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// maxEdits 回溯要保存每一步的 v，内存是 O(D*maxEdits)，
+// 差异超过这么多行就不找最短的了，直接整段删掉再整段插入
+const maxEdits = 500
+
+// Lines 用 Myers 算法计算从 a 到 b 的最短编辑脚本
+// 时间复杂度 O((N+M)D)，D 是差异的行数，对于大部分只改了几行的场景足够快
+// 差异超过 maxEdits 行的时候退化成整段替换
+func Lines(a, b string) []Line {
+	return diff(splitLines(a), splitLines(b))
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+}
+
+func diff(a, b []string) []Line {
+	// 先去掉公共的前缀和后缀，减少计算量
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix &&
+		a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	res := make([]Line, 0, len(a)+len(b))
+	for _, l := range a[:prefix] {
+		res = append(res, Line{Op: OpEqual, Text: l})
+	}
+	res = append(res, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, l := range a[len(a)-suffix:] {
+		res = append(res, Line{Op: OpEqual, Text: l})
+	}
+	return res
+}
+
+func myers(a, b []string) []Line {
+	n, m := len(a), len(b)
+	if n == 0 && m == 0 {
+		return nil
+	}
+	maxD := min(n+m, maxEdits)
+	offset := maxD
+	// v[k+offset] 记录对角线 k 上走得最远的 x
+	v := make([]int, 2*maxD+2)
+	// 记录每一步的 v，用于回溯
+	trace := make([][]int, 0, maxD+1)
+	for d := 0; d <= maxD; d++ {
+		cur := make([]int, len(v))
+		copy(cur, v)
+		trace = append(trace, cur)
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[k-1+offset] < v[k+1+offset]) {
+				// 向下走，也就是插入
+				x = v[k+1+offset]
+			} else {
+				// 向右走，也就是删除
+				x = v[k-1+offset] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[k+offset] = x
+			if x >= n && y >= m {
+				return backtrack(a, b, trace, offset, d)
+			}
+		}
+	}
+	return replace(a, b)
+}
+
+// replace 差异太多，旧的全部删掉，新的全部插入
+func replace(a, b []string) []Line {
+	res := make([]Line, 0, len(a)+len(b))
+	for _, l := range a {
+		res = append(res, Line{Op: OpDelete, Text: l})
+	}
+	for _, l := range b {
+		res = append(res, Line{Op: OpInsert, Text: l})
+	}
+	return res
+}
+
+func backtrack(a, b []string, trace [][]int, offset, d int) []Line {
+	res := make([]Line, 0, len(a)+len(b))
+	x, y := len(a), len(b)
+	for ; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[k-1+offset] < v[k+1+offset]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[prevK+offset]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			res = append(res, Line{Op: OpEqual, Text: a[x]})
+		}
+		if x == prevX {
+			y--
+			res = append(res, Line{Op: OpInsert, Text: b[y]})
+		} else {
+			x--
+			res = append(res, Line{Op: OpDelete, Text: a[x]})
+		}
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		res = append(res, Line{Op: OpEqual, Text: a[x]})
+	}
+	// 回溯出来是倒序的
+	for i, j := 0, len(res)-1; i < j; i, j = i+1, j-1 {
+		res[i], res[j] = res[j], res[i]
+	}
+	return res
+}
@@ -0,0 +1,117 @@
+package diff
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLines(t *testing.T) {
+	testCases := []struct {
+		name string
+		a    string
+		b    string
+
+		want []Line
+	}{
+		{
+			name: "都为空",
+			want: []Line{},
+		},
+		{
+			name: "完全一样",
+			a:    "a\nb",
+			b:    "a\nb",
+			want: []Line{
+				{Op: OpEqual, Text: "a"},
+				{Op: OpEqual, Text: "b"},
+			},
+		},
+		{
+			name: "全部新增",
+			b:    "a\nb",
+			want: []Line{
+				{Op: OpInsert, Text: "a"},
+				{Op: OpInsert, Text: "b"},
+			},
+		},
+		{
+			name: "全部删除",
+			a:    "a\nb",
+			want: []Line{
+				{Op: OpDelete, Text: "a"},
+				{Op: OpDelete, Text: "b"},
+			},
+		},
+		{
+			name: "中间修改一行",
+			a:    "a\nb\nc",
+			b:    "a\nB\nc",
+			want: []Line{
+				{Op: OpEqual, Text: "a"},
+				{Op: OpDelete, Text: "b"},
+				{Op: OpInsert, Text: "B"},
+				{Op: OpEqual, Text: "c"},
+			},
+		},
+		{
+			name: "交错修改",
+			a:    "a\nb\nc\na\nb\nb\na",
+			b:    "c\nb\na\nb\na\nc",
+			want: []Line{
+				{Op: OpDelete, Text: "a"},
+				{Op: OpDelete, Text: "b"},
+				{Op: OpEqual, Text: "c"},
+				{Op: OpInsert, Text: "b"},
+				{Op: OpEqual, Text: "a"},
+				{Op: OpEqual, Text: "b"},
+				{Op: OpDelete, Text: "b"},
+				{Op: OpEqual, Text: "a"},
+				{Op: OpInsert, Text: "c"},
+			},
+		},
+		{
+			name: "兼容 CRLF",
+			a:    "a\r\nb",
+			b:    "a\nb\nc",
+			want: []Line{
+				{Op: OpEqual, Text: "a"},
+				{Op: OpEqual, Text: "b"},
+				{Op: OpInsert, Text: "c"},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res := Lines(tc.a, tc.b)
+			assert.Equal(t, tc.want, res)
+		})
+	}
+}
+
+func TestLines_TooManyEdits(t *testing.T) {
+	// 隔一行改一行，差异 600 行，超过了 maxEdits
+	a := make([]string, 0, 600)
+	b := make([]string, 0, 600)
+	for i := 0; i < 600; i++ {
+		a = append(a, strconv.Itoa(i))
+		if i%2 == 0 {
+			b = append(b, "b"+strconv.Itoa(i))
+		} else {
+			b = append(b, strconv.Itoa(i))
+		}
+	}
+	want := make([]Line, 0, 1200)
+	for _, l := range a[:599] {
+		want = append(want, Line{Op: OpDelete, Text: l})
+	}
+	for _, l := range b[:599] {
+		want = append(want, Line{Op: OpInsert, Text: l})
+	}
+	// 公共的后缀还是保留
+	want = append(want, Line{Op: OpEqual, Text: "599"})
+	res := Lines(strings.Join(a, "\n"), strings.Join(b, "\n"))
+	assert.Equal(t, want, res)
+}
@@ -0,0 +1,167 @@
+package events
+
+import (
+	"context"
+	"errors"
+
+	"github.com/IBM/sarama"
+)
+
+var _ Broker = (*KafkaBroker)(nil)
+
+// KafkaBroker 线上用的 Kafka，分区和消费者组都交给 Kafka 自己管
+type KafkaBroker struct {
+	addrs []string
+	cfg   *sarama.Config
+}
+
+func NewKafkaBroker(addrs []string, cfg *sarama.Config) *KafkaBroker {
+	// 同步发送要拿到发送结果
+	cfg.Producer.Return.Successes = true
+	// 位移由我们在处理成功之后标记，sarama 定时提交
+	cfg.Consumer.Offsets.AutoCommit.Enable = true
+	// 消息头要 0.11 以上才支持
+	if !cfg.Version.IsAtLeast(sarama.V0_11_0_0) {
+		cfg.Version = sarama.V0_11_0_0
+	}
+	return &KafkaBroker{
+		addrs: addrs,
+		cfg:   cfg,
+	}
+}
+
+func (b *KafkaBroker) Producer() (Producer, error) {
+	p, err := sarama.NewSyncProducer(b.addrs, b.cfg)
+	if err != nil {
+		return nil, err
+	}
+	return NewKafkaProducer(p), nil
+}
+
+func (b *KafkaBroker) Consumer(group string) (Consumer, error) {
+	cg, err := sarama.NewConsumerGroup(b.addrs, group, b.cfg)
+	if err != nil {
+		return nil, err
+	}
+	return NewKafkaConsumer(cg), nil
+}
+
+type KafkaProducer struct {
+	producer sarama.SyncProducer
+}
+
+func NewKafkaProducer(producer sarama.SyncProducer) *KafkaProducer {
+	return &KafkaProducer{
+		producer: producer,
+	}
+}
+
+func (p *KafkaProducer) Produce(ctx context.Context, msg *Message) error {
+	pm := &sarama.ProducerMessage{
+		Topic: msg.Topic,
+		Value: sarama.ByteEncoder(msg.Value),
+	}
+	// 没有 key 的时候不能传空的 key，不然所有消息都进同一个分区
+	if len(msg.Key) > 0 {
+		pm.Key = sarama.ByteEncoder(msg.Key)
+	}
+	for k, v := range msg.Headers {
+		pm.Headers = append(pm.Headers, sarama.RecordHeader{Key: []byte(k), Value: []byte(v)})
+	}
+	partition, offset, err := p.producer.SendMessage(pm)
+	if err != nil {
+		return err
+	}
+	msg.Partition, msg.Offset = partition, offset
+	return nil
+}
+
+func (p *KafkaProducer) Close() error {
+	return p.producer.Close()
+}
+
+type KafkaConsumer struct {
+	group sarama.ConsumerGroup
+}
+
+func NewKafkaConsumer(group sarama.ConsumerGroup) *KafkaConsumer {
+	return &KafkaConsumer{
+		group: group,
+	}
+}
+
+func (c *KafkaConsumer) Consume(ctx context.Context, topics []string, hdl Handler) error {
+	cfg, batchHdl := singleBatch(hdl)
+	return c.ConsumeBatch(ctx, topics, cfg, batchHdl)
+}
+
+func (c *KafkaConsumer) ConsumeBatch(ctx context.Context, topics []string, cfg BatchConfig, hdl BatchHandler) error {
+	for {
+		// 每次重平衡 Consume 都会返回，要重新调用
+		err := c.group.Consume(ctx, topics, &kafkaGroupHandler{cfg: cfg, hdl: hdl})
+		if errors.Is(err, sarama.ErrClosedConsumerGroup) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+func (c *KafkaConsumer) Close() error {
+	return c.group.Close()
+}
+
+type kafkaGroupHandler struct {
+	cfg BatchConfig
+	hdl BatchHandler
+}
+
+func (h *kafkaGroupHandler) Setup(session sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (h *kafkaGroupHandler) Cleanup(session sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (h *kafkaGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	ctx, cancel := context.WithCancel(session.Context())
+	defer cancel()
+	msgs := make(chan *Message)
+	go func() {
+		defer close(msgs)
+		for msg := range claim.Messages() {
+			select {
+			case msgs <- kafkaToMessage(msg):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	// 重平衡或者退出了，没有标记的消息会重新投递
+	consumeBatches(ctx, msgs, h.cfg, h.hdl, func(last *Message) {
+		session.MarkOffset(last.Topic, last.Partition, last.Offset+1, "")
+	})
+	return nil
+}
+
+func kafkaToMessage(msg *sarama.ConsumerMessage) *Message {
+	res := &Message{
+		Topic:     msg.Topic,
+		Key:       msg.Key,
+		Value:     msg.Value,
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+	}
+	if len(msg.Headers) > 0 {
+		res.Headers = make(map[string]string, len(msg.Headers))
+		for _, h := range msg.Headers {
+			res.Headers[string(h.Key)] = string(h.Value)
+		}
+	}
+	return res
+}
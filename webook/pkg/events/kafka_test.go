@@ -0,0 +1,56 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKafkaProducer_Produce(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(t *testing.T) sarama.SyncProducer
+
+		wantErr error
+	}{
+		{
+			name: "发送成功",
+			mock: func(t *testing.T) sarama.SyncProducer {
+				p := mocks.NewSyncProducer(t, nil)
+				p.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+					key, _ := msg.Key.Encode()
+					if msg.Topic != "test" || string(key) != "key1" {
+						return errors.New("消息不对")
+					}
+					return nil
+				})
+				return p
+			},
+		},
+		{
+			name: "发送失败",
+			mock: func(t *testing.T) sarama.SyncProducer {
+				p := mocks.NewSyncProducer(t, nil)
+				p.ExpectSendMessageAndFail(sarama.ErrOutOfBrokers)
+				return p
+			},
+			wantErr: sarama.ErrOutOfBrokers,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewKafkaProducer(tc.mock(t))
+			defer p.Close()
+			err := p.Produce(context.Background(), &Message{
+				Topic: "test",
+				Key:   []byte("key1"),
+				Value: []byte("value1"),
+			})
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
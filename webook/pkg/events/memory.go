@@ -0,0 +1,349 @@
+package events
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+)
+
+var _ Broker = (*MemoryBroker)(nil)
+
+// MemoryBroker 内存里的消息队列，给测试和本地开发用，重启就没了
+type MemoryBroker struct {
+	mu         sync.Mutex
+	partitions int
+	topics     map[string]*memoryTopic
+	groups     map[string]*memoryGroup
+	// rr 没有 key 的消息轮询分区
+	rr atomic.Uint32
+}
+
+// NewMemoryBroker partitions 是每个 topic 的分区数
+func NewMemoryBroker(partitions int) *MemoryBroker {
+	return &MemoryBroker{
+		partitions: partitions,
+		topics:     make(map[string]*memoryTopic),
+		groups:     make(map[string]*memoryGroup),
+	}
+}
+
+func (b *MemoryBroker) Producer() (Producer, error) {
+	return &memoryProducer{broker: b}, nil
+}
+
+func (b *MemoryBroker) Consumer(group string) (Consumer, error) {
+	return &memoryConsumer{
+		broker: b,
+		group:  b.group(group),
+		closed: make(chan struct{}),
+	}, nil
+}
+
+// topic 第一次用到的时候创建
+func (b *MemoryBroker) topic(name string) *memoryTopic {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t, ok := b.topics[name]
+	if !ok {
+		t = &memoryTopic{partitions: make([]*memoryPartition, b.partitions)}
+		for i := range t.partitions {
+			t.partitions[i] = &memoryPartition{notify: make(chan struct{})}
+		}
+		b.topics[name] = t
+	}
+	return t
+}
+
+func (b *MemoryBroker) group(name string) *memoryGroup {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	g, ok := b.groups[name]
+	if !ok {
+		g = &memoryGroup{
+			offsets:   make(map[string][]int64),
+			claims:    make(map[string][]chan struct{}),
+			rebalance: make(chan struct{}),
+		}
+		b.groups[name] = g
+	}
+	return g
+}
+
+func (b *MemoryBroker) partition(key []byte) int32 {
+	if len(key) == 0 {
+		return int32((b.rr.Add(1) - 1) % uint32(b.partitions))
+	}
+	h := fnv.New32a()
+	_, _ = h.Write(key)
+	return int32(h.Sum32() % uint32(b.partitions))
+}
+
+type memoryTopic struct {
+	partitions []*memoryPartition
+}
+
+type memoryPartition struct {
+	mu   sync.Mutex
+	msgs []*Message
+	// notify 有新消息的时候关掉，唤醒等待的消费者
+	notify chan struct{}
+}
+
+func (p *memoryPartition) append(msg *Message) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	msg.Offset = int64(len(p.msgs))
+	p.msgs = append(p.msgs, msg)
+	close(p.notify)
+	p.notify = make(chan struct{})
+}
+
+// fetch 没有 offset 这条消息的时候，返回一个有新消息会被关掉的 channel
+func (p *memoryPartition) fetch(offset int64) (*Message, <-chan struct{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if offset < int64(len(p.msgs)) {
+		return p.msgs[offset], nil
+	}
+	return nil, p.notify
+}
+
+type memoryProducer struct {
+	broker *MemoryBroker
+}
+
+func (p *memoryProducer) Produce(ctx context.Context, msg *Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	t := p.broker.topic(msg.Topic)
+	// 存一份副本，调用方后面再改 msg 也不影响
+	stored := *msg
+	stored.Headers = copyHeaders(msg.Headers)
+	stored.Partition = p.broker.partition(msg.Key)
+	t.partitions[stored.Partition].append(&stored)
+	msg.Partition, msg.Offset = stored.Partition, stored.Offset
+	return nil
+}
+
+func (p *memoryProducer) Close() error {
+	return nil
+}
+
+// memoryGroup 消费者组，记录每个分区提交了的位移，以及分区分给了谁
+type memoryGroup struct {
+	mu      sync.Mutex
+	members []*memoryMember
+	// offsets 下一条要消费的位移，key 是 topic
+	offsets map[string][]int64
+	// claims 每个分区同一时刻只能有一个消费者在消费，重平衡的时候新消费者要等旧的退出
+	claims map[string][]chan struct{}
+	// rebalance 成员变化的时候关掉，通知所有成员重新分配分区
+	rebalance chan struct{}
+}
+
+type memoryMember struct {
+	topics []string
+}
+
+type topicPartition struct {
+	topic     string
+	partition int32
+}
+
+func (g *memoryGroup) join(m *memoryMember, partitions int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, topic := range m.topics {
+		if _, ok := g.offsets[topic]; !ok {
+			g.offsets[topic] = make([]int64, partitions)
+			claims := make([]chan struct{}, partitions)
+			for i := range claims {
+				claims[i] = make(chan struct{}, 1)
+			}
+			g.claims[topic] = claims
+		}
+	}
+	g.members = append(g.members, m)
+	g.triggerRebalance()
+}
+
+func (g *memoryGroup) leave(m *memoryMember) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for i, member := range g.members {
+		if member == m {
+			g.members = append(g.members[:i], g.members[i+1:]...)
+			break
+		}
+	}
+	g.triggerRebalance()
+}
+
+func (g *memoryGroup) triggerRebalance() {
+	close(g.rebalance)
+	g.rebalance = make(chan struct{})
+}
+
+// assignment 每个 topic 的分区按照加入顺序轮流分给订阅了这个 topic 的成员
+func (g *memoryGroup) assignment(m *memoryMember) ([]topicPartition, <-chan struct{}) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var res []topicPartition
+	for _, topic := range m.topics {
+		var subscribers []*memoryMember
+		for _, member := range g.members {
+			for _, t := range member.topics {
+				if t == topic {
+					subscribers = append(subscribers, member)
+					break
+				}
+			}
+		}
+		for p := range g.offsets[topic] {
+			if subscribers[p%len(subscribers)] == m {
+				res = append(res, topicPartition{topic: topic, partition: int32(p)})
+			}
+		}
+	}
+	return res, g.rebalance
+}
+
+func (g *memoryGroup) offset(tp topicPartition) int64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.offsets[tp.topic][tp.partition]
+}
+
+func (g *memoryGroup) commit(tp topicPartition, offset int64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.offsets[tp.topic][tp.partition] = offset
+}
+
+func (g *memoryGroup) claim(tp topicPartition) chan struct{} {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.claims[tp.topic][tp.partition]
+}
+
+type memoryConsumer struct {
+	broker    *MemoryBroker
+	group     *memoryGroup
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (c *memoryConsumer) Consume(ctx context.Context, topics []string, hdl Handler) error {
+	cfg, batchHdl := singleBatch(hdl)
+	return c.ConsumeBatch(ctx, topics, cfg, batchHdl)
+}
+
+func (c *memoryConsumer) ConsumeBatch(ctx context.Context, topics []string, cfg BatchConfig, hdl BatchHandler) error {
+	for _, topic := range topics {
+		c.broker.topic(topic)
+	}
+	m := &memoryMember{topics: topics}
+	c.group.join(m, c.broker.partitions)
+	defer c.group.leave(m)
+	for {
+		assigned, rebalance := c.group.assignment(m)
+		sessCtx, cancel := context.WithCancel(ctx)
+		var wg sync.WaitGroup
+		for _, tp := range assigned {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				c.consumePartition(sessCtx, tp, cfg, hdl)
+			}()
+		}
+		var err error
+		select {
+		case <-rebalance:
+		case <-ctx.Done():
+			err = ctx.Err()
+		case <-c.closed:
+		}
+		cancel()
+		wg.Wait()
+		if err != nil {
+			return err
+		}
+		select {
+		case <-c.closed:
+			return nil
+		default:
+		}
+	}
+}
+
+func (c *memoryConsumer) consumePartition(ctx context.Context, tp topicPartition, cfg BatchConfig, hdl BatchHandler) {
+	claim := c.group.claim(tp)
+	select {
+	case claim <- struct{}{}:
+	case <-ctx.Done():
+		return
+	}
+	defer func() {
+		<-claim
+	}()
+	ctx, cancel := context.WithCancel(ctx)
+	msgs := make(chan *Message)
+	fetched := make(chan struct{})
+	go func() {
+		defer close(fetched)
+		defer close(msgs)
+		c.fetchPartition(ctx, tp, msgs)
+	}()
+	consumeBatches(ctx, msgs, cfg, hdl, func(last *Message) {
+		c.group.commit(tp, last.Offset+1)
+	})
+	// 拉消息的 goroutine 退出了才能把分区让出去
+	cancel()
+	<-fetched
+}
+
+// fetchPartition 从提交了的位移开始，按顺序把消息放进 msgs
+func (c *memoryConsumer) fetchPartition(ctx context.Context, tp topicPartition, msgs chan<- *Message) {
+	p := c.broker.topic(tp.topic).partitions[tp.partition]
+	offset := c.group.offset(tp)
+	for {
+		msg, wait := p.fetch(offset)
+		if msg == nil {
+			select {
+			case <-wait:
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+		// handler 拿到的是副本，改了也不影响重投
+		delivered := *msg
+		delivered.Headers = copyHeaders(msg.Headers)
+		select {
+		case msgs <- &delivered:
+			offset++
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (c *memoryConsumer) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+	})
+	return nil
+}
+
+func copyHeaders(headers map[string]string) map[string]string {
+	if headers == nil {
+		return nil
+	}
+	res := make(map[string]string, len(headers))
+	for k, v := range headers {
+		res[k] = v
+	}
+	return res
+}
@@ -0,0 +1,163 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryBroker_SameKeySamePartition(t *testing.T) {
+	b := NewMemoryBroker(4)
+	p, err := b.Producer()
+	require.NoError(t, err)
+	var partition int32 = -1
+	for i := 0; i < 10; i++ {
+		msg := &Message{Topic: "test", Key: []byte("key1"), Value: []byte(fmt.Sprint(i))}
+		require.NoError(t, p.Produce(context.Background(), msg))
+		if partition >= 0 {
+			assert.Equal(t, partition, msg.Partition)
+		}
+		partition = msg.Partition
+		// 分区内的位移是递增的
+		assert.Equal(t, int64(i), msg.Offset)
+	}
+}
+
+func TestMemoryBroker_ConsumerGroup(t *testing.T) {
+	b := NewMemoryBroker(4)
+	p, err := b.Producer()
+	require.NoError(t, err)
+	const total = 100
+	for i := 0; i < total; i++ {
+		err = p.Produce(context.Background(), &Message{
+			Topic: "test",
+			Key:   []byte(fmt.Sprint(i)),
+			Value: []byte(fmt.Sprint(i)),
+		})
+		require.NoError(t, err)
+	}
+
+	var (
+		mu       sync.Mutex
+		received = make(map[string]map[string]int)
+		wg       sync.WaitGroup
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	consume := func(group string) {
+		c, err := b.Consumer(group)
+		require.NoError(t, err)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = c.Consume(ctx, []string{"test"}, func(ctx context.Context, msg *Message) error {
+				mu.Lock()
+				defer mu.Unlock()
+				if received[group] == nil {
+					received[group] = make(map[string]int)
+				}
+				received[group][string(msg.Value)]++
+				if len(received["g1"]) == total && len(received["g2"]) == total {
+					cancel()
+				}
+				return nil
+			})
+		}()
+	}
+	// g1 两个消费者分摊分区，g2 一个消费者消费全部
+	consume("g1")
+	consume("g1")
+	consume("g2")
+	wg.Wait()
+
+	for _, group := range []string{"g1", "g2"} {
+		assert.Len(t, received[group], total, group)
+	}
+}
+
+func TestMemoryBroker_AtLeastOnce(t *testing.T) {
+	b := NewMemoryBroker(1)
+	p, err := b.Producer()
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		err = p.Produce(context.Background(), &Message{Topic: "test", Value: []byte(fmt.Sprint(i))})
+		require.NoError(t, err)
+	}
+	c, err := b.Consumer("g1")
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	var got []string
+	failed := false
+	_ = c.Consume(ctx, []string{"test"}, func(ctx context.Context, msg *Message) error {
+		got = append(got, string(msg.Value))
+		// 第一条第一次失败，要重新投递，并且不能跳到下一条
+		if string(msg.Value) == "0" && !failed {
+			failed = true
+			return errors.New("mock handler error")
+		}
+		if len(got) == 3 {
+			cancel()
+		}
+		return nil
+	})
+	assert.Equal(t, []string{"0", "0", "1"}, got)
+}
+
+func TestMemoryBroker_ConsumeBatch(t *testing.T) {
+	b := NewMemoryBroker(1)
+	p, err := b.Producer()
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		err = p.Produce(context.Background(), &Message{Topic: "test", Value: []byte(fmt.Sprint(i))})
+		require.NoError(t, err)
+	}
+	c, err := b.Consumer("g1")
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	var (
+		got    [][]string
+		failed bool
+	)
+	cfg := BatchConfig{Size: 2, Interval: time.Millisecond * 100}
+	_ = c.ConsumeBatch(ctx, []string{"test"}, cfg, func(ctx context.Context, msgs []*Message) error {
+		var batch []string
+		for _, msg := range msgs {
+			batch = append(batch, string(msg.Value))
+		}
+		got = append(got, batch)
+		// 第二批第一次失败，整批重新处理
+		if batch[0] == "2" && !failed {
+			failed = true
+			return errors.New("mock handler error")
+		}
+		if batch[len(batch)-1] == "4" {
+			cancel()
+		}
+		return nil
+	})
+	// 最后一批凑不够两条，等到 Interval 也要处理
+	assert.Equal(t, [][]string{{"0", "1"}, {"2", "3"}, {"2", "3"}, {"4"}}, got)
+
+	// 位移已经提交了，同一个组再消费不会重复拿到
+	err = p.Produce(context.Background(), &Message{Topic: "test", Value: []byte("5")})
+	require.NoError(t, err)
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	got = nil
+	_ = c.ConsumeBatch(ctx, []string{"test"}, cfg, func(ctx context.Context, msgs []*Message) error {
+		for _, msg := range msgs {
+			got = append(got, []string{string(msg.Value)})
+		}
+		cancel()
+		return nil
+	})
+	assert.Equal(t, [][]string{{"5"}}, got)
+}
@@ -0,0 +1,189 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+)
+
+const (
+	// HeaderOriginTopic 进了重试 topic 的消息，原来的 topic
+	HeaderOriginTopic = "origin-topic"
+	// HeaderRetryAt 到了这个时间才能重试，毫秒时间戳
+	HeaderRetryAt = "retry-at"
+)
+
+// DeadLetter 所有重试都失败了的消息，需要人工处理之后再重放
+type DeadLetter struct {
+	// Group 失败的消费者组，重放的时候只有这个组会再消费一次
+	Group string
+	// Topic 原始的 topic
+	Topic string
+	Key   []byte
+	Value []byte
+	Err   string
+}
+
+// DeadLetterStore 保存死信，返回 error 会一直重试
+type DeadLetterStore interface {
+	Save(ctx context.Context, dl DeadLetter) error
+}
+
+// RetryTopic 消费者组第 stage 级的重试 topic
+func RetryTopic(group string, stage int) string {
+	return fmt.Sprintf("%s.retry.%d", group, stage)
+}
+
+// ReplayTopic 消费者组的重放 topic，重放的消息当作新消息处理
+func ReplayTopic(group string) string {
+	return group + ".replay"
+}
+
+// Replay 把死信重新投递给原来的消费者组
+func Replay(ctx context.Context, producer Producer, dl DeadLetter) error {
+	return producer.Produce(ctx, &Message{
+		Topic: ReplayTopic(dl.Group),
+		Key:   dl.Key,
+		Value: dl.Value,
+		Headers: map[string]string{
+			HeaderOriginTopic: dl.Topic,
+		},
+	})
+}
+
+var _ Consumer = (*RetryConsumer)(nil)
+
+// RetryConsumer 处理失败的消息不阻塞分区，而是依次进入各级重试 topic，
+// 延迟之后再处理，最后一级也失败了就进死信。
+// 重试 topic 是按照消费者组划分的，一个组只能有一个 RetryConsumer
+type RetryConsumer struct {
+	consumer Consumer
+	producer Producer
+	store    DeadLetterStore
+	group    string
+	// stages 每一级重试的延迟
+	stages []time.Duration
+	// retryTopics value 是这个 topic 里的消息已经重试到了第几级，重放的是 -1
+	retryTopics map[string]int
+}
+
+func NewRetryConsumer(consumer Consumer, producer Producer, store DeadLetterStore,
+	group string, stages []time.Duration) *RetryConsumer {
+	retryTopics := make(map[string]int, len(stages)+1)
+	retryTopics[ReplayTopic(group)] = -1
+	for i := range stages {
+		retryTopics[RetryTopic(group, i)] = i
+	}
+	return &RetryConsumer{
+		consumer:    consumer,
+		producer:    producer,
+		store:       store,
+		group:       group,
+		stages:      stages,
+		retryTopics: retryTopics,
+	}
+}
+
+func (c *RetryConsumer) Consume(ctx context.Context, topics []string, hdl Handler) error {
+	cfg, batchHdl := singleBatch(hdl)
+	return c.ConsumeBatch(ctx, topics, cfg, batchHdl)
+}
+
+// ConsumeBatch 一批里面有一条失败，整批都会进入下一级重试
+func (c *RetryConsumer) ConsumeBatch(ctx context.Context, topics []string, cfg BatchConfig, hdl BatchHandler) error {
+	all := make([]string, 0, len(topics)+len(c.retryTopics))
+	all = append(all, topics...)
+	for topic := range c.retryTopics {
+		all = append(all, topic)
+	}
+	return c.consumer.ConsumeBatch(ctx, all, cfg, c.wrap(hdl))
+}
+
+func (c *RetryConsumer) Close() error {
+	return c.consumer.Close()
+}
+
+func (c *RetryConsumer) wrap(hdl BatchHandler) BatchHandler {
+	return func(ctx context.Context, msgs []*Message) error {
+		stage, ok := c.retryTopics[msgs[0].Topic]
+		if !ok {
+			stage = -1
+		}
+		delivered := msgs
+		if ok {
+			// 一批都来自同一个分区，后面的消息重试时间更晚
+			if err := c.wait(ctx, msgs[len(msgs)-1]); err != nil {
+				return err
+			}
+			delivered = c.restore(msgs)
+		}
+		err := hdl(ctx, delivered)
+		if err == nil || ctx.Err() != nil {
+			// 退出了的话不提交，重新投递
+			return err
+		}
+		log.Println("处理消息失败，进入下一级重试", c.group, delivered[0].Topic, stage+1, err)
+		// 转发失败就原地重试转发，不要再调用一次 hdl
+		return handleWithRetry(ctx, func(ctx context.Context, msgs []*Message) error {
+			return c.forward(ctx, msgs, stage+1, err)
+		}, delivered)
+	}
+}
+
+func (c *RetryConsumer) wait(ctx context.Context, msg *Message) error {
+	retryAt, _ := strconv.ParseInt(msg.Headers[HeaderRetryAt], 10, 64)
+	d := time.Until(time.UnixMilli(retryAt))
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// restore 换回原来的 topic，业务看到的和第一次消费一样
+func (c *RetryConsumer) restore(msgs []*Message) []*Message {
+	res := make([]*Message, 0, len(msgs))
+	for _, msg := range msgs {
+		m := *msg
+		m.Topic = msg.Headers[HeaderOriginTopic]
+		res = append(res, &m)
+	}
+	return res
+}
+
+func (c *RetryConsumer) forward(ctx context.Context, msgs []*Message, stage int, cause error) error {
+	for _, msg := range msgs {
+		var err error
+		if stage < len(c.stages) {
+			err = c.producer.Produce(ctx, &Message{
+				Topic: RetryTopic(c.group, stage),
+				Key:   msg.Key,
+				Value: msg.Value,
+				Headers: map[string]string{
+					HeaderOriginTopic: msg.Topic,
+					HeaderRetryAt:     strconv.FormatInt(time.Now().Add(c.stages[stage]).UnixMilli(), 10),
+				},
+			})
+		} else {
+			err = c.store.Save(ctx, DeadLetter{
+				Group: c.group,
+				Topic: msg.Topic,
+				Key:   msg.Key,
+				Value: msg.Value,
+				Err:   cause.Error(),
+			})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
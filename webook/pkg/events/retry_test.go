@@ -0,0 +1,97 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryDeadLetterStore struct {
+	mu  sync.Mutex
+	dls []DeadLetter
+}
+
+func (s *memoryDeadLetterStore) Save(ctx context.Context, dl DeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dls = append(s.dls, dl)
+	return nil
+}
+
+func (s *memoryDeadLetterStore) get() []DeadLetter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]DeadLetter(nil), s.dls...)
+}
+
+func TestRetryConsumer(t *testing.T) {
+	b := NewMemoryBroker(2)
+	p, err := b.Producer()
+	require.NoError(t, err)
+	c, err := b.Consumer("g1")
+	require.NoError(t, err)
+	store := &memoryDeadLetterStore{}
+	rc := NewRetryConsumer(c, p, store, "g1", []time.Duration{time.Millisecond * 10, time.Millisecond * 20})
+
+	var (
+		mu       sync.Mutex
+		attempts = make(map[string][]string)
+		fixed    bool
+		done     = make(chan struct{}, 2)
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	go func() {
+		_ = rc.Consume(ctx, []string{"test"}, func(ctx context.Context, msg *Message) error {
+			mu.Lock()
+			defer mu.Unlock()
+			val := string(msg.Value)
+			// 业务看到的永远是原始 topic
+			attempts[val] = append(attempts[val], msg.Topic)
+			switch {
+			case val == "ok-on-retry" && len(attempts[val]) == 2:
+				done <- struct{}{}
+				return nil
+			case val == "always-fail" && fixed:
+				done <- struct{}{}
+				return nil
+			}
+			return errors.New("mock handler error")
+		})
+	}()
+
+	for _, val := range []string{"ok-on-retry", "always-fail"} {
+		err = p.Produce(ctx, &Message{Topic: "test", Key: []byte(val), Value: []byte(val)})
+		require.NoError(t, err)
+	}
+	<-done
+	// 第一次加上两级重试，一共三次，然后进死信
+	require.Eventually(t, func() bool {
+		return len(store.get()) == 1
+	}, time.Second*3, time.Millisecond*10)
+	dl := store.get()[0]
+	assert.Equal(t, DeadLetter{
+		Group: "g1",
+		Topic: "test",
+		Key:   []byte("always-fail"),
+		Value: []byte("always-fail"),
+		Err:   "mock handler error",
+	}, dl)
+
+	// 修好了之后重放
+	mu.Lock()
+	fixed = true
+	mu.Unlock()
+	require.NoError(t, Replay(ctx, p, dl))
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"test", "test"}, attempts["ok-on-retry"])
+	assert.Equal(t, []string{"test", "test", "test", "test"}, attempts["always-fail"])
+}
@@ -0,0 +1,151 @@
+// Package events 消息队列的抽象，语义和 Kafka 保持一致：
+// topic 分成多个分区，同一个 key 的消息进同一个分区，分区内有序；
+// 同一个消费者组里面每个分区只会分给一个消费者，不同的消费者组各自消费全量消息；
+// handler 返回 nil 之后才提交位移，所以是至少一次，handler 要自己保证幂等。
+package events
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Message 一条消息，Partition 和 Offset 发送成功之后才有
+type Message struct {
+	Topic string
+	Key   []byte
+	Value []byte
+	// Headers 附加信息，比如重试的时候记录原始 topic
+	Headers   map[string]string
+	Partition int32
+	Offset    int64
+}
+
+type Producer interface {
+	// Produce 同步发送，返回 nil 说明消息已经写进去了
+	Produce(ctx context.Context, msg *Message) error
+	Close() error
+}
+
+// Handler 处理一条消息，返回 error 会一直重试，直到成功或者消费者退出
+type Handler func(ctx context.Context, msg *Message) error
+
+// BatchHandler 处理一批消息，返回 error 整批重试
+type BatchHandler func(ctx context.Context, msgs []*Message) error
+
+// BatchConfig 攒够 Size 条或者等了 Interval 就处理一批，先到哪个算哪个。
+// 批次按照分区来攒，一批里面的消息都是同一个分区的
+type BatchConfig struct {
+	Size     int
+	Interval time.Duration
+}
+
+type Consumer interface {
+	// Consume 阻塞消费，直到 ctx 被取消或者 Close
+	Consume(ctx context.Context, topics []string, hdl Handler) error
+	// ConsumeBatch 和 Consume 一样，只是整批处理成功之后才提交位移
+	ConsumeBatch(ctx context.Context, topics []string, cfg BatchConfig, hdl BatchHandler) error
+	Close() error
+}
+
+// Broker 创建生产者和消费者，本地用内存实现，线上用 Kafka
+type Broker interface {
+	Producer() (Producer, error)
+	// Consumer group 是消费者组的名字
+	Consumer(group string) (Consumer, error)
+}
+
+const (
+	minRetryInterval = time.Millisecond * 100
+	maxRetryInterval = time.Second * 10
+	// defaultBatchInterval 没有配置 Interval 的时候用
+	defaultBatchInterval = time.Second
+)
+
+// singleBatch 单条消费就是每批一条
+func singleBatch(hdl Handler) (BatchConfig, BatchHandler) {
+	return BatchConfig{Size: 1}, func(ctx context.Context, msgs []*Message) error {
+		return hdl(ctx, msgs[0])
+	}
+}
+
+// consumeBatches 从 msgs 里面攒批处理，处理成功之后用这一批的最后一条提交位移。
+// msgs 被关掉或者 ctx 被取消就返回，没处理的消息会重新投递
+func consumeBatches(ctx context.Context, msgs <-chan *Message, cfg BatchConfig,
+	hdl BatchHandler, commit func(last *Message)) {
+	if cfg.Size <= 0 {
+		cfg.Size = 1
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultBatchInterval
+	}
+	for {
+		batch, ok := collectBatch(ctx, msgs, cfg)
+		if len(batch) > 0 {
+			if err := handleWithRetry(ctx, hdl, batch); err != nil {
+				return
+			}
+			commit(batch[len(batch)-1])
+		}
+		if !ok {
+			return
+		}
+	}
+}
+
+// collectBatch 返回 false 说明不会再有消息了
+func collectBatch(ctx context.Context, msgs <-chan *Message, cfg BatchConfig) ([]*Message, bool) {
+	batch := make([]*Message, 0, cfg.Size)
+	// 第一条消息到了才开始计时，不然空闲的时候会一直处理空批次
+	select {
+	case msg, ok := <-msgs:
+		if !ok {
+			return nil, false
+		}
+		batch = append(batch, msg)
+	case <-ctx.Done():
+		return nil, false
+	}
+	if len(batch) >= cfg.Size {
+		return batch, true
+	}
+	timer := time.NewTimer(cfg.Interval)
+	defer timer.Stop()
+	for len(batch) < cfg.Size {
+		select {
+		case msg, ok := <-msgs:
+			if !ok {
+				return batch, false
+			}
+			batch = append(batch, msg)
+		case <-timer.C:
+			return batch, true
+		case <-ctx.Done():
+			// 处理不了了，交给下一个拿到这个分区的消费者
+			return nil, false
+		}
+	}
+	return batch, true
+}
+
+// handleWithRetry 失败了按照指数退避重试，不能跳过，不然就丢消息了
+func handleWithRetry(ctx context.Context, hdl BatchHandler, msgs []*Message) error {
+	interval := minRetryInterval
+	for {
+		err := hdl(ctx, msgs)
+		if err == nil {
+			return nil
+		}
+		first := msgs[0]
+		log.Println("处理消息失败", first.Topic, first.Partition, first.Offset, len(msgs), err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+		interval *= 2
+		if interval > maxRetryInterval {
+			interval = maxRetryInterval
+		}
+	}
+}
@@ -3,6 +3,9 @@
 package main
 
 import (
+	"dream/webook/internal/events/article"
+	"dream/webook/internal/events/outbox"
+	"dream/webook/internal/job"
 	"dream/webook/internal/repository"
 	"dream/webook/internal/repository/cache"
 	"dream/webook/internal/repository/dao"
@@ -11,34 +14,109 @@ import (
 	ijwt "dream/webook/internal/web/jwt"
 	"dream/webook/ioc"
 
-	"github.com/gin-gonic/gin"
 	"github.com/google/wire"
 )
 
-func initWebServer() *gin.Engine {
+func initApp() *App {
 	wire.Build(
 		ioc.InitDB, ioc.InitRedis,
 		dao.NewUserDAO,
+		dao.NewArticleDAO,
+		dao.NewArticleRevisionDAO,
+		dao.NewInteractiveDAO,
+		dao.NewCollectionDAO,
+		dao.NewJobDAO,
+		dao.NewRewardDAO,
+		dao.NewReconciliationDAO,
+		dao.NewAccountDAO,
+		dao.NewCommentDAO,
+		dao.NewFollowDAO,
+		dao.NewFeedDAO,
+		dao.NewOutboxDAO,
+		dao.NewDeadLetterDAO,
+		dao.NewAsyncSMSDAO,
 
 		cache.NewUserCache,
 		cache.NewCodeCache,
+		cache.NewInteractiveCache,
+		cache.NewRankingCache,
+		cache.NewRankingLocalCache,
+		cache.NewRewardCache,
+		cache.NewFollowCache,
 
 		repository.NewCodeRepository,
 		repository.NewUserRepository,
+		repository.NewArticleRepository,
+		repository.NewArticleRevisionRepository,
+		repository.NewInteractiveRepository,
+		repository.NewCollectionRepository,
+		repository.NewRankingRepository,
+		repository.NewJobRepository,
+		repository.NewRewardRepository,
+		repository.NewReconciliationRepository,
+		repository.NewAccountRepository,
+		repository.NewCommentRepository,
+		repository.NewFollowRepository,
+		repository.NewFeedRepository,
+		repository.NewOutboxRepository,
+		repository.NewDeadLetterRepository,
+		repository.NewAsyncSMSRepository,
 
 		service.NewCodeService,
 		service.NewUserService,
+		service.NewArticleService,
+		service.NewArticleRevisionService,
+		service.NewInteractiveService,
+		service.NewCollectionService,
+		service.NewRankingService,
+		service.NewJobService,
+		service.NewRewardService,
+		service.NewReconciliationService,
+		service.NewAccountService,
+		service.NewCommentService,
+		service.NewFollowService,
+		service.NewFeedService,
+		service.NewDeadLetterService,
 
+		ioc.InitAsyncSMSService,
 		ioc.InitSMSService,
 		ioc.InitWechatService,
+		ioc.InitPaymentService,
 
 		web.NewUserHandler,
 		web.NewWeChatOAuth2Handler,
+		web.NewArticleHandler,
+		web.NewCollectionHandler,
+		web.NewJobHandler,
+		web.NewRewardHandler,
+		web.NewPaymentHandler,
+		web.NewReconciliationHandler,
+		web.NewAccountHandler,
+		web.NewCommentHandler,
+		web.NewFollowHandler,
+		web.NewFeedHandler,
+		web.NewDeadLetterHandler,
 
 		ijwt.NewRedisJWTHandler,
 
+		ioc.InitEventBroker,
+		ioc.InitEventProducer,
+		article.NewProducer,
+		ioc.InitReadEventConsumer,
+		ioc.InitPublishedEventConsumer,
+		ioc.InitSignupEventConsumer,
+		outbox.NewRelay,
+		ioc.InitConsumers,
+
+		job.NewRankingJob,
+		job.NewReconciliationJob,
+		ioc.InitScheduler,
+		ioc.InitJobScheduler,
+
 		ioc.InitMiddlewares,
 		ioc.InitGin,
+
+		wire.Struct(new(App), "*"),
 	)
-	return new(gin.Engine)
+	return new(App)
 }
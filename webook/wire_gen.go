@@ -7,6 +7,9 @@
 package main
 
 import (
+	"dream/webook/internal/events/article"
+	"dream/webook/internal/events/outbox"
+	"dream/webook/internal/job"
 	"dream/webook/internal/repository"
 	"dream/webook/internal/repository/cache"
 	"dream/webook/internal/repository/dao"
@@ -14,12 +17,11 @@ import (
 	"dream/webook/internal/web"
 	"dream/webook/internal/web/jwt"
 	"dream/webook/ioc"
-	"github.com/gin-gonic/gin"
 )
 
 // Injectors from wire.go:
 
-func initWebServer() *gin.Engine {
+func initApp() *App {
 	db := ioc.InitDB()
 	userDAO := dao.NewUserDAO(db)
 	cmdable := ioc.InitRedis()
@@ -28,13 +30,91 @@ func initWebServer() *gin.Engine {
 	userService := service.NewUserService(userRepository)
 	codeCache := cache.NewCodeCache(cmdable)
 	codeRepository := repository.NewCodeRepository(codeCache)
-	smsService := ioc.InitSMSService()
+	asyncSMSDAO := dao.NewAsyncSMSDAO(db)
+	asyncSMSRepository := repository.NewAsyncSMSRepository(asyncSMSDAO)
+	asyncService := ioc.InitAsyncSMSService(cmdable, asyncSMSRepository)
+	smsService := ioc.InitSMSService(asyncService)
 	codeService := service.NewCodeService(codeRepository, smsService)
-	userHandler := web.NewUserHandler(userService, codeService)
+	followDAO := dao.NewFollowDAO(db)
+	followCache := cache.NewFollowCache(cmdable)
+	followRepository := repository.NewFollowRepository(followDAO, followCache)
+	followService := service.NewFollowService(followRepository, userRepository)
+	userHandler := web.NewUserHandler(userService, codeService, followService)
 	handler := jwt.NewRedisJWTHandler(cmdable)
 	v := ioc.InitMiddlewares(cmdable, handler)
 	wechatService := ioc.InitWechatService()
 	weChatOAuth2Handler := web.NewWeChatOAuth2Handler(wechatService, userService, handler)
-	engine := ioc.InitGin(userHandler, v, weChatOAuth2Handler)
-	return engine
+	articleDAO := dao.NewArticleDAO(db)
+	articleRepository := repository.NewArticleRepository(articleDAO)
+	articleService := service.NewArticleService(articleRepository, userRepository)
+	articleRevisionDAO := dao.NewArticleRevisionDAO(db)
+	articleRevisionRepository := repository.NewArticleRevisionRepository(articleRevisionDAO)
+	articleRevisionService := service.NewArticleRevisionService(articleRevisionRepository, articleRepository)
+	interactiveDAO := dao.NewInteractiveDAO(db)
+	interactiveCache := cache.NewInteractiveCache(cmdable)
+	interactiveRepository := repository.NewInteractiveRepository(interactiveDAO, interactiveCache)
+	collectionDAO := dao.NewCollectionDAO(db)
+	collectionRepository := repository.NewCollectionRepository(collectionDAO, interactiveRepository)
+	interactiveService := service.NewInteractiveService(interactiveRepository, collectionRepository, articleRepository)
+	rankingCache := cache.NewRankingCache(cmdable)
+	rankingLocalCache := cache.NewRankingLocalCache()
+	rankingRepository := repository.NewRankingRepository(rankingCache, rankingLocalCache, articleRepository)
+	rankingService := service.NewRankingService(articleRepository, interactiveRepository, rankingRepository)
+	rewardDAO := dao.NewRewardDAO(db)
+	rewardCache := cache.NewRewardCache(cmdable)
+	rewardRepository := repository.NewRewardRepository(rewardDAO, rewardCache)
+	paymentService := ioc.InitPaymentService()
+	accountDAO := dao.NewAccountDAO(db)
+	accountRepository := repository.NewAccountRepository(accountDAO)
+	accountService := service.NewAccountService(accountRepository)
+	rewardService := service.NewRewardService(rewardRepository, paymentService, accountService)
+	broker := ioc.InitEventBroker()
+	producer := ioc.InitEventProducer(broker)
+	articleProducer := article.NewProducer(producer)
+	articleHandler := web.NewArticleHandler(articleService, articleRevisionService, interactiveService, rankingService, rewardService, articleProducer)
+	collectionService := service.NewCollectionService(collectionRepository, articleRepository, interactiveRepository)
+	collectionHandler := web.NewCollectionHandler(collectionService)
+	jobDAO := dao.NewJobDAO(db)
+	jobRepository := repository.NewJobRepository(jobDAO)
+	jobService := service.NewJobService(jobRepository)
+	jobHandler := web.NewJobHandler(jobService)
+	rewardHandler := web.NewRewardHandler(rewardService)
+	paymentHandler := web.NewPaymentHandler(paymentService, rewardService)
+	reconciliationDAO := dao.NewReconciliationDAO(db)
+	reconciliationRepository := repository.NewReconciliationRepository(reconciliationDAO)
+	reconciliationService := service.NewReconciliationService(reconciliationRepository, rewardRepository, rewardService, paymentService)
+	reconciliationHandler := web.NewReconciliationHandler(reconciliationService)
+	accountHandler := web.NewAccountHandler(accountService)
+	commentDAO := dao.NewCommentDAO(db)
+	commentRepository := repository.NewCommentRepository(commentDAO)
+	commentService := service.NewCommentService(commentRepository, articleRepository)
+	commentHandler := web.NewCommentHandler(commentService)
+	followHandler := web.NewFollowHandler(followService)
+	feedDAO := dao.NewFeedDAO(db)
+	feedRepository := repository.NewFeedRepository(feedDAO)
+	feedService := service.NewFeedService(feedRepository, followRepository, articleRepository)
+	feedHandler := web.NewFeedHandler(feedService)
+	deadLetterDAO := dao.NewDeadLetterDAO(db)
+	deadLetterRepository := repository.NewDeadLetterRepository(deadLetterDAO)
+	deadLetterService := service.NewDeadLetterService(deadLetterRepository, producer)
+	deadLetterHandler := web.NewDeadLetterHandler(deadLetterService)
+	engine := ioc.InitGin(userHandler, v, weChatOAuth2Handler, articleHandler, collectionHandler, jobHandler, rewardHandler, paymentHandler, reconciliationHandler, accountHandler, commentHandler, followHandler, feedHandler, deadLetterHandler)
+	rankingJob := job.NewRankingJob(rankingService)
+	scheduler := ioc.InitScheduler(cmdable, db, rankingJob)
+	reconciliationJob := job.NewReconciliationJob(reconciliationService)
+	jobScheduler := ioc.InitJobScheduler(jobService, reconciliationJob)
+	readEventConsumer := ioc.InitReadEventConsumer(broker, producer, deadLetterService, interactiveService, rankingService)
+	publishedEventConsumer := ioc.InitPublishedEventConsumer(broker, producer, deadLetterService, feedService)
+	signupEventConsumer := ioc.InitSignupEventConsumer(broker, producer, deadLetterService, collectionService)
+	outboxDAO := dao.NewOutboxDAO(db)
+	outboxRepository := repository.NewOutboxRepository(outboxDAO)
+	relay := outbox.NewRelay(outboxRepository, producer)
+	v2 := ioc.InitConsumers(readEventConsumer, publishedEventConsumer, signupEventConsumer, relay, asyncService)
+	app := &App{
+		server:       engine,
+		scheduler:    scheduler,
+		jobScheduler: jobScheduler,
+		consumers:    v2,
+	}
+	return app
 }